@@ -2,8 +2,15 @@ package tdms
 
 import (
 	"encoding/binary"
+	"fmt"
+	"io"
 	"iter"
+	"maps"
+	"math/cmplx"
+	"slices"
+	"sync"
 	"time"
+	"unsafe"
 )
 
 // Channel represents a data channel within a [Group]. Use the ReadData methods
@@ -15,18 +22,49 @@ type Channel struct {
 	// GroupName is the name of the group that contains this channel.
 	GroupName string
 
-	// DataType is the type of data stored in this channel.
+	// DataType is the type of data stored in this channel. The "with unit"
+	// float types are reported as the plain float type, use RawTypeCode to
+	// distinguish them.
 	DataType DataType
 
-	// Properties contains all properties associated with this channel.
+	// Properties contains all properties associated with this channel. This
+	// map belongs to the Channel, so changing it does not affect any other
+	// object in the file.
 	Properties map[string]Property
 
 	f              *File
 	path           string
-	dataChunks     []dataChunk
+	rawTypeCode    uint32
+	lazyChunks     *lazyDataChunks
 	totalNumValues uint64
 }
 
+// lazyDataChunks holds the data chunks of a channel, computing them the first
+// time they're needed. It is shared between copies of a Channel so that the
+// chunks are only computed once.
+type lazyDataChunks struct {
+	once   sync.Once
+	build  func() []dataChunk
+	chunks []dataChunk
+}
+
+// dataChunks returns the position and metadata of each raw data chunk of this
+// channel, computing them if this is the first time they've been needed.
+func (ch *Channel) dataChunks() []dataChunk {
+	if ch.lazyChunks == nil {
+		return nil
+	}
+
+	ch.lazyChunks.once.Do(func() {
+		if ch.lazyChunks.build != nil {
+			ch.lazyChunks.chunks = ch.lazyChunks.build()
+			ch.lazyChunks.build = nil
+		}
+	})
+
+	return ch.lazyChunks.chunks
+}
+
 // dataChunk is similar to objectIndex, but is a single object index can
 // correspond to multiple chunks whereas a single dataChunk instance corresponds
 // to a single raw data chunk in the TDMS file.
@@ -45,6 +83,13 @@ type dataChunk struct {
 	size          uint64
 	numValues     uint64
 	stride        int64
+
+	// segmentIndex is the index of the segment that this chunk belongs to.
+	segmentIndex int
+
+	// daqmx is the raw data index of the object if it holds DAQmx raw data,
+	// which gives the layout of the values in the chunk.
+	daqmx *objectIndex
 }
 
 // Group returns the [Group] that this channel belongs to.
@@ -52,129 +97,502 @@ func (ch *Channel) Group() Group {
 	return ch.f.Groups[ch.GroupName]
 }
 
+// Property returns the property of this channel with the given name, and
+// whether it exists.
+func (ch *Channel) Property(name string) (Property, bool) {
+	prop, ok := ch.Properties[name]
+	return prop, ok
+}
+
+// GetTyped looks up the property with the given name, returning a
+// [PropertyResult] with typed getters which report whether the property is
+// present with the expected type, e.g.
+//
+//	if gain, ok := ch.GetTyped("gain").AsFloat64(); ok {
+//		// ...
+//	}
+func (ch *Channel) GetTyped(name string) PropertyResult {
+	prop, ok := ch.Properties[name]
+	return PropertyResult{prop: prop, present: ok}
+}
+
+// RawTypeCode returns the type code of the channel data exactly as it is stored
+// in the file. This differs from DataType only for the "with unit" float types,
+// e.g. a channel stored as [DataTypeFloat64WithUnit] has a DataType of
+// [DataTypeFloat64].
+func (ch *Channel) RawTypeCode() uint32 {
+	return ch.rawTypeCode
+}
+
+// DataTypeConsistent returns whether the data type of this channel is the same
+// in every segment which has a raw data index for it, along with each distinct
+// data type in the order in which they first appear. The TDMS format forbids
+// the data type of a channel from changing, but some buggy writers do it, in
+// which case DataType is the type in the last segment and the data can't be
+// read correctly.
+func (ch *Channel) DataTypeConsistent() (bool, []DataType) {
+	var dataTypes []DataType
+
+	for _, segment := range ch.f.segments {
+		obj, ok := segment.metadata.objects[ch.path]
+		if !ok || obj.index == nil {
+			continue
+		}
+
+		if !slices.Contains(dataTypes, obj.index.dataType) {
+			dataTypes = append(dataTypes, obj.index.dataType)
+		}
+	}
+
+	return len(dataTypes) <= 1, dataTypes
+}
+
+// Unit returns the unit of this channel's data from its "unit_string"
+// property, and whether the channel has one. This is where the unit of the
+// "with unit" float types is stored, but any channel may have a unit.
+func (ch *Channel) Unit() (string, bool) {
+	prop, ok := ch.Properties["unit_string"]
+	if !ok {
+		return "", false
+	}
+
+	unit, err := prop.AsString()
+	if err != nil {
+		return "", false
+	}
+
+	return unit, true
+}
+
 // NumValues returns the total number of data values in this channel across all
 // segments.
 func (ch *Channel) NumValues() uint64 {
 	return ch.totalNumValues
 }
 
+// CompleteValueCount returns the number of values of this channel which were
+// completely written and can be read. For an incomplete or truncated file, this
+// is the sample at which the channel was cut off, and [Channel.LostValueCount]
+// gives the number of values after it which are missing. This is always the
+// same as [Channel.NumValues], but is provided to make the intent clear when
+// investigating truncated files alongside [File.CompleteDataBoundary].
+func (ch *Channel) CompleteValueCount() uint64 {
+	return ch.totalNumValues
+}
+
+// LostValueCount returns the number of values of this channel which the raw
+// data index declares for a final chunk that was cut off, e.g. by LabVIEW
+// crashing or the file being truncated, but which weren't completely written.
+// These values are not included in [Channel.CompleteValueCount], so the
+// channel was cut off at sample CompleteValueCount, with LostValueCount values
+// after it missing. Returns 0 if no values were lost.
+//
+// The number of chunks of an incomplete segment isn't known, so only the
+// values of the final chunk which was at least partly written are counted.
+func (ch *Channel) LostValueCount() uint64 {
+	lost := uint64(0)
+	for _, segment := range ch.f.segments {
+		if !segment.leadIn.containsRawData {
+			continue
+		}
+
+		obj, ok := segment.metadata.objects[ch.path]
+		if !ok || obj.index == nil {
+			continue
+		}
+
+		lost += segment.metadata.lostValues(obj.index, segment.leadIn.isInterleaved)
+	}
+
+	return lost
+}
+
+// FirstDataOffset returns the absolute offset in the file of the first data
+// value of this channel, and whether the channel has any data. Along with
+// [DataType.Size] and [Channel.NumValues], this allows external tools to locate
+// the data of contiguous channels directly.
+func (ch *Channel) FirstDataOffset() (int64, bool) {
+	chunks := ch.dataChunks()
+	if len(chunks) == 0 {
+		return 0, false
+	}
+
+	return chunks[0].offset, true
+}
+
+// NumChunks returns the number of raw data chunks of this channel across all
+// segments. This is always zero for a file opened with the MetadataOnly option.
+func (ch *Channel) NumChunks() int {
+	return len(ch.dataChunks())
+}
+
+// ChunkSegmentIndex returns the index of the segment containing the raw data
+// chunk with the given index, or -1 if there is no such chunk. Along with
+// [Channel.SegmentProperties], this maps each chunk of data back to the
+// properties which applied when it was written.
+func (ch *Channel) ChunkSegmentIndex(chunkIndex int) int {
+	chunks := ch.dataChunks()
+	if chunkIndex < 0 || chunkIndex >= len(chunks) {
+		return -1
+	}
+
+	return chunks[chunkIndex].segmentIndex
+}
+
+// ChunkInfo describes a single raw data chunk of a channel, as yielded by
+// [Channel.Chunks]. A chunk is the data for the channel from one write, so
+// chunks line up with acquisition bursts.
+type ChunkInfo struct {
+	// Index is the index of the chunk among all the chunks of the channel.
+	Index int
+
+	// Offset is the absolute offset in the file of the first value.
+	Offset int64
+
+	// FirstValue is the index within the whole channel of the first value in
+	// the chunk.
+	FirstValue uint64
+
+	NumValues    uint64
+	Interleaved  bool
+	ByteOrder    binary.ByteOrder
+	SegmentIndex int
+}
+
+// Chunks returns an iterator over the raw data chunks of the channel, in file
+// order. Use [Channel.ReadChunkFloat64] to read the values of a chunk. This
+// yields nothing for a file opened with the MetadataOnly option.
+func (ch *Channel) Chunks() iter.Seq[ChunkInfo] {
+	return func(yield func(ChunkInfo) bool) {
+		firstValue := uint64(0)
+		for i, chunk := range ch.dataChunks() {
+			info := ChunkInfo{
+				Index:        i,
+				Offset:       chunk.offset,
+				FirstValue:   firstValue,
+				NumValues:    chunk.numValues,
+				Interleaved:  chunk.isInterleaved,
+				ByteOrder:    chunk.order,
+				SegmentIndex: chunk.segmentIndex,
+			}
+
+			if !yield(info) {
+				return
+			}
+
+			firstValue += chunk.numValues
+		}
+	}
+}
+
+// ReadChunkFloat64 reads the float64 values of a single chunk of the channel,
+// as yielded by [Channel.Chunks]. Only the Index of the chunk is used, so a
+// ChunkInfo from a different channel must not be passed.
+//
+// Returns ErrNotFound if the channel has no chunk with the chunk's index.
+func (ch *Channel) ReadChunkFloat64(chunk ChunkInfo, options ...ReadOption) ([]float64, error) {
+	return readChunkData(ch, options, DataTypeFloat64, interpretFloat64, chunk.Index)
+}
+
+// SegmentProperties returns the properties of this channel as they were at the
+// end of the metadata of the segment with the given index, and whether the
+// channel is in that segment's object list. The properties of the segments up
+// to and including the given one are merged in the same way as for Properties,
+// but any properties written or changed in later segments are excluded.
+func (ch *Channel) SegmentProperties(segmentIndex int) (map[string]Property, bool) {
+	if segmentIndex < 0 || segmentIndex >= len(ch.f.segments) {
+		return nil, false
+	}
+
+	if _, ok := ch.f.segments[segmentIndex].metadata.objects[ch.path]; !ok {
+		return nil, false
+	}
+
+	// A segment with a new object list only has the properties which were
+	// written in it, so earlier segments have to be merged in too.
+	props := make(map[string]Property)
+	for _, segment := range ch.f.segments[:segmentIndex+1] {
+		if obj, ok := segment.metadata.objects[ch.path]; ok {
+			maps.Copy(props, obj.properties)
+		}
+	}
+
+	return props, true
+}
+
+// PropertyChange is the value a property was given in a particular segment,
+// as returned by [Channel.PropertyHistory].
+type PropertyChange struct {
+	SegmentIndex int
+	Property     Property
+}
+
+// PropertyHistory returns each change to the value of the named property of
+// this channel through the file, in segment order, starting with the segment
+// where it was first set. Writing the same value again in a later segment
+// isn't counted as a change. The last change gives the value in Properties.
+// Returns nil if the property is never set.
+func (ch *Channel) PropertyHistory(name string) []PropertyChange {
+	var history []PropertyChange
+	for i, segment := range ch.f.segments {
+		obj, ok := segment.metadata.objects[ch.path]
+		if !ok {
+			continue
+		}
+
+		prop, ok := obj.properties[name]
+		if !ok {
+			continue
+		}
+
+		if len(history) > 0 {
+			last := history[len(history)-1].Property
+			if last.TypeCode == prop.TypeCode && last.Value == prop.Value {
+				continue
+			}
+		}
+
+		history = append(history, PropertyChange{SegmentIndex: i, Property: prop})
+	}
+
+	return history
+}
+
+// EstimatedReadBytes returns an estimate of the memory in bytes needed to read
+// all of this channel's data into a single slice with one of the ReadData*All
+// methods, so that reads which won't fit in memory can be rejected or streamed
+// instead. For fixed-size data types, this is the number of values multiplied
+// by the size of a value. For strings, it is the total length of the strings
+// plus a string header for each value. It is zero for channels whose data
+// can't be read.
+func (ch *Channel) EstimatedReadBytes() uint64 {
+	switch {
+	case ch.DataType == DataTypeString:
+		chunks := slices.Values(ch.dataChunks())
+		if ch.f.opts.metadataOnly {
+			// The chunks aren't kept for metadata only files, but their sizes
+			// can still be worked out.
+			chunks = ch.f.objectDataChunks(ch.path)
+		}
+
+		// The size of each chunk includes the offset of the end of each string.
+		stringBytes := uint64(0)
+		for chunk := range chunks {
+			stringBytes += chunk.size - 4*chunk.numValues
+		}
+
+		return stringBytes + ch.totalNumValues*uint64(unsafe.Sizeof(""))
+	default:
+		return ch.totalNumValues * uint64(ch.DataType.Size())
+	}
+}
+
+// maxExactFloat64Int is the largest integer such that it and every integer
+// below it can be represented exactly as a float64.
+const maxExactFloat64Int = 1 << 53
+
 type readOptions struct {
-	batchSize int
+	batchSize         int
+	retries           int
+	retryBackoff      time.Duration
+	warnPrecisionLoss func(channel string, value uint64)
+	byteOrder         binary.ByteOrder
+	stringDecoder     StringDecoder
+	alignment         int
 }
 
 // ReadOption configures how data is read from a [Channel].
 type ReadOption func(*readOptions)
 
 // BatchSize sets the number of values read per batch during streaming. This
-// controls the internal buffer size used by the streaming and batch readers.
+// controls the internal buffer size used by the streaming and batch readers,
+// overriding any default set with [File.SetDefaultBatchSize].
 func BatchSize(batchSize int) ReadOption {
 	return func(opts *readOptions) {
 		opts.batchSize = batchSize
 	}
 }
 
+// WarnPrecisionLoss sets a function which is called by
+// [Channel.ReadDataAsFloat64Coerced] for each uint64 value which is too large
+// to be represented exactly as a float64, i.e. is greater than 2^53. It is
+// called with the path of the channel and the original value.
+func WarnPrecisionLoss(warn func(channel string, value uint64)) ReadOption {
+	return func(opts *readOptions) {
+		opts.warnPrecisionLoss = warn
+	}
+}
+
+// ForceByteOrder interprets the raw data of the channel using the given byte
+// order, instead of the byte order given by the lead in of each segment. This
+// is an escape hatch for recovering data from files whose big endian flag was
+// set incorrectly by the writer. Only the raw data is affected, as the
+// metadata must have been read correctly for the file to be opened at all.
+func ForceByteOrder(order binary.ByteOrder) ReadOption {
+	return func(opts *readOptions) {
+		opts.byteOrder = order
+	}
+}
+
+// AlignedAlloc makes the ReadData*All methods return slices whose first
+// element is aligned to the given number of bytes, e.g. 32 or 64 for passing
+// the values straight to SIMD routines without a copy. The alignment must be a
+// power of two. The slice is cut from a slightly larger allocation, so its
+// capacity is exactly its length and appending to it moves it elsewhere.
+func AlignedAlloc(bytes int) ReadOption {
+	return func(opts *readOptions) {
+		opts.alignment = bytes
+	}
+}
+
 // Data streaming functions that yield each item at a time.
 
 // ReadDataAsInt8 returns an iterator that yields individual int8 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsInt8(options ...ReadOption) iter.Seq2[int8, error] {
-	return StreamReader(ch, options, DataTypeInt8, interpretInt8)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeInt8, interpretInt8))
 }
 
 // ReadDataAsInt16 returns an iterator that yields individual int16 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsInt16(options ...ReadOption) iter.Seq2[int16, error] {
-	return StreamReader(ch, options, DataTypeInt16, interpretInt16)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeInt16, interpretInt16))
 }
 
 // ReadDataAsInt32 returns an iterator that yields individual int32 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsInt32(options ...ReadOption) iter.Seq2[int32, error] {
-	return StreamReader(ch, options, DataTypeInt32, interpretInt32)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeInt32, interpretInt32))
 }
 
 // ReadDataAsInt64 returns an iterator that yields individual int64 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsInt64(options ...ReadOption) iter.Seq2[int64, error] {
-	return StreamReader(ch, options, DataTypeInt64, interpretInt64)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeInt64, interpretInt64))
 }
 
 // ReadDataAsUint8 returns an iterator that yields individual uint8 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsUint8(options ...ReadOption) iter.Seq2[uint8, error] {
-	return StreamReader(ch, options, DataTypeUint8, interpretUint8)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeUint8, interpretUint8))
 }
 
 // ReadDataAsUint16 returns an iterator that yields individual uint16 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsUint16(options ...ReadOption) iter.Seq2[uint16, error] {
-	return StreamReader(ch, options, DataTypeUint16, interpretUint16)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeUint16, interpretUint16))
 }
 
 // ReadDataAsUint32 returns an iterator that yields individual uint32 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsUint32(options ...ReadOption) iter.Seq2[uint32, error] {
-	return StreamReader(ch, options, DataTypeUint32, interpretUint32)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeUint32, interpretUint32))
 }
 
 // ReadDataAsUint64 returns an iterator that yields individual uint64 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsUint64(options ...ReadOption) iter.Seq2[uint64, error] {
-	return StreamReader(ch, options, DataTypeUint64, interpretUint64)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeUint64, interpretUint64))
 }
 
 // ReadDataAsFloat32 returns an iterator that yields individual float32 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsFloat32(options ...ReadOption) iter.Seq2[float32, error] {
-	return StreamReader(ch, options, DataTypeFloat32, interpretFloat32)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeFloat32, interpretFloat32))
 }
 
 // ReadDataAsFloat64 returns an iterator that yields individual float64 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsFloat64(options ...ReadOption) iter.Seq2[float64, error] {
-	return StreamReader(ch, options, DataTypeFloat64, interpretFloat64)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeFloat64, interpretFloat64))
 }
 
 // ReadDataAsFloat128 returns an iterator that yields individual [Float128] values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsFloat128(options ...ReadOption) iter.Seq2[Float128, error] {
-	return StreamReader(ch, options, DataTypeFloat128, interpretFloat128)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeFloat128, interpretFloat128))
+}
+
+// ReadDataAsFloat128AsFloat64 returns an iterator that yields individual
+// [Float128] values from the channel converted to float64, losing precision.
+// This is useful for plotting or other uses where the full precision is not
+// needed. Use BatchSize option to control internal buffer size.
+func (ch *Channel) ReadDataAsFloat128AsFloat64(options ...ReadOption) iter.Seq2[float64, error] {
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeFloat128, interpretFloat128AsFloat64))
 }
 
 // ReadDataAsString returns an iterator that yields individual string values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsString(options ...ReadOption) iter.Seq2[string, error] {
-	return StreamReader(ch, options, DataTypeString, interpretString)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeString, interpretString))
 }
 
 // ReadDataAsBool returns an iterator that yields individual bool values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsBool(options ...ReadOption) iter.Seq2[bool, error] {
-	return StreamReader(ch, options, DataTypeBool, interpretBool)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeBool, interpretBool))
 }
 
 // ReadDataAsTimestamp returns an iterator that yields individual [Timestamp] values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsTimestamp(options ...ReadOption) iter.Seq2[Timestamp, error] {
-	return StreamReader(ch, options, DataTypeTimestamp, interpretTimestamp)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeTimestamp, interpretTimestamp))
 }
 
 // ReadDataAsTime returns an iterator that yields individual [time.Time] values from the channel.
-// Timestamps are automatically converted from TDMS format. Use BatchSize option to control internal buffer size.
+// Timestamps are automatically converted from TDMS format to UTC. Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsTime(options ...ReadOption) iter.Seq2[time.Time, error] {
-	return StreamReader(ch, options, DataTypeTimestamp, interpretTime)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeTimestamp, interpretTime))
+}
+
+// ReadDataAsTimeIn returns an iterator that yields individual [time.Time] values from the channel
+// in the given location, e.g. [time.Local]. A nil location is treated as UTC. Only the location
+// used to present each time differs from [Channel.ReadDataAsTime], not the instant it represents.
+func (ch *Channel) ReadDataAsTimeIn(loc *time.Location, options ...ReadOption) iter.Seq2[time.Time, error] {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	interpret := func(bytes []byte, order binary.ByteOrder) time.Time {
+		return interpretTime(bytes, order).In(loc)
+	}
+
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeTimestamp, interpret))
 }
 
 // ReadDataAsComplex64 returns an iterator that yields individual complex64 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsComplex64(options ...ReadOption) iter.Seq2[complex64, error] {
-	return StreamReader(ch, options, DataTypeComplex64, interpretComplex64)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeComplex64, interpretComplex64))
 }
 
 // ReadDataAsComplex128 returns an iterator that yields individual complex128 values from the channel.
 // Use BatchSize option to control internal buffer size.
 func (ch *Channel) ReadDataAsComplex128(options ...ReadOption) iter.Seq2[complex128, error] {
-	return StreamReader(ch, options, DataTypeComplex128, interpretComplex128)
+	return unbatch(nativeBatchStreamReader(ch, options, DataTypeComplex128, interpretComplex128))
+}
+
+// ReadDataFloat64Filter returns an iterator that yields only the float64 values
+// from the channel for which pred returns true. Use BatchSize option to control
+// internal buffer size.
+func (ch *Channel) ReadDataFloat64Filter(pred func(float64) bool, options ...ReadOption) iter.Seq2[float64, error] {
+	return filterStreamReader(ch, options, DataTypeFloat64, interpretFloat64, pred)
+}
+
+// ReadDataFloat64Map returns an iterator that yields f applied to each float64
+// value from the channel, e.g. for unit conversions or removing an offset as
+// the values are streamed. Use BatchSize option to control internal buffer
+// size.
+func (ch *Channel) ReadDataFloat64Map(f func(float64) float64, options ...ReadOption) iter.Seq2[float64, error] {
+	return mapStreamReader(ch, options, DataTypeFloat64, interpretFloat64, f)
+}
+
+// ReduceFloat64 folds every float64 value of the channel into an accumulator,
+// starting with init and calling f with the accumulator and each value in turn,
+// e.g. to compute a sum or a norm without reading all the data into memory.
+// See [Reduce] for other data types.
+func (ch *Channel) ReduceFloat64(init float64, f func(acc, value float64) float64, options ...ReadOption) (float64, error) {
+	return reduce(nativeBatchStreamReader(ch, options, DataTypeFloat64, interpretFloat64), init, f)
 }
 
 // Data streaming functions that yield items in batches.
@@ -182,106 +600,108 @@ func (ch *Channel) ReadDataAsComplex128(options ...ReadOption) iter.Seq2[complex
 // ReadDataAsInt8Batch returns an iterator that yields batches of int8 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsInt8Batch(options ...ReadOption) iter.Seq2[[]int8, error] {
-	return BatchStreamReader(ch, options, DataTypeInt8, interpretInt8)
+	return nativeBatchStreamReader(ch, options, DataTypeInt8, interpretInt8)
 }
 
 // ReadDataAsInt16Batch returns an iterator that yields batches of int16 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsInt16Batch(options ...ReadOption) iter.Seq2[[]int16, error] {
-	return BatchStreamReader(ch, options, DataTypeInt16, interpretInt16)
+	return nativeBatchStreamReader(ch, options, DataTypeInt16, interpretInt16)
 }
 
 // ReadDataAsInt32Batch returns an iterator that yields batches of int32 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsInt32Batch(options ...ReadOption) iter.Seq2[[]int32, error] {
-	return BatchStreamReader(ch, options, DataTypeInt32, interpretInt32)
+	return nativeBatchStreamReader(ch, options, DataTypeInt32, interpretInt32)
 }
 
 // ReadDataAsInt64Batch returns an iterator that yields batches of int64 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsInt64Batch(options ...ReadOption) iter.Seq2[[]int64, error] {
-	return BatchStreamReader(ch, options, DataTypeInt64, interpretInt64)
+	return nativeBatchStreamReader(ch, options, DataTypeInt64, interpretInt64)
 }
 
 // ReadDataAsUint8Batch returns an iterator that yields batches of uint8 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsUint8Batch(options ...ReadOption) iter.Seq2[[]uint8, error] {
-	return BatchStreamReader(ch, options, DataTypeUint8, interpretUint8)
+	return nativeBatchStreamReader(ch, options, DataTypeUint8, interpretUint8)
 }
 
 // ReadDataAsUint16Batch returns an iterator that yields batches of uint16 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsUint16Batch(options ...ReadOption) iter.Seq2[[]uint16, error] {
-	return BatchStreamReader(ch, options, DataTypeUint16, interpretUint16)
+	return nativeBatchStreamReader(ch, options, DataTypeUint16, interpretUint16)
 }
 
 // ReadDataAsUint32Batch returns an iterator that yields batches of uint32 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsUint32Batch(options ...ReadOption) iter.Seq2[[]uint32, error] {
-	return BatchStreamReader(ch, options, DataTypeUint32, interpretUint32)
+	return nativeBatchStreamReader(ch, options, DataTypeUint32, interpretUint32)
 }
 
 // ReadDataAsUint64Batch returns an iterator that yields batches of uint64 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsUint64Batch(options ...ReadOption) iter.Seq2[[]uint64, error] {
-	return BatchStreamReader(ch, options, DataTypeUint64, interpretUint64)
+	return nativeBatchStreamReader(ch, options, DataTypeUint64, interpretUint64)
 }
 
 // ReadDataAsFloat32Batch returns an iterator that yields batches of float32 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsFloat32Batch(options ...ReadOption) iter.Seq2[[]float32, error] {
-	return BatchStreamReader(ch, options, DataTypeFloat32, interpretFloat32)
+	return nativeBatchStreamReader(ch, options, DataTypeFloat32, interpretFloat32)
 }
 
 // ReadDataAsFloat64Batch returns an iterator that yields batches of float64 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsFloat64Batch(options ...ReadOption) iter.Seq2[[]float64, error] {
-	return BatchStreamReader(ch, options, DataTypeFloat64, interpretFloat64)
+	return nativeBatchStreamReader(ch, options, DataTypeFloat64, interpretFloat64)
 }
 
 // ReadDataAsFloat128Batch returns an iterator that yields batches of [Float128] values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsFloat128Batch(options ...ReadOption) iter.Seq2[[]Float128, error] {
-	return BatchStreamReader(ch, options, DataTypeFloat128, interpretFloat128)
+	return nativeBatchStreamReader(ch, options, DataTypeFloat128, interpretFloat128)
 }
 
 // ReadDataAsStringBatch returns an iterator that yields batches of string values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsStringBatch(options ...ReadOption) iter.Seq2[[]string, error] {
-	return BatchStreamReader(ch, options, DataTypeString, interpretString)
+	return nativeBatchStreamReader(ch, options, DataTypeString, interpretString)
 }
 
 // ReadDataAsBoolBatch returns an iterator that yields batches of bool values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsBoolBatch(options ...ReadOption) iter.Seq2[[]bool, error] {
-	return BatchStreamReader(ch, options, DataTypeBool, interpretBool)
+	return nativeBatchStreamReader(ch, options, DataTypeBool, interpretBool)
 }
 
 // ReadDataAsTimestampBatch returns an iterator that yields batches of [Timestamp] values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsTimestampBatch(options ...ReadOption) iter.Seq2[[]Timestamp, error] {
-	return BatchStreamReader(ch, options, DataTypeTimestamp, interpretTimestamp)
+	return nativeBatchStreamReader(ch, options, DataTypeTimestamp, interpretTimestamp)
 }
 
 // ReadDataAsTimeBatch returns an iterator that yields batches of [time.Time] values from the channel.
 // Timestamps are automatically converted from TDMS format. Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsTimeBatch(options ...ReadOption) iter.Seq2[[]time.Time, error] {
-	return BatchStreamReader(ch, options, DataTypeTimestamp, interpretTime)
+	return nativeBatchStreamReader(ch, options, DataTypeTimestamp, interpretTime)
 }
 
 // ReadDataAsComplex64Batch returns an iterator that yields batches of complex64 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsComplex64Batch(options ...ReadOption) iter.Seq2[[]complex64, error] {
-	return BatchStreamReader(ch, options, DataTypeComplex64, interpretComplex64)
+	return nativeBatchStreamReader(ch, options, DataTypeComplex64, interpretComplex64)
 }
 
 // ReadDataAsComplex128Batch returns an iterator that yields batches of complex128 values from the channel.
 // Use BatchSize option to control batch size.
 func (ch *Channel) ReadDataAsComplex128Batch(options ...ReadOption) iter.Seq2[[]complex128, error] {
-	return BatchStreamReader(ch, options, DataTypeComplex128, interpretComplex128)
+	return nativeBatchStreamReader(ch, options, DataTypeComplex128, interpretComplex128)
 }
 
 // Data streaming functions that read all the data for a channel in one go.
+// These return an error wrapping ErrLengthMismatch, along with the values that
+// could be read, if the file contains fewer values than it declares.
 
 // ReadDataInt8All reads all int8 values from the channel into a single slice.
 func (ch *Channel) ReadDataInt8All(options ...ReadOption) ([]int8, error) {
@@ -368,3 +788,245 @@ func (ch *Channel) ReadDataComplex64All(options ...ReadOption) ([]complex64, err
 func (ch *Channel) ReadDataComplex128All(options ...ReadOption) ([]complex128, error) {
 	return readAllData(ch, options, DataTypeComplex128, interpretComplex128)
 }
+
+// ReadDataAsFloat64Coerced reads all values from a channel of any real numeric
+// type into a single slice, converting each value to float64. This is useful
+// for plotting or other generic processing where the exact type of the channel
+// doesn't matter. Values of 64-bit integer and [Float128] channels may lose
+// precision in the conversion.
+//
+// Returns ErrIncorrectType if the channel isn't a real numeric type, i.e. for
+// string, bool, timestamp and complex channels. Use the WarnPrecisionLoss
+// option to be told about uint64 values which lose precision.
+func (ch *Channel) ReadDataAsFloat64Coerced(options ...ReadOption) ([]float64, error) {
+	opts := readOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var interpret interpreter[float64]
+	switch ch.DataType {
+	case DataTypeInt8:
+		interpret = interpretAsFloat64(interpretInt8)
+	case DataTypeInt16:
+		interpret = interpretAsFloat64(interpretInt16)
+	case DataTypeInt32:
+		interpret = interpretAsFloat64(interpretInt32)
+	case DataTypeInt64:
+		interpret = interpretAsFloat64(interpretInt64)
+	case DataTypeUint8:
+		interpret = interpretAsFloat64(interpretUint8)
+	case DataTypeUint16:
+		interpret = interpretAsFloat64(interpretUint16)
+	case DataTypeUint32:
+		interpret = interpretAsFloat64(interpretUint32)
+	case DataTypeUint64:
+		logged := false
+		interpret = func(bytes []byte, order binary.ByteOrder) float64 {
+			value := interpretUint64(bytes, order)
+			if value > maxExactFloat64Int {
+				if opts.warnPrecisionLoss != nil {
+					opts.warnPrecisionLoss(ch.path, value)
+				}
+
+				// Only log the first value to avoid flooding the log.
+				if !logged {
+					ch.f.opts.logger.Warn("uint64 value loses precision as float64", "channel", ch.path, "value", value)
+					logged = true
+				}
+			}
+			return float64(value)
+		}
+	case DataTypeFloat32:
+		interpret = interpretAsFloat64(interpretFloat32)
+	case DataTypeFloat64:
+		interpret = interpretFloat64
+	case DataTypeFloat128:
+		interpret = interpretFloat128AsFloat64
+	default:
+		return nil, fmt.Errorf("%w: cannot read %s channel as float64", ErrIncorrectType, ch.DataType)
+	}
+
+	return readAllData(ch, options, ch.DataType, interpret)
+}
+
+// ReadDataAsFloat32Coerced reads all values from a channel of any real numeric
+// type into a single slice, converting each value straight to float32 without
+// an intermediate float64 slice. This halves the memory needed compared with
+// [Channel.ReadDataAsFloat64Coerced], e.g. for uploading data to a GPU.
+//
+// float32 has only 24 bits of precision, so int32, uint32, 64-bit integer,
+// float64 and [Float128] values may lose precision in the conversion, and
+// float64 and Float128 values beyond the range of float32 become infinite.
+//
+// Returns ErrIncorrectType if the channel isn't a real numeric type, i.e. for
+// string, bool, timestamp and complex channels.
+func (ch *Channel) ReadDataAsFloat32Coerced(options ...ReadOption) ([]float32, error) {
+	var interpret interpreter[float32]
+	switch ch.DataType {
+	case DataTypeInt8:
+		interpret = interpretAsFloat32(interpretInt8)
+	case DataTypeInt16:
+		interpret = interpretAsFloat32(interpretInt16)
+	case DataTypeInt32:
+		interpret = interpretAsFloat32(interpretInt32)
+	case DataTypeInt64:
+		interpret = interpretAsFloat32(interpretInt64)
+	case DataTypeUint8:
+		interpret = interpretAsFloat32(interpretUint8)
+	case DataTypeUint16:
+		interpret = interpretAsFloat32(interpretUint16)
+	case DataTypeUint32:
+		interpret = interpretAsFloat32(interpretUint32)
+	case DataTypeUint64:
+		interpret = interpretAsFloat32(interpretUint64)
+	case DataTypeFloat32:
+		interpret = interpretFloat32
+	case DataTypeFloat64:
+		interpret = interpretAsFloat32(interpretFloat64)
+	case DataTypeFloat128:
+		interpret = interpretAsFloat32(interpretFloat128AsFloat64)
+	default:
+		return nil, fmt.Errorf("%w: cannot read %s channel as float32", ErrIncorrectType, ch.DataType)
+	}
+
+	return readAllData(ch, options, ch.DataType, interpret)
+}
+
+// ReadDataComplexAsReImFloat64 reads all values from a complex64 or complex128
+// channel, returning the real and imaginary parts of the values as separate
+// slices. This avoids holding a slice of complex values in memory alongside the
+// split slices, which is useful for large spectra.
+//
+// Returns ErrIncorrectType if the channel isn't a complex channel.
+func (ch *Channel) ReadDataComplexAsReImFloat64(options ...ReadOption) ([]float64, []float64, error) {
+	switch ch.DataType {
+	case DataTypeComplex64:
+		return readReImData(ch, options, DataTypeComplex64, interpretComplex64)
+	case DataTypeComplex128:
+		return readReImData(ch, options, DataTypeComplex128, interpretComplex128)
+	default:
+		return nil, nil, fmt.Errorf("%w: cannot read %s channel as complex", ErrIncorrectType, ch.DataType)
+	}
+}
+
+// ReadDataComplexMagnitude reads all values from a complex64 or complex128
+// channel, returning the magnitude of each value, i.e. [cmplx.Abs], e.g. for
+// displaying a spectrum. The magnitudes are computed as the values are read,
+// so the complex values are never held in memory.
+//
+// Returns ErrIncorrectType if the channel isn't a complex channel.
+func (ch *Channel) ReadDataComplexMagnitude(options ...ReadOption) ([]float64, error) {
+	return readComplexAs(ch, options, cmplx.Abs)
+}
+
+// ReadDataComplexPhase reads all values from a complex64 or complex128
+// channel, returning the phase of each value in radians, in the range [-Pi,
+// Pi], i.e. [cmplx.Phase]. As with [Channel.ReadDataComplexMagnitude], the
+// complex values are never held in memory.
+//
+// Returns ErrIncorrectType if the channel isn't a complex channel.
+func (ch *Channel) ReadDataComplexPhase(options ...ReadOption) ([]float64, error) {
+	return readComplexAs(ch, options, cmplx.Phase)
+}
+
+// readComplexAs reads all values from a complex channel, applying f to each.
+func readComplexAs(ch *Channel, options []ReadOption, f func(complex128) float64) ([]float64, error) {
+	switch ch.DataType {
+	case DataTypeComplex64:
+		return readAllData(ch, options, DataTypeComplex64, interpretComplexAs(interpretComplex64, f))
+	case DataTypeComplex128:
+		return readAllData(ch, options, DataTypeComplex128, interpretComplexAs(interpretComplex128, f))
+	default:
+		return nil, fmt.Errorf("%w: cannot read %s channel as complex", ErrIncorrectType, ch.DataType)
+	}
+}
+
+// Functions that read a limited number of values from either end of a channel.
+
+// ReadDataFloat64Head reads the first n float64 values from the channel into a
+// single slice. If the channel has fewer than n values, all values are returned.
+// Use [ReadHead] for channels of other data types.
+func (ch *Channel) ReadDataFloat64Head(n uint64, options ...ReadOption) ([]float64, error) {
+	return readRangeData(ch, options, DataTypeFloat64, interpretFloat64, 0, n, true)
+}
+
+// ReadDataFloat64Tail reads the last n float64 values from the channel into a
+// single slice. If the channel has fewer than n values, all values are returned.
+//
+// Chunks before the last n values are skipped entirely, so this is cheap even
+// for very large channels. Use [ReadTail] for channels of other data types.
+func (ch *Channel) ReadDataFloat64Tail(n uint64, options ...ReadOption) ([]float64, error) {
+	start := uint64(0)
+	if n < ch.totalNumValues {
+		start = ch.totalNumValues - n
+	}
+
+	return readRangeData(ch, options, DataTypeFloat64, interpretFloat64, start, n, true)
+}
+
+// ReadDataFloat64Stride reads every nth float64 value from the channel into a
+// single slice, starting with the first value, for a quick look at a large
+// channel. The file is still read through sequentially, but the values in
+// between are neither interpreted nor kept in memory.
+//
+// n must be at least 1, where a stride of 1 reads every value.
+func (ch *Channel) ReadDataFloat64Stride(n int, options ...ReadOption) ([]float64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("stride must be at least 1, got %d", n)
+	}
+
+	return readStrideData(ch, options, DataTypeFloat64, interpretFloat64, uint64(n))
+}
+
+// ReadState records how far through a channel's data a sequence of
+// [Channel.ReadDataFloat64Into] calls has got, so that each call carries on
+// from where the previous one stopped. The zero value starts at the first
+// value. A ReadState must only be used with one channel.
+type ReadState struct {
+	next uint64
+}
+
+// Offset returns the index of the next value that will be read.
+func (s *ReadState) Offset() uint64 {
+	return s.next
+}
+
+// ReadDataFloat64Into reads up to len(dst) float64 values from the channel into
+// dst and returns the number of values read. The only allocation is the
+// internal batch buffer, so this is suited to processing a channel in windows
+// using a buffer which is reused across calls and channels.
+//
+// If state is nil, the values are always read from the start of the channel.
+// Otherwise, they are read from the value at state's offset, which is then
+// advanced past the values read, including when an error stops the read part
+// way through. Once every value has been read, it returns 0 and [io.EOF]. Fewer than len(dst) values are only read at the end of the
+// channel, or along with an error wrapping ErrLengthMismatch if the file
+// contains fewer values than it declares.
+func (ch *Channel) ReadDataFloat64Into(dst []float64, state *ReadState, options ...ReadOption) (int, error) {
+	start := uint64(0)
+	if state != nil {
+		start = state.next
+	}
+
+	if start >= ch.totalNumValues && len(dst) > 0 {
+		return 0, io.EOF
+	}
+
+	n, err := readRangeInto(ch, options, DataTypeFloat64, interpretFloat64, start, dst, true)
+	if state != nil {
+		state.next += uint64(n)
+	}
+
+	if expected := min(uint64(len(dst)), ch.totalNumValues-start); err == nil && uint64(n) < expected {
+		err = fmt.Errorf(
+			"%w: channel %s declares %d values but only %d could be read",
+			ErrLengthMismatch,
+			ch.path,
+			ch.totalNumValues,
+			start+uint64(n),
+		)
+	}
+
+	return n, err
+}
@@ -0,0 +1,1384 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"maps"
+	"math"
+	"math/bits"
+	"slices"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestReadDataFloat64HeadTail(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: []float64{0, 1, 2, 3}},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'channel'", values: []float64{4, 5, 6}},
+			},
+			appendObjects: true,
+		},
+	)
+
+	ch := testChannel(t, f, "group", "channel")
+
+	tests := []struct {
+		name     string
+		read     func(uint64, ...ReadOption) ([]float64, error)
+		n        uint64
+		expected []float64
+	}{
+		{"head zero", ch.ReadDataFloat64Head, 0, []float64{}},
+		{"head within chunk", ch.ReadDataFloat64Head, 3, []float64{0, 1, 2}},
+		{"head across chunks", ch.ReadDataFloat64Head, 6, []float64{0, 1, 2, 3, 0, 1}},
+		{"head beyond end", ch.ReadDataFloat64Head, 100, []float64{0, 1, 2, 3, 0, 1, 2, 3, 4, 5, 6}},
+		{"tail zero", ch.ReadDataFloat64Tail, 0, []float64{}},
+		{"tail within chunk", ch.ReadDataFloat64Tail, 2, []float64{5, 6}},
+		{"tail across segments", ch.ReadDataFloat64Tail, 5, []float64{2, 3, 4, 5, 6}},
+		{"tail beyond end", ch.ReadDataFloat64Tail, 100, []float64{0, 1, 2, 3, 0, 1, 2, 3, 4, 5, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, batchSize := range []int{1, 2, 1024} {
+				values, err := tt.read(tt.n, BatchSize(batchSize))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !slices.Equal(values, tt.expected) {
+					t.Errorf("batch size %d: expected %v, got %v", batchSize, tt.expected, values)
+				}
+			}
+		})
+	}
+}
+
+func TestReadHeadTail(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'ints'", values: []int16{0, 1, 2}},
+				{path: "/'group'/'strings'", values: []string{"a", "bc", ""}},
+			},
+			numChunks: 2,
+		},
+	)
+
+	ints := testChannel(t, f, "group", "ints")
+	head, err := ReadHead(ints, nil, DataTypeInt16, interpretInt16, 4)
+	if err != nil || !slices.Equal(head, []int16{0, 1, 2, 0}) {
+		t.Errorf("expected int16 head [0 1 2 0], got %v (%v)", head, err)
+	}
+
+	// Strings can't be trimmed to the range before reading them, so values at
+	// the start of the first chunk read are skipped.
+	strings := testChannel(t, f, "group", "strings")
+	tail, err := ReadTail(strings, nil, DataTypeString, interpretString, 2)
+	if err != nil || !slices.Equal(tail, []string{"bc", ""}) {
+		t.Errorf("expected string tail [bc ], got %q (%v)", tail, err)
+	}
+}
+
+func TestReadDataFloat64Filter(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 50, 2, 75, 3, 100}},
+		},
+	})
+
+	ch := testChannel(t, f, "group", "channel")
+
+	values := make([]float64, 0)
+	for value, err := range ch.ReadDataFloat64Filter(func(v float64) bool { return v > 10 }, BatchSize(2)) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		values = append(values, value)
+	}
+
+	if expected := []float64{50, 75, 100}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	// Breaking out of the loop early must stop the iterator cleanly.
+	for value := range ch.ReadDataFloat64Filter(func(v float64) bool { return v > 10 }) {
+		if value != 50 {
+			t.Errorf("expected first value 50, got %v", value)
+		}
+		break
+	}
+}
+
+func TestReadDataFloat64Map(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+		},
+		numChunks: 2,
+	})
+
+	ch := testChannel(t, f, "group", "channel")
+
+	values, err := CollectErr(ch.ReadDataFloat64Map(func(v float64) float64 { return v*10 - 1 }, BatchSize(2)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []float64{9, 19, 29, 9, 19, 29}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	// Breaking out of the loop early must stop the iterator cleanly.
+	for value := range ch.ReadDataFloat64Map(math.Sqrt) {
+		if value != 1 {
+			t.Errorf("expected first value 1, got %v", value)
+		}
+		break
+	}
+
+	// Errors from reading must come through the mapped iterator.
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+		},
+	})
+	metadataOnly, err := New(bytes.NewReader(data), false, int64(len(data)), MetadataOnly())
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+	if _, err := CollectErr(testChannel(t, metadataOnly, "group", "channel").ReadDataFloat64Map(math.Sqrt)); !errors.Is(err, ErrMetadataOnly) {
+		t.Errorf("expected ErrMetadataOnly, got %v", err)
+	}
+}
+
+func TestReadDataAsFloat128AsFloat64(t *testing.T) {
+	expected := []float64{1, -2.5, 1e100}
+
+	for _, bigEndian := range []bool{false, true} {
+		values := make([]Float128, len(expected))
+		for i, value := range expected {
+			values[i] = testFloat128(value)
+		}
+
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: values},
+			},
+			bigEndian: bigEndian,
+		})
+
+		actual := make([]float64, 0)
+		for value, err := range testChannel(t, f, "group", "channel").ReadDataAsFloat128AsFloat64() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual = append(actual, value)
+		}
+
+		if !slices.Equal(actual, expected) {
+			t.Errorf("big endian %v: expected %v, got %v", bigEndian, expected, actual)
+		}
+	}
+}
+
+func TestFirstDataOffset(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'empty'"},
+			{path: "/'group'/'channel'", values: []float64{42, 43}},
+		},
+		padding: 4,
+	})
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	if _, ok := testChannel(t, f, "group", "empty").FirstDataOffset(); ok {
+		t.Errorf("expected channel without data to have no offset")
+	}
+
+	offset, ok := testChannel(t, f, "group", "channel").FirstDataOffset()
+	if !ok {
+		t.Fatalf("expected channel to have an offset")
+	}
+
+	if value := math.Float64frombits(binary.LittleEndian.Uint64(data[offset:])); value != 42 {
+		t.Errorf("expected first value at offset %d to be 42, got %v", offset, value)
+	}
+}
+
+func TestReadDataAsFloat64Coerced(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   any
+		expected []float64
+	}{
+		{"int8", []int8{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"int16", []int16{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"int32", []int32{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"int64", []int64{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"uint8", []uint8{1, 2, 3}, []float64{1, 2, 3}},
+		{"uint16", []uint16{1, 2, 3}, []float64{1, 2, 3}},
+		{"uint32", []uint32{1, 2, 3}, []float64{1, 2, 3}},
+		{"uint64", []uint64{1, 2, 3}, []float64{1, 2, 3}},
+		{"float32", []float32{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"float64", []float64{-1, 2, 3}, []float64{-1, 2, 3}},
+		{"float128", []Float128{testFloat128(-1), testFloat128(2), testFloat128(3)}, []float64{-1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := openTestFile(t, testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'channel'", values: tt.values},
+				},
+			})
+
+			values, err := testChannel(t, f, "group", "channel").ReadDataAsFloat64Coerced()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(values, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+
+	for _, values := range []any{[]string{"a"}, []bool{true}, []Timestamp{{}}, []complex128{1i}} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: values},
+			},
+		})
+
+		if _, err := testChannel(t, f, "group", "channel").ReadDataAsFloat64Coerced(); !errors.Is(err, ErrIncorrectType) {
+			t.Errorf("%T: expected ErrIncorrectType, got %v", values, err)
+		}
+	}
+}
+
+func TestReadDataAsFloat32Coerced(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   any
+		expected []float32
+	}{
+		{"int8", []int8{-1, 2, 3}, []float32{-1, 2, 3}},
+		{"int16", []int16{-1, 2, 3}, []float32{-1, 2, 3}},
+		{"int32", []int32{-1, 2, 1 << 24}, []float32{-1, 2, 1 << 24}},
+		{"int64", []int64{-1, 2, 1<<24 + 1}, []float32{-1, 2, 1 << 24}},
+		{"uint8", []uint8{1, 2, 3}, []float32{1, 2, 3}},
+		{"uint16", []uint16{1, 2, 3}, []float32{1, 2, 3}},
+		{"uint32", []uint32{1, 2, 3}, []float32{1, 2, 3}},
+		{"uint64", []uint64{1, 2, 3}, []float32{1, 2, 3}},
+		{"float32", []float32{-1, 2.5, 3}, []float32{-1, 2.5, 3}},
+		{"float64", []float64{-1, 0.1, 1e300}, []float32{-1, 0.1, float32(math.Inf(1))}},
+		{"float128", []Float128{testFloat128(-1), testFloat128(2), testFloat128(3)}, []float32{-1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := openTestFile(t, testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'channel'", values: tt.values},
+				},
+			})
+
+			values, err := testChannel(t, f, "group", "channel").ReadDataAsFloat32Coerced()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(values, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, values)
+			}
+		})
+	}
+
+	for _, values := range []any{[]string{"a"}, []bool{true}, []Timestamp{{}}, []complex64{1i}} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: values},
+			},
+		})
+
+		if _, err := testChannel(t, f, "group", "channel").ReadDataAsFloat32Coerced(); !errors.Is(err, ErrIncorrectType) {
+			t.Errorf("%T: expected ErrIncorrectType, got %v", values, err)
+		}
+	}
+}
+
+func TestRawTypeCode(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:     "/'group'/'with unit'",
+				values:   []float64{1, 2},
+				dataType: DataTypeFloat64WithUnit,
+				props: []Property{
+					{Name: "unit_string", TypeCode: DataTypeString, Value: "V"},
+					{Name: "offset", TypeCode: DataTypeFloat64WithUnit, Value: 0.5},
+				},
+			},
+			{path: "/'group'/'plain'", values: []float64{3, 4}},
+		},
+	})
+
+	withUnit := testChannel(t, f, "group", "with unit")
+	if withUnit.DataType != DataTypeFloat64 {
+		t.Errorf("expected data type %s, got %s", DataTypeFloat64, withUnit.DataType)
+	}
+	if code := withUnit.RawTypeCode(); code != uint32(DataTypeFloat64WithUnit) {
+		t.Errorf("expected raw type code %#x, got %#x", DataTypeFloat64WithUnit, code)
+	}
+
+	values, err := withUnit.ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{1, 2}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	offset, ok := withUnit.Property("offset")
+	if !ok {
+		t.Fatalf("expected offset property")
+	}
+	if offset.TypeCode != DataTypeFloat64WithUnit {
+		t.Errorf("expected property type code %s, got %s", DataTypeFloat64WithUnit, offset.TypeCode)
+	}
+	if code := offset.RawTypeCode(); code != uint32(DataTypeFloat64WithUnit) {
+		t.Errorf("expected property raw type code %#x, got %#x", DataTypeFloat64WithUnit, code)
+	}
+	if value, err := offset.AsFloat64(); err != nil || value != 0.5 {
+		t.Errorf("expected offset 0.5, got %v (%v)", value, err)
+	}
+
+	if code := testChannel(t, f, "group", "plain").RawTypeCode(); code != uint32(DataTypeFloat64) {
+		t.Errorf("expected raw type code %#x, got %#x", DataTypeFloat64, code)
+	}
+}
+
+func TestWithUnitChannels(t *testing.T) {
+	unit := []Property{{Name: "unit_string", TypeCode: DataTypeString, Value: "V"}}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'float32'", values: []float32{1.5, -2}, dataType: DataTypeFloat32WithUnit, props: unit},
+			{path: "/'group'/'float64'", values: []float64{2.5, -3}, dataType: DataTypeFloat64WithUnit, props: unit},
+			{
+				path:     "/'group'/'float128'",
+				values:   []Float128{testFloat128(3.5), testFloat128(-4)},
+				dataType: DataTypeFloat128WithUnit,
+				props:    unit,
+			},
+			{path: "/'group'/'plain'", values: []float64{1}},
+		},
+	})
+
+	expected := map[string][]float64{
+		"float32":  {1.5, -2},
+		"float64":  {2.5, -3},
+		"float128": {3.5, -4},
+	}
+
+	for name, expectedValues := range expected {
+		ch := testChannel(t, f, "group", name)
+
+		if unit, ok := ch.Unit(); !ok || unit != "V" {
+			t.Errorf("%s: expected unit V, got %q (%v)", name, unit, ok)
+		}
+
+		values, err := ch.ReadDataAsFloat64Coerced()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+
+		if !slices.Equal(values, expectedValues) {
+			t.Errorf("%s: expected %v, got %v", name, expectedValues, values)
+		}
+	}
+
+	float64Values, err := testChannel(t, f, "group", "float64").ReadDataFloat64All()
+	if err != nil || !slices.Equal(float64Values, expected["float64"]) {
+		t.Errorf("expected %v, got %v (%v)", expected["float64"], float64Values, err)
+	}
+
+	if unit, ok := testChannel(t, f, "group", "plain").Unit(); ok {
+		t.Errorf("expected no unit, got %q", unit)
+	}
+}
+
+func TestReadDataTimestampAllPreservesRemainder(t *testing.T) {
+	expected := []Timestamp{
+		{Timestamp: 3788905723, Remainder: 1265713805430620160},
+		{Timestamp: 3788905723, Remainder: 1<<64 - 1},
+		{Timestamp: -1, Remainder: 1},
+		{Timestamp: 0, Remainder: 0x0123456789ABCDEF},
+	}
+
+	for _, bigEndian := range []bool{false, true} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: expected},
+			},
+			bigEndian: bigEndian,
+		})
+
+		values, err := testChannel(t, f, "group", "channel").ReadDataTimestampAll(BatchSize(3))
+		if err != nil {
+			t.Fatalf("big endian %v: unexpected error: %v", bigEndian, err)
+		}
+
+		if !slices.Equal(values, expected) {
+			t.Errorf("big endian %v: expected %v, got %v", bigEndian, expected, values)
+		}
+	}
+}
+
+func TestReadDataAsTimeIn(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []Timestamp{
+				{Timestamp: 0},
+				{Timestamp: 3788905723, Remainder: 1 << 63},
+			}},
+		},
+	})
+	ch := testChannel(t, f, "group", "channel")
+
+	expected := []time.Time{
+		time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 24, 1, 48, 43, 500_000_000, time.UTC),
+	}
+
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	for _, test := range []struct {
+		name     string
+		loc      *time.Location
+		expected *time.Location
+	}{
+		{"nil location", nil, time.UTC},
+		{"UTC", time.UTC, time.UTC},
+		{"fixed zone", loc, loc},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			i := 0
+			for value, err := range ch.ReadDataAsTimeIn(test.loc, BatchSize(1)) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !value.Equal(expected[i]) {
+					t.Errorf("value %d: expected %v, got %v", i, expected[i], value)
+				}
+				if value.Location() != test.expected {
+					t.Errorf("value %d: expected location %v, got %v", i, test.expected, value.Location())
+				}
+				i++
+			}
+
+			if i != len(expected) {
+				t.Errorf("expected %d values, got %d", len(expected), i)
+			}
+		})
+	}
+}
+
+func TestForceByteOrder(t *testing.T) {
+	expected := []int32{1, -2, 0x01020304}
+
+	swapped := make([]int32, len(expected))
+	for i, v := range expected {
+		swapped[i] = int32(bits.ReverseBytes32(uint32(v)))
+	}
+
+	for _, test := range []struct {
+		name      string
+		bigEndian bool
+		order     binary.ByteOrder
+	}{
+		{"big endian data with little endian flag", false, binary.BigEndian},
+		{"little endian data with big endian flag", true, binary.LittleEndian},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := openTestFile(t, testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'channel'", values: swapped},
+				},
+				bigEndian: test.bigEndian,
+			})
+			ch := testChannel(t, f, "group", "channel")
+
+			values, err := ch.ReadDataInt32All(ForceByteOrder(test.order))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(values, expected) {
+				t.Errorf("expected %v, got %v", expected, values)
+			}
+
+			values, err = ch.ReadDataInt32All()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(values, swapped) {
+				t.Errorf("expected byte-swapped %v without option, got %v", swapped, values)
+			}
+		})
+	}
+}
+
+func TestReadAllLengthMismatch(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3, 4}},
+		},
+	})
+
+	// The file claims to be complete, but the reader ends part way through the
+	// raw data.
+	f, err := New(bytes.NewReader(data[:len(data)-12]), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	ch := testChannel(t, f, "group", "channel")
+	values, err := ch.ReadDataFloat64All()
+	if !errors.Is(err, ErrLengthMismatch) {
+		t.Fatalf("expected ErrLengthMismatch, got %v", err)
+	}
+
+	if expected := []float64{1, 2}; !slices.Equal(values, expected) {
+		t.Errorf("expected partial values %v, got %v", expected, values)
+	}
+
+	f, err = Open("testdata/standard.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	for _, group := range f.Groups {
+		for _, ch := range group.Channels {
+			if _, err := ch.ReadDataFloat64All(); err != nil {
+				t.Errorf("channel %s: unexpected error: %v", ch.path, err)
+			}
+		}
+	}
+}
+
+func TestWarnPrecisionLoss(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'counter'", values: []uint64{1, 1 << 53, 1<<53 + 1, 1<<64 - 1}},
+		},
+	})
+
+	type warning struct {
+		channel string
+		value   uint64
+	}
+
+	var warnings []warning
+	values, err := testChannel(t, f, "group", "counter").ReadDataAsFloat64Coerced(
+		WarnPrecisionLoss(func(channel string, value uint64) {
+			warnings = append(warnings, warning{channel, value})
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(values) != 4 {
+		t.Errorf("expected 4 values, got %d", len(values))
+	}
+
+	expected := []warning{{"/'group'/'counter'", 1<<53 + 1}, {"/'group'/'counter'", 1<<64 - 1}}
+	if !slices.Equal(warnings, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, warnings)
+	}
+}
+
+func TestAlignedAlloc(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'int8'", values: []int8{1, 2, 3}},
+			{path: "/'group'/'float64'", values: []float64{1, 2, 3, 4, 5}},
+			{path: "/'group'/'time'", values: []Timestamp{{Timestamp: 1}, {Timestamp: 2}}},
+		},
+		numChunks: 2,
+	})
+
+	for _, alignment := range []int{1, 16, 32, 64} {
+		int8s, err := testChannel(t, f, "group", "int8").ReadDataInt8All(AlignedAlloc(alignment))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(int8s, []int8{1, 2, 3, 1, 2, 3}) {
+			t.Errorf("unexpected values %v", int8s)
+		}
+		if addr := uintptr(unsafe.Pointer(&int8s[0])); addr%uintptr(alignment) != 0 {
+			t.Errorf("expected int8 values to be aligned to %d bytes, got address 0x%x", alignment, addr)
+		}
+
+		float64s, err := testChannel(t, f, "group", "float64").ReadDataFloat64All(AlignedAlloc(alignment))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(float64s, []float64{1, 2, 3, 4, 5, 1, 2, 3, 4, 5}) {
+			t.Errorf("unexpected values %v", float64s)
+		}
+		if addr := uintptr(unsafe.Pointer(&float64s[0])); addr%uintptr(alignment) != 0 {
+			t.Errorf("expected float64 values to be aligned to %d bytes, got address 0x%x", alignment, addr)
+		}
+		if cap(float64s) != len(float64s) {
+			t.Errorf("expected capacity %d, got %d", len(float64s), cap(float64s))
+		}
+
+		times, err := testChannel(t, f, "group", "time").ReadDataTimeAll(AlignedAlloc(alignment))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(times) != 4 {
+			t.Errorf("expected 4 values, got %d", len(times))
+		}
+		if addr := uintptr(unsafe.Pointer(&times[0])); addr%uintptr(alignment) != 0 {
+			t.Errorf("expected time values to be aligned to %d bytes, got address 0x%x", alignment, addr)
+		}
+	}
+
+	if _, err := testChannel(t, f, "group", "float64").ReadDataFloat64All(AlignedAlloc(24)); err == nil {
+		t.Errorf("expected error for alignment which isn't a power of two")
+	}
+}
+
+func TestSetDefaultBatchSize(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3, 4, 5}},
+		},
+	})
+
+	ch := testChannel(t, f, "group", "channel")
+
+	batchLens := func(options ...ReadOption) []int {
+		lens := make([]int, 0)
+		for batch, err := range ch.ReadDataAsFloat64Batch(options...) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lens = append(lens, len(batch))
+		}
+		return lens
+	}
+
+	if lens := batchLens(); !slices.Equal(lens, []int{5}) {
+		t.Errorf("expected built-in default to read in one batch, got %v", lens)
+	}
+
+	f.SetDefaultBatchSize(2)
+	if lens := batchLens(); !slices.Equal(lens, []int{2, 2, 1}) {
+		t.Errorf("expected file default batch size to be used, got %v", lens)
+	}
+
+	if lens := batchLens(BatchSize(4)); !slices.Equal(lens, []int{4, 1}) {
+		t.Errorf("expected BatchSize option to override file default, got %v", lens)
+	}
+
+	f.SetDefaultBatchSize(0)
+	if lens := batchLens(); !slices.Equal(lens, []int{5}) {
+		t.Errorf("expected built-in default to be restored, got %v", lens)
+	}
+}
+
+func TestReadDataComplexAsReImFloat64(t *testing.T) {
+	tests := []struct {
+		name   string
+		values any
+	}{
+		{"complex64", []complex64{1 + 2i, -3.5, 4i}},
+		{"complex128", []complex128{1 + 2i, -3.5, 4i}},
+	}
+
+	for _, tt := range tests {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: tt.values},
+			},
+			numChunks: 2,
+		})
+
+		re, im, err := testChannel(t, f, "group", "channel").ReadDataComplexAsReImFloat64(BatchSize(2))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		if expected := []float64{1, -3.5, 0, 1, -3.5, 0}; !slices.Equal(re, expected) {
+			t.Errorf("%s: expected real parts %v, got %v", tt.name, expected, re)
+		}
+		if expected := []float64{2, 0, 4, 2, 0, 4}; !slices.Equal(im, expected) {
+			t.Errorf("%s: expected imaginary parts %v, got %v", tt.name, expected, im)
+		}
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1}},
+		},
+	})
+	if _, _, err := testChannel(t, f, "group", "channel").ReadDataComplexAsReImFloat64(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestReadDataComplexMagnitudePhase(t *testing.T) {
+	tests := []struct {
+		name   string
+		values any
+	}{
+		{"complex64", []complex64{3 + 4i, -2, -1i}},
+		{"complex128", []complex128{3 + 4i, -2, -1i}},
+	}
+
+	for _, tt := range tests {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: tt.values},
+			},
+			numChunks: 2,
+		})
+		ch := testChannel(t, f, "group", "channel")
+
+		magnitudes, err := ch.ReadDataComplexMagnitude(BatchSize(2))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if expected := []float64{5, 2, 1, 5, 2, 1}; !slices.Equal(magnitudes, expected) {
+			t.Errorf("%s: expected magnitudes %v, got %v", tt.name, expected, magnitudes)
+		}
+
+		phases, err := ch.ReadDataComplexPhase()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		expected := []float64{math.Atan2(4, 3), math.Pi, -math.Pi / 2}
+		expected = append(expected, expected...)
+		if len(phases) != len(expected) {
+			t.Fatalf("%s: expected %d phases, got %d", tt.name, len(expected), len(phases))
+		}
+		for i := range phases {
+			if math.Abs(phases[i]-expected[i]) > 1e-6 {
+				t.Errorf("%s: expected phases %v, got %v", tt.name, expected, phases)
+				break
+			}
+		}
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1}},
+		},
+	})
+	ch := testChannel(t, f, "group", "channel")
+	if _, err := ch.ReadDataComplexMagnitude(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+	if _, err := ch.ReadDataComplexPhase(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestChunkSegmentIndex(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{
+					path:   "/'group'/'a'",
+					values: []int32{1, 2},
+					props:  []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(1)}},
+				},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{
+					path:   "/'group'/'a'",
+					values: []int32{3},
+					props:  []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}},
+				},
+			},
+		},
+	)
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ch, err := f.Channel("group", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ch.NumChunks() != 3 {
+		t.Fatalf("expected 3 chunks, got %d", ch.NumChunks())
+	}
+
+	for i, expected := range []int{0, 0, 1, -1} {
+		if actual := ch.ChunkSegmentIndex(i); actual != expected {
+			t.Errorf("chunk %d: expected segment %d, got %d", i, expected, actual)
+		}
+	}
+
+	if ch.ChunkSegmentIndex(-1) != -1 {
+		t.Errorf("expected -1 for negative chunk index")
+	}
+
+	for segment, expected := range []int32{1, 2} {
+		props, ok := ch.SegmentProperties(segment)
+		if !ok {
+			t.Fatalf("segment %d: expected properties", segment)
+		}
+
+		if gain := props["gain"].Value; gain != expected {
+			t.Errorf("segment %d: expected gain %d, got %v", segment, expected, gain)
+		}
+	}
+
+	if _, ok := ch.SegmentProperties(2); ok {
+		t.Errorf("expected no properties for non-existent segment")
+	}
+
+	if gain := ch.Properties["gain"].Value; gain != int32(2) {
+		t.Errorf("expected latest gain 2, got %v", gain)
+	}
+}
+
+func TestSegmentPropertiesMerged(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{
+					path:   "/'group'/'a'",
+					values: []int32{1},
+					props: []Property{
+						{Name: "unit", TypeCode: DataTypeString, Value: "V"},
+						{Name: "gain", TypeCode: DataTypeInt32, Value: int32(1)},
+					},
+				},
+			},
+		},
+		// The new object list doesn't write the unit again.
+		testSegment{
+			objects: []testObject{
+				{
+					path:   "/'group'/'a'",
+					values: []int32{2},
+					props:  []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}},
+				},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'b'", values: []int32{3}},
+			},
+		},
+	)
+	ch := testChannel(t, f, "group", "a")
+
+	for segment, expectedGain := range []int32{1, 2} {
+		props, ok := ch.SegmentProperties(segment)
+		if !ok {
+			t.Fatalf("segment %d: expected properties", segment)
+		}
+
+		if unit := props["unit"].Value; unit != "V" {
+			t.Errorf("segment %d: expected unit V, got %v", segment, unit)
+		}
+		if gain := props["gain"].Value; gain != expectedGain {
+			t.Errorf("segment %d: expected gain %d, got %v", segment, expectedGain, gain)
+		}
+	}
+
+	if _, ok := ch.SegmentProperties(2); ok {
+		t.Errorf("expected no properties for segment without the channel")
+	}
+
+	if props, _ := ch.SegmentProperties(1); !maps.Equal(props, ch.Properties) {
+		t.Errorf("expected properties of segment 1 to match Properties, got %v", props)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []float64{1, 2}},
+				{path: "/'group'/'b'", values: []int32{1, 2}},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []float64{3, 4, 5}},
+			},
+			interleaved: true,
+			bigEndian:   true,
+		},
+	)
+	ch := testChannel(t, f, "group", "a")
+
+	var chunks []ChunkInfo
+	for chunk := range ch.Chunks() {
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+
+	expected := []struct {
+		firstValue   uint64
+		numValues    uint64
+		interleaved  bool
+		segmentIndex int
+		values       []float64
+	}{
+		{0, 2, false, 0, []float64{1, 2}},
+		{2, 2, false, 0, []float64{1, 2}},
+		{4, 3, true, 1, []float64{3, 4, 5}},
+	}
+
+	for i, chunk := range chunks {
+		e := expected[i]
+		if chunk.Index != i || chunk.FirstValue != e.firstValue || chunk.NumValues != e.numValues ||
+			chunk.Interleaved != e.interleaved || chunk.SegmentIndex != e.segmentIndex {
+			t.Errorf("chunk %d: expected %+v, got %+v", i, e, chunk)
+		}
+
+		values, err := ch.ReadChunkFloat64(chunk)
+		if err != nil {
+			t.Fatalf("chunk %d: unexpected error: %v", i, err)
+		}
+
+		if !slices.Equal(values, e.values) {
+			t.Errorf("chunk %d: expected values %v, got %v", i, e.values, values)
+		}
+	}
+
+	if chunks[2].ByteOrder != binary.BigEndian {
+		t.Errorf("expected last chunk to be big endian, got %v", chunks[2].ByteOrder)
+	}
+
+	if chunks[1].Offset <= chunks[0].Offset {
+		t.Errorf("expected chunk offsets to increase, got %d then %d", chunks[0].Offset, chunks[1].Offset)
+	}
+
+	if _, err := ch.ReadChunkFloat64(ChunkInfo{Index: 3}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing chunk, got %v", err)
+	}
+}
+
+func TestPropertyHistory(t *testing.T) {
+	scale := func(value float64) []Property {
+		return []Property{{Name: "NI_Scale[1]_Linear_Slope", TypeCode: DataTypeFloat64, Value: value}}
+	}
+
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1}, props: scale(1)},
+				{path: "/'group'/'b'", values: []int32{1}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{2}},
+			},
+			appendObjects: true,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}, props: scale(1)},
+			},
+			appendObjects: true,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'b'", values: []int32{4}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{5}, props: scale(2.5)},
+			},
+		},
+	)
+
+	history := testChannel(t, f, "group", "a").PropertyHistory("NI_Scale[1]_Linear_Slope")
+
+	expected := []PropertyChange{
+		{SegmentIndex: 0, Property: scale(1)[0]},
+		{SegmentIndex: 4, Property: scale(2.5)[0]},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), history)
+	}
+
+	for i, change := range history {
+		if change.SegmentIndex != expected[i].SegmentIndex || change.Property.Value != expected[i].Property.Value {
+			t.Errorf("change %d: expected %v, got %v", i, expected[i], change)
+		}
+	}
+
+	if history := testChannel(t, f, "group", "b").PropertyHistory("NI_Scale[1]_Linear_Slope"); history != nil {
+		t.Errorf("expected no history for property which is never set, got %v", history)
+	}
+}
+
+func TestDataTypeConsistent(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'consistent'", values: []int32{1}},
+				{path: "/'group'/'changed'", values: []int32{1}},
+				{path: "/'group'/'empty'"},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'consistent'", values: []int32{2}},
+				{path: "/'group'/'changed'", values: []float64{2}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'consistent'", values: []int32{3}},
+				{path: "/'group'/'changed'", values: []int32{3}},
+			},
+		},
+	)
+
+	tests := []struct {
+		channel    string
+		consistent bool
+		dataTypes  []DataType
+	}{
+		{"consistent", true, []DataType{DataTypeInt32}},
+		{"changed", false, []DataType{DataTypeInt32, DataTypeFloat64}},
+		{"empty", true, nil},
+	}
+
+	for _, test := range tests {
+		consistent, dataTypes := testChannel(t, f, "group", test.channel).DataTypeConsistent()
+		if consistent != test.consistent || !slices.Equal(dataTypes, test.dataTypes) {
+			t.Errorf("%s: expected %v %v, got %v %v", test.channel, test.consistent, test.dataTypes, consistent, dataTypes)
+		}
+	}
+}
+
+func TestReadDataFloat64Stride(t *testing.T) {
+	for _, interleaved := range []bool{false, true} {
+		f := openTestFile(t,
+			testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'a'", values: []float64{0, 1, 2, 3, 4}},
+					{path: "/'group'/'b'", values: []int32{-1, -2, -3, -4, -5}},
+				},
+				numChunks:   2,
+				interleaved: interleaved,
+			},
+			testSegment{
+				objects: []testObject{
+					{path: "/'group'/'a'", values: []float64{10, 11, 12}},
+				},
+				appendObjects: true,
+				interleaved:   interleaved,
+			},
+		)
+		ch := testChannel(t, f, "group", "a")
+
+		// The values of a are 0 to 4 twice over, then 10 to 12.
+		all, err := ch.ReadDataFloat64All()
+		if err != nil {
+			t.Fatalf("interleaved %t: unexpected error: %v", interleaved, err)
+		}
+
+		for _, n := range []int{1, 2, 3, 4, 7, 13, 100} {
+			for _, batchSize := range []int{0, 1, 2} {
+				values, err := ch.ReadDataFloat64Stride(n, BatchSize(batchSize))
+				if err != nil {
+					t.Fatalf("interleaved %t, stride %d: unexpected error: %v", interleaved, n, err)
+				}
+
+				var expected []float64
+				for i := 0; i < len(all); i += n {
+					expected = append(expected, all[i])
+				}
+
+				if !slices.Equal(values, expected) {
+					t.Errorf("interleaved %t, stride %d, batch size %d: expected %v, got %v", interleaved, n, batchSize, expected, values)
+				}
+			}
+		}
+
+		if _, err := ch.ReadDataFloat64Stride(0); err == nil {
+			t.Errorf("interleaved %t: expected error for stride 0", interleaved)
+		}
+	}
+}
+
+func TestReadDataFloat64Into(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'channel'", values: []float64{4}},
+			},
+		},
+	)
+	ch := testChannel(t, f, "group", "channel")
+
+	dst := make([]float64, 4)
+	state := &ReadState{}
+
+	var windows [][]float64
+	for {
+		n, err := ch.ReadDataFloat64Into(dst, state, BatchSize(3))
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		windows = append(windows, slices.Clone(dst[:n]))
+	}
+
+	expected := [][]float64{{1, 2, 3, 1}, {2, 3, 4}}
+	if !slices.EqualFunc(windows, expected, slices.Equal) {
+		t.Errorf("expected windows %v, got %v", expected, windows)
+	}
+	if state.Offset() != 7 {
+		t.Errorf("expected offset 7, got %d", state.Offset())
+	}
+
+	// Without a state, every call reads from the start.
+	for range 2 {
+		n, err := ch.ReadDataFloat64Into(dst[:2], nil)
+		if err != nil || n != 2 || !slices.Equal(dst[:2], []float64{1, 2}) {
+			t.Errorf("expected [1 2], got %v (%v)", dst[:n], err)
+		}
+	}
+
+	if n, err := ch.ReadDataFloat64Into(nil, nil); n != 0 || err != nil {
+		t.Errorf("expected nothing read into empty slice, got %d (%v)", n, err)
+	}
+}
+
+func TestReadDataFloat64IntoError(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3, 4, 5, 6, 7}},
+		},
+		numChunks: 2,
+	})
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	ch := testChannel(t, f, "group", "channel")
+
+	// The first batch is read and the second fails.
+	f.data = &flakyReader{Reader: bytes.NewReader(data)}
+
+	dst := make([]float64, 10)
+	state := &ReadState{}
+	n, err := ch.ReadDataFloat64Into(dst, state, BatchSize(3))
+	if !errors.Is(err, errFlaky) {
+		t.Fatalf("expected flaky error, got %v", err)
+	}
+	if n != 3 || !slices.Equal(dst[:n], []float64{1, 2, 3}) {
+		t.Errorf("expected [1 2 3] read before the error, got %v", dst[:n])
+	}
+	if state.Offset() != 3 {
+		t.Errorf("expected offset 3, got %d", state.Offset())
+	}
+
+	// The next call carries on from the values already read.
+	f.data = bytes.NewReader(data)
+	n, err = ch.ReadDataFloat64Into(dst, state, BatchSize(3))
+	if err != nil || !slices.Equal(dst[:n], []float64{4, 5, 6, 7, 1, 2, 3, 4, 5, 6}) {
+		t.Errorf("expected the rest of the values, got %v (%v)", dst[:n], err)
+	}
+}
+
+func TestRangeReadersRealFiles(t *testing.T) {
+	// Both files have several chunks per channel: 2000 values per chunk in
+	// standard.tdms and 500 in big_endian.tdms, so these ranges start and end
+	// part way through chunks.
+	tests := []struct {
+		filename    string
+		groupName   string
+		channelName string
+	}{
+		{"testdata/standard.tdms", "EHM", "TorqueNm"},
+		{"testdata/big_endian.tdms", "Measured Data", "Phase sweep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			f, err := Open(tt.filename)
+			if err != nil {
+				t.Fatalf("failed to open file: %v", err)
+			}
+			t.Cleanup(func() { f.Close() })
+
+			ch := testChannel(t, f, tt.groupName, tt.channelName)
+			all, err := ch.ReadDataFloat64All()
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.filename, err)
+			}
+
+			head, err := ch.ReadDataFloat64Head(2345, BatchSize(300))
+			if err != nil || !slices.Equal(head, all[:2345]) {
+				t.Errorf("%s: head does not match the start of the data (%v)", tt.filename, err)
+			}
+
+			tail, err := ch.ReadDataFloat64Tail(2345, BatchSize(300))
+			if err != nil || !slices.Equal(tail, all[len(all)-2345:]) {
+				t.Errorf("%s: tail does not match the end of the data (%v)", tt.filename, err)
+			}
+
+			stride, err := ch.ReadDataFloat64Stride(777)
+			var expectedStride []float64
+			for i := 0; i < len(all); i += 777 {
+				expectedStride = append(expectedStride, all[i])
+			}
+			if err != nil || !slices.Equal(stride, expectedStride) {
+				t.Errorf("%s: stride does not match every 777th value (%v)", tt.filename, err)
+			}
+
+			dst := make([]float64, 1234)
+			state := &ReadState{}
+			var windows []float64
+			for {
+				n, err := ch.ReadDataFloat64Into(dst, state)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", tt.filename, err)
+				}
+				windows = append(windows, dst[:n]...)
+			}
+			if !slices.Equal(windows, all) {
+				t.Errorf("%s: windows do not match the data", tt.filename)
+			}
+
+			// The generic readers call the given interpreter rather than reading
+			// the values directly.
+			negate := func(b []byte, order binary.ByteOrder) float64 { return -interpretFloat64(b, order) }
+			negHead, err := ReadHead(ch, nil, DataTypeFloat64, negate, 10)
+			negTail, tailErr := ReadTail(ch, nil, DataTypeFloat64, negate, 10)
+			if err != nil || tailErr != nil {
+				t.Fatalf("%s: unexpected errors: %v, %v", tt.filename, err, tailErr)
+			}
+			for i := range 10 {
+				if negHead[i] != -all[i] || negTail[i] != -all[len(all)-10+i] {
+					t.Errorf("%s: expected negated values at %d, got %v and %v", tt.filename, i, negHead[i], negTail[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEstimatedReadBytes(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'floats'", values: []float64{1, 2, 3}},
+				{path: "/'group'/'strings'", values: []string{"a", "bc", ""}},
+				{path: "/'group'/'empty'"},
+			},
+			numChunks: 2,
+		},
+	}
+
+	stringHeaderSize := uint64(unsafe.Sizeof(""))
+	expected := map[string]uint64{
+		"floats":  6 * 8,
+		"strings": 6 + 6*stringHeaderSize,
+		"empty":   0,
+	}
+
+	for _, options := range [][]OpenOption{nil, {MetadataOnly()}} {
+		data := buildTestFile(segments...)
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), options...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for name, size := range expected {
+			if actual := testChannel(t, f, "group", name).EstimatedReadBytes(); actual != size {
+				t.Errorf("%s: expected %d bytes, got %d", name, size, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package tdms
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// Difference is a single difference between two TDMS files found by
+// [Compare].
+type Difference struct {
+	// Path is the TDMS object path that the difference was found in, e.g.
+	// "/'group'/'channel'". The root object has the path "/".
+	Path string
+
+	// Message is a human-readable description of the difference.
+	Message string
+}
+
+// String implements the [fmt.Stringer] interface, returning the path and
+// message of the difference.
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Message)
+}
+
+type compareOptions struct {
+	compareData bool
+	tolerance   float64
+	readOptions []ReadOption
+}
+
+// CompareOption configures how files are compared by [Compare].
+type CompareOption func(*compareOptions)
+
+// CompareData makes [Compare] also compare the data values of each channel
+// which exists in both files with the same data type. Numeric values are
+// considered equal if they differ by at most tolerance, and all other values
+// must be exactly equal. The given read options are used when reading the
+// data.
+func CompareData(tolerance float64, options ...ReadOption) CompareOption {
+	return func(opts *compareOptions) {
+		opts.compareData = true
+		opts.tolerance = tolerance
+		opts.readOptions = options
+	}
+}
+
+// Compare reports the structural differences between two TDMS files: groups
+// and channels which only exist in one of the files, properties which are
+// missing or whose values or types differ, and channels whose data types or
+// number of values differ. Use [CompareData] to compare channel data as well.
+//
+// Differences are returned in a deterministic order, sorted by object path.
+// An error is only returned if reading data from either file fails.
+func Compare(a, b *File, options ...CompareOption) ([]Difference, error) {
+	opts := compareOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	diffs := compareProperties("/", a.Properties, b.Properties)
+
+	for _, groupName := range sortedUnion(a.Groups, b.Groups) {
+		groupPath := formatPath(groupName, "")
+
+		groupA, inA := a.Groups[groupName]
+		groupB, inB := b.Groups[groupName]
+		if !inA || !inB {
+			diffs = append(diffs, missingDifference(groupPath, "group", inA))
+			continue
+		}
+
+		diffs = append(diffs, compareProperties(groupPath, groupA.Properties, groupB.Properties)...)
+
+		for _, channelName := range sortedUnion(groupA.Channels, groupB.Channels) {
+			channelPath := formatPath(groupName, channelName)
+
+			chA, inA := groupA.Channels[channelName]
+			chB, inB := groupB.Channels[channelName]
+			if !inA || !inB {
+				diffs = append(diffs, missingDifference(channelPath, "channel", inA))
+				continue
+			}
+
+			channelDiffs, err := compareChannels(channelPath, &chA, &chB, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			diffs = append(diffs, channelDiffs...)
+		}
+	}
+
+	return diffs, nil
+}
+
+func compareChannels(path string, a, b *Channel, opts compareOptions) ([]Difference, error) {
+	diffs := compareProperties(path, a.Properties, b.Properties)
+
+	if a.DataType != b.DataType {
+		diffs = append(diffs, Difference{
+			Path:    path,
+			Message: fmt.Sprintf("data type differs: %s != %s", a.DataType, b.DataType),
+		})
+
+		// The data can't be meaningfully compared if the types differ.
+		return diffs, nil
+	}
+
+	if a.NumValues() != b.NumValues() {
+		diffs = append(diffs, Difference{
+			Path:    path,
+			Message: fmt.Sprintf("number of values differs: %d != %d", a.NumValues(), b.NumValues()),
+		})
+
+		return diffs, nil
+	}
+
+	if !opts.compareData || a.NumValues() == 0 {
+		return diffs, nil
+	}
+
+	var (
+		numDiffs int
+		firstIdx int
+		err      error
+	)
+
+	switch a.DataType {
+	case DataTypeString:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataStringAll, equal)
+	case DataTypeBool:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataBoolAll, equal)
+	case DataTypeTimestamp:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataTimestampAll, equal)
+	case DataTypeComplex64:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataComplex64All,
+			func(x, y complex64) bool { return cmplx.Abs(complex128(x-y)) <= opts.tolerance })
+	case DataTypeComplex128:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataComplex128All,
+			func(x, y complex128) bool { return cmplx.Abs(x-y) <= opts.tolerance })
+	default:
+		numDiffs, firstIdx, err = compareChannelData(a, b, opts.readOptions, (*Channel).ReadDataAsFloat64Coerced,
+			func(x, y float64) bool {
+				return x == y || math.Abs(x-y) <= opts.tolerance || (math.IsNaN(x) && math.IsNaN(y))
+			})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare data of channel %s: %w", path, err)
+	}
+
+	if numDiffs > 0 {
+		diffs = append(diffs, Difference{
+			Path:    path,
+			Message: fmt.Sprintf("%d of %d values differ, starting at index %d", numDiffs, a.NumValues(), firstIdx),
+		})
+	}
+
+	return diffs, nil
+}
+
+// compareChannelData reads all the values of both channels and returns the
+// number of values which aren't equal, along with the index of the first one.
+func compareChannelData[T any](
+	a, b *Channel,
+	options []ReadOption,
+	readAll func(*Channel, ...ReadOption) ([]T, error),
+	eq func(T, T) bool,
+) (int, int, error) {
+	valuesA, err := readAll(a, options...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	valuesB, err := readAll(b, options...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	numDiffs := 0
+	firstIdx := -1
+	for i := range min(len(valuesA), len(valuesB)) {
+		if !eq(valuesA[i], valuesB[i]) {
+			numDiffs++
+			if firstIdx == -1 {
+				firstIdx = i
+			}
+		}
+	}
+
+	return numDiffs, firstIdx, nil
+}
+
+func equal[T comparable](x, y T) bool {
+	return x == y
+}
+
+func compareProperties(path string, a, b map[string]Property) []Difference {
+	var diffs []Difference
+
+	for _, name := range sortedUnion(a, b) {
+		propA, inA := a[name]
+		propB, inB := b[name]
+
+		switch {
+		case !inA || !inB:
+			diffs = append(diffs, missingDifference(path, fmt.Sprintf("property %q", name), inA))
+		case propA.TypeCode != propB.TypeCode:
+			diffs = append(diffs, Difference{
+				Path:    path,
+				Message: fmt.Sprintf("property %q type differs: %s != %s", name, propA.TypeCode, propB.TypeCode),
+			})
+		case propA.Format() != propB.Format():
+			diffs = append(diffs, Difference{
+				Path:    path,
+				Message: fmt.Sprintf("property %q value differs: %s != %s", name, propA.Format(), propB.Format()),
+			})
+		}
+	}
+
+	return diffs
+}
+
+func missingDifference(path, kind string, inA bool) Difference {
+	if inA {
+		return Difference{Path: path, Message: kind + " missing from second file"}
+	}
+	return Difference{Path: path, Message: kind + " missing from first file"}
+}
+
+// sortedUnion returns the keys which are in either map, sorted.
+func sortedUnion[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
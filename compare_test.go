@@ -0,0 +1,76 @@
+package tdms
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	a := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "run 1"}}},
+			{path: "/'group'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+			{path: "/'group'/'same'", values: []float64{1, 2, 3}},
+			{path: "/'group'/'close'", values: []float64{1, 2, 3}},
+			{path: "/'group'/'type'", values: []int32{1}},
+			{path: "/'group'/'length'", values: []int32{1, 2}},
+			{path: "/'group'/'strings'", values: []string{"a", "b"}},
+			{path: "/'group'/'only a'", values: []int32{1}},
+			{path: "/'only a'"},
+		},
+	})
+
+	b := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "run 2"}}},
+			{path: "/'group'", props: []Property{{Name: "gain", TypeCode: DataTypeFloat64, Value: 2.0}}},
+			{path: "/'group'/'same'", values: []float64{1, 2, 3}},
+			{path: "/'group'/'close'", values: []float64{1, 2.05, 3.5}},
+			{path: "/'group'/'type'", values: []int64{1}},
+			{path: "/'group'/'length'", values: []int32{1}},
+			{path: "/'group'/'strings'", values: []string{"a", "c"}},
+			{path: "/'only b'", props: []Property{{Name: "x", TypeCode: DataTypeBool, Value: true}}},
+		},
+	})
+
+	structural := []Difference{
+		{"/", `property "name" value differs: run 1 != run 2`},
+		{"/'group'", `property "gain" type differs: Int32 != Float64`},
+		{"/'group'/'length'", "number of values differs: 2 != 1"},
+		{"/'group'/'only a'", "channel missing from second file"},
+		{"/'group'/'type'", "data type differs: Int32 != Int64"},
+		{"/'only a'", "group missing from second file"},
+		{"/'only b'", "group missing from first file"},
+	}
+
+	diffs, err := Compare(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(diffs, structural) {
+		t.Errorf("expected differences:\n%v\ngot:\n%v", structural, diffs)
+	}
+
+	diffs, err = Compare(a, b, CompareData(0.1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	withData := slices.Insert(slices.Clone(structural), 2,
+		Difference{"/'group'/'close'", "1 of 3 values differ, starting at index 2"},
+	)
+	withData = slices.Insert(withData, 5,
+		Difference{"/'group'/'strings'", "1 of 2 values differ, starting at index 1"},
+	)
+	if !slices.Equal(diffs, withData) {
+		t.Errorf("expected differences:\n%v\ngot:\n%v", withData, diffs)
+	}
+
+	diffs, err = Compare(a, a, CompareData(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected no differences comparing file to itself, got %v", diffs)
+	}
+}
@@ -0,0 +1,72 @@
+package tdms
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// gzipMagicBytes are the first two bytes of every gzip stream.
+var gzipMagicBytes = []byte{0x1f, 0x8b}
+
+// OpenCompressed opens and parses a gzip-compressed TDMS file, e.g. a
+// ".tdms.gz" file. The file is treated as compressed if its name ends in ".gz"
+// or it starts with the gzip magic bytes. Otherwise, it is opened as a normal
+// TDMS file in the same way as [OpenWith].
+//
+// As gzip streams can't be seeked, the whole file is decompressed into memory
+// before it is parsed, so this needs as much memory as the uncompressed size
+// of the file. For files which are too large for this, decompress them to disk
+// first and use [Open] instead.
+//
+// The caller should still call [File.Close] when done.
+func OpenCompressed(filename string, options ...OpenOption) (*File, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	magic, err := reader.Peek(len(gzipMagicBytes))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	isGzip := strings.HasSuffix(filename, ".gz") || bytes.Equal(magic, gzipMagicBytes)
+	if !isGzip {
+		return OpenWith(filename, options...)
+	}
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress file %s: %w", filename, err)
+	}
+	defer gzipReader.Close()
+
+	data, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress file %s: %w", filename, err)
+	}
+
+	opts := openOptions{
+		isIndex: strings.HasSuffix(strings.TrimSuffix(filename, ".gz"), ".tdms_index"),
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	f := newFile(bytes.NewReader(data), int64(len(data)), opts)
+	f.path = filename
+	if err := f.readMetadata(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	return f, nil
+}
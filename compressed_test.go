@@ -0,0 +1,56 @@
+package tdms
+
+import (
+	"bytes"
+	"compress/gzip"
+	"slices"
+	"testing"
+)
+
+func TestOpenCompressed(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+		},
+	})
+
+	compressed := &bytes.Buffer{}
+	w := gzip.NewWriter(compressed)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("failed to compress test file: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to compress test file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"test.tdms.gz", compressed.Bytes()},
+		// Detected from the magic bytes rather than the suffix.
+		{"test.tdms", compressed.Bytes()},
+		// Not compressed at all.
+		{"plain.tdms", data},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := OpenCompressed(writeTestFile(t, tt.name, tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer f.Close()
+
+			values, err := testChannel(t, f, "group", "channel").ReadDataFloat64All()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if expected := []float64{1, 2, 3}; !slices.Equal(values, expected) {
+				t.Errorf("expected %v, got %v", expected, values)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// daqmxDataType converts the data type code of a DAQmx scaler, which has its
+// own numbering, into the equivalent TDMS data type. Returns DataTypeVoid for
+// unknown codes.
+func daqmxDataType(code uint32) DataType {
+	switch code {
+	case 0:
+		return DataTypeUint8
+	case 1:
+		return DataTypeInt8
+	case 2:
+		return DataTypeUint16
+	case 3:
+		return DataTypeInt16
+	case 4:
+		return DataTypeUint32
+	case 5:
+		return DataTypeInt32
+	case 6:
+		return DataTypeUint64
+	case 7:
+		return DataTypeInt64
+	case 8:
+		return DataTypeFloat32
+	case 9:
+		return DataTypeFloat64
+	case 0xFFFFFFFF:
+		return DataTypeTimestamp
+	default:
+		return DataTypeVoid
+	}
+}
+
+// daqmxInterpreter returns an interpreter converting the raw values of a DAQmx
+// scaler with the given data type to float64.
+func daqmxInterpreter(dataType DataType) (interpreter[float64], error) {
+	switch dataType {
+	case DataTypeInt8:
+		return interpretAsFloat64(interpretInt8), nil
+	case DataTypeInt16:
+		return interpretAsFloat64(interpretInt16), nil
+	case DataTypeInt32:
+		return interpretAsFloat64(interpretInt32), nil
+	case DataTypeInt64:
+		return interpretAsFloat64(interpretInt64), nil
+	case DataTypeUint8:
+		return interpretAsFloat64(interpretUint8), nil
+	case DataTypeUint16:
+		return interpretAsFloat64(interpretUint16), nil
+	case DataTypeUint32:
+		return interpretAsFloat64(interpretUint32), nil
+	case DataTypeUint64:
+		return interpretAsFloat64(interpretUint64), nil
+	case DataTypeFloat32:
+		return interpretAsFloat64(interpretFloat32), nil
+	case DataTypeFloat64:
+		return interpretFloat64, nil
+	default:
+		return nil, fmt.Errorf("%w: DAQmx scaler with data type %s", ErrUnsupportedType, dataType)
+	}
+}
+
+// IsDAQmx returns whether the channel holds DAQmx raw data, which can only be
+// read with [Channel.ReadDAQmxRawData].
+func (ch *Channel) IsDAQmx() bool {
+	return ch.DataType == DataTypeDAQmxRawData
+}
+
+// DAQmxScalerType returns the type of the DAQmx scalers of the channel, as
+// given by its most recent raw data index, and whether the channel holds DAQmx
+// raw data. The type is either "FormatChanging" for the usual scalers, or
+// "DigitalLine" for the scalers of digital line channels.
+func (ch *Channel) DAQmxScalerType() (string, bool) {
+	obj, ok := ch.f.objects[ch.path]
+	if !ok || obj.index == nil || obj.index.scalerType == daqmxScalerTypeNone {
+		return "", false
+	}
+
+	return obj.index.scalerType.String(), true
+}
+
+// ReadDAQmxRawData reads the raw values of a channel written by DAQmx, i.e.
+// one with a DataType of DataTypeDAQmxRawData, before any scaling is applied.
+// The values are converted to float64 and keyed by the scale ID of the scaler
+// they belong to, as a single DAQmx channel may be made up of several raw
+// streams. Values of 64-bit integer scalers may lose precision.
+//
+// DAQmx data is stored in one or more raw buffers, one after the other in each
+// chunk. Each buffer holds a row of bytes for every sample, of the width given
+// in the raw data index, and each scaler picks out its values from a fixed
+// position within the rows of one of the buffers. For digital line scalers,
+// the position is of a single bit, giving values of 0 or 1.
+//
+// Returns ErrIncorrectType if the channel doesn't hold DAQmx raw data, or
+// ErrUnsupportedType if any scaler has a data type which can't be read.
+func (ch *Channel) ReadDAQmxRawData(options ...ReadOption) (map[uint32][]float64, error) {
+	if ch.f.opts.metadataOnly {
+		return nil, ErrMetadataOnly
+	}
+
+	if ch.DataType != DataTypeDAQmxRawData {
+		return nil, fmt.Errorf("%w: %s channel doesn't hold DAQmx raw data", ErrIncorrectType, ch.DataType)
+	}
+
+	opts := readOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	r := ch.f.data
+	if opts.retries > 0 {
+		r = &retryReader{r: r, retries: opts.retries, backoff: opts.retryBackoff}
+	}
+
+	values := make(map[uint32][]float64)
+	var buf []byte
+
+	for _, chunk := range ch.dataChunks() {
+		index := chunk.daqmx
+		if index == nil {
+			return nil, fmt.Errorf("%w: channel %s has data which isn't DAQmx raw data", ErrInvalidFileFormat, ch.path)
+		}
+
+		order := chunk.order
+		if opts.byteOrder != nil {
+			order = opts.byteOrder
+		}
+
+		if _, err := r.Seek(chunk.offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if uint64(cap(buf)) < chunk.size {
+			buf = make([]byte, chunk.size)
+		}
+		buf = buf[:chunk.size]
+
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, errors.Join(ErrReadFailed, err)
+		}
+
+		bufferStart := uint64(0)
+		for bufferIndex, width := range index.widths {
+			bufferSize := chunk.numValues * uint64(width)
+			if bufferStart+bufferSize > uint64(len(buf)) {
+				return nil, fmt.Errorf("%w: DAQmx raw buffers of channel %s overrun the chunk", ErrInvalidFileFormat, ch.path)
+			}
+			buffer := buf[bufferStart : bufferStart+bufferSize]
+			bufferStart += bufferSize
+
+			for _, scaler := range index.scalers {
+				if scaler.rawBufferIndex != uint32(bufferIndex) {
+					continue
+				}
+
+				scalerValues, err := readDAQmxScaler(index.scalerType, scaler, buffer, width, order)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read channel %s: %w", ch.path, err)
+				}
+
+				values[scaler.scaleID] = append(values[scaler.scaleID], scalerValues...)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// readDAQmxScaler picks out the values of a single scaler from a raw buffer
+// made up of rows of the given width.
+func readDAQmxScaler(
+	scalerType daqmxScalerType,
+	scaler daqmxScaler,
+	buffer []byte,
+	width uint32,
+	order binary.ByteOrder,
+) ([]float64, error) {
+	if width == 0 {
+		return nil, fmt.Errorf("%w: DAQmx scaler reads from raw buffer %d, which is empty", ErrInvalidFileFormat, scaler.rawBufferIndex)
+	}
+
+	numValues := len(buffer) / int(width)
+	values := make([]float64, numValues)
+
+	if scalerType == daqmxScalerTypeDigitalLine {
+		byteOffset := scaler.rawByteOffsetWithinStride / 8
+		bit := scaler.rawByteOffsetWithinStride % 8
+		if byteOffset >= width {
+			return nil, fmt.Errorf("%w: digital line bit offset %d is beyond the raw buffer width %d", ErrInvalidFileFormat, scaler.rawByteOffsetWithinStride, width)
+		}
+
+		for i := range values {
+			values[i] = float64((buffer[i*int(width)+int(byteOffset)] >> bit) & 1)
+		}
+
+		return values, nil
+	}
+
+	interpret, err := daqmxInterpreter(scaler.dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := int(scaler.rawByteOffsetWithinStride)
+	size := scaler.dataType.Size()
+	if offset+size > int(width) {
+		return nil, fmt.Errorf("%w: DAQmx scaler at offset %d is beyond the raw buffer width %d", ErrInvalidFileFormat, offset, width)
+	}
+
+	for i := range values {
+		start := i*int(width) + offset
+		values[i] = interpret(buffer[start:start+size], order)
+	}
+
+	return values, nil
+}
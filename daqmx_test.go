@@ -0,0 +1,250 @@
+package tdms
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestReadDAQmxRawDataMultipleBuffers(t *testing.T) {
+	channel := func(name string, rawBufferIndex, offset uint32) testObject {
+		return testObject{
+			path: "/'group'/'" + name + "'",
+			daqmx: &testDAQmx{
+				numValues: 4,
+				widths:    []uint32{4, 4},
+				scalers: []daqmxScaler{
+					{dataType: DataTypeInt16, rawBufferIndex: rawBufferIndex, rawByteOffsetWithinStride: offset},
+				},
+			},
+		}
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			channel("a", 0, 0),
+			channel("b", 0, 2),
+			channel("c", 1, 0),
+			channel("d", 1, 2),
+		},
+		daqmxData: []byte{
+			// Buffer 0, with a row of 4 bytes for each value.
+			1, 0, 2, 0,
+			3, 0, 4, 0,
+			5, 0, 6, 0,
+			7, 0, 8, 0,
+			// Buffer 1.
+			9, 0, 10, 0,
+			11, 0, 12, 0,
+			13, 0, 14, 0,
+			15, 0, 0xff, 0xff,
+		},
+		numChunks: 2,
+	})
+
+	expected := map[string][]float64{
+		"a": {1, 3, 5, 7},
+		"b": {2, 4, 6, 8},
+		"c": {9, 11, 13, 15},
+		"d": {10, 12, 14, -1},
+	}
+
+	for name, values := range expected {
+		ch := testChannel(t, f, "group", name)
+		if ch.DataType != DataTypeDAQmxRawData {
+			t.Errorf("%s: expected data type %s, got %s", name, DataTypeDAQmxRawData, ch.DataType)
+		}
+
+		if ch.NumValues() != 8 {
+			t.Errorf("%s: expected 8 values, got %d", name, ch.NumValues())
+		}
+
+		data, err := ch.ReadDAQmxRawData()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if expected := slices.Concat(values, values); !slices.Equal(data[0], expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, data[0])
+		}
+
+		if _, err := ch.ReadDataFloat64All(); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%s: expected ErrUnsupportedType reading as float64, got %v", name, err)
+		}
+	}
+}
+
+func TestReadDAQmxRawDataMultipleScalers(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'float'", values: []float64{1}},
+			{
+				path: "/'group'/'a'",
+				daqmx: &testDAQmx{
+					numValues: 2,
+					widths:    []uint32{5, 1},
+					scalers: []daqmxScaler{
+						{dataType: DataTypeInt32, rawBufferIndex: 0, rawByteOffsetWithinStride: 1, scaleID: 0},
+						{dataType: DataTypeUint8, rawBufferIndex: 1, rawByteOffsetWithinStride: 0, scaleID: 1},
+					},
+				},
+			},
+		},
+		daqmxData: []byte{
+			0xaa, 1, 0, 0, 0,
+			0xaa, 0xfe, 0xff, 0xff, 0xff,
+			7,
+			8,
+		},
+	})
+
+	data, err := testChannel(t, f, "group", "a").ReadDAQmxRawData()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keys := slices.Sorted(maps.Keys(data)); !slices.Equal(keys, []uint32{0, 1}) {
+		t.Fatalf("expected scale IDs [0 1], got %v", keys)
+	}
+
+	if expected := []float64{1, -2}; !slices.Equal(data[0], expected) {
+		t.Errorf("scale 0: expected %v, got %v", expected, data[0])
+	}
+
+	if expected := []float64{7, 8}; !slices.Equal(data[1], expected) {
+		t.Errorf("scale 1: expected %v, got %v", expected, data[1])
+	}
+
+	// The data of the object before the DAQmx object comes before the raw
+	// buffers.
+	values, err := testChannel(t, f, "group", "float").ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []float64{1}; !slices.Equal(values, expected) {
+		t.Errorf("float: expected %v, got %v", expected, values)
+	}
+
+	if _, err := testChannel(t, f, "group", "float").ReadDAQmxRawData(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestReadDAQmxRawDataDigitalLines(t *testing.T) {
+	line := func(name string, bitOffset uint32) testObject {
+		return testObject{
+			path: "/'group'/'" + name + "'",
+			daqmx: &testDAQmx{
+				numValues: 3,
+				widths:    []uint32{2},
+				scalers:   []daqmxScaler{{dataType: DataTypeUint8, rawByteOffsetWithinStride: bitOffset}},
+				digital:   true,
+			},
+		}
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			line("line 0", 0),
+			line("line 3", 3),
+			line("line 9", 9),
+		},
+		daqmxData: []byte{
+			0b0000_0001, 0b0000_0000,
+			0b0000_1000, 0b0000_0010,
+			0b0000_1001, 0b0000_0010,
+		},
+	})
+
+	expected := map[string][]float64{
+		"line 0": {1, 0, 1},
+		"line 3": {0, 1, 1},
+		"line 9": {0, 1, 1},
+	}
+
+	for name, values := range expected {
+		data, err := testChannel(t, f, "group", name).ReadDAQmxRawData()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if !slices.Equal(data[0], values) {
+			t.Errorf("%s: expected %v, got %v", name, values, data[0])
+		}
+	}
+}
+
+func TestReadDAQmxRawDataTestData(t *testing.T) {
+	f, err := Open("testdata/raw.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	data, err := testChannel(t, f, "Layer Data", "First  Channel").ReadDAQmxRawData()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(data[0]) != 2000 {
+		t.Fatalf("expected 2000 values, got %d", len(data[0]))
+	}
+
+	if expected := []float64{-603, 485, -803}; !slices.Equal(data[0][:3], expected) {
+		t.Errorf("expected values to start with %v, got %v", expected, data[0][:3])
+	}
+}
+
+func TestDAQmxScalerType(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'plain'", values: []float64{1, 2}},
+			{
+				path: "/'group'/'analog'",
+				daqmx: &testDAQmx{
+					numValues: 2,
+					widths:    []uint32{2},
+					scalers:   []daqmxScaler{{dataType: DataTypeInt16}},
+				},
+			},
+			{
+				path: "/'group'/'digital'",
+				daqmx: &testDAQmx{
+					numValues: 2,
+					widths:    []uint32{2},
+					scalers:   []daqmxScaler{{dataType: DataTypeUint8}},
+					digital:   true,
+				},
+			},
+		},
+		daqmxData: []byte{1, 0, 2, 0},
+	})
+
+	tests := []struct {
+		channel    string
+		isDAQmx    bool
+		scalerType string
+	}{
+		{"plain", false, ""},
+		{"analog", true, "FormatChanging"},
+		{"digital", true, "DigitalLine"},
+	}
+
+	for _, tt := range tests {
+		ch := testChannel(t, f, "group", tt.channel)
+		if ch.IsDAQmx() != tt.isDAQmx {
+			t.Errorf("%s: expected IsDAQmx to be %t", tt.channel, tt.isDAQmx)
+		}
+
+		scalerType, ok := ch.DAQmxScalerType()
+		if ok != tt.isDAQmx || scalerType != tt.scalerType {
+			t.Errorf("%s: expected scaler type %q, %t, got %q, %t", tt.channel, tt.scalerType, tt.isDAQmx, scalerType, ok)
+		}
+	}
+}
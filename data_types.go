@@ -4,7 +4,10 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
+	"math/bits"
+	"strings"
 	"time"
 )
 
@@ -79,6 +82,59 @@ const (
 	DataTypeDAQmxRawData DataType = 0xFFFFFFFF
 )
 
+// SupportedDataTypes returns all the data types which can currently be read by
+// this library, in type code order. Fixed point and DAQmx raw data are not
+// included as they are not supported.
+func SupportedDataTypes() []DataType {
+	return []DataType{
+		DataTypeInt8,
+		DataTypeInt16,
+		DataTypeInt32,
+		DataTypeInt64,
+		DataTypeUint8,
+		DataTypeUint16,
+		DataTypeUint32,
+		DataTypeUint64,
+		DataTypeFloat32,
+		DataTypeFloat64,
+		DataTypeFloat128,
+		DataTypeString,
+		DataTypeBool,
+		DataTypeTimestamp,
+		DataTypeComplex64,
+		DataTypeComplex128,
+	}
+}
+
+// baseType returns the data type without any unit, i.e. the "with unit" float
+// types are mapped to the plain float types which they are stored as. All other
+// data types are returned as-is.
+func (dt DataType) baseType() DataType {
+	switch dt {
+	case DataTypeFloat32WithUnit:
+		return DataTypeFloat32
+	case DataTypeFloat64WithUnit:
+		return DataTypeFloat64
+	case DataTypeFloat128WithUnit:
+		return DataTypeFloat128
+	default:
+		return dt
+	}
+}
+
+// isRealNumeric returns whether values of this data type are real numbers,
+// i.e. integers or floats, which can be coerced to float64.
+func (dt DataType) isRealNumeric() bool {
+	switch dt.baseType() {
+	case DataTypeInt8, DataTypeInt16, DataTypeInt32, DataTypeInt64,
+		DataTypeUint8, DataTypeUint16, DataTypeUint32, DataTypeUint64,
+		DataTypeFloat32, DataTypeFloat64, DataTypeFloat128:
+		return true
+	default:
+		return false
+	}
+}
+
 // Size returns the size in bytes of a single value of this data type.
 // Returns 0 for variable-length types like strings.
 func (dt DataType) Size() int {
@@ -152,6 +208,108 @@ func (dt DataType) Name() string {
 	}
 }
 
+// ParseDataType returns the [DataType] with the given name, ignoring case. As
+// well as the names returned by [DataType.String], it accepts the Go style
+// names "boolean", "timestamp", "complex64" and "complex128". This is useful
+// for tools which take data types from configuration files.
+//
+// ParseDataType(dt.String()) round-trips for every named data type except the
+// "with unit" float types, which have no names of their own:
+// DataTypeFloat128WithUnit is named "Float128" and parses as DataTypeFloat128.
+//
+// Returns ErrUnsupportedType if the name isn't recognised.
+func ParseDataType(name string) (DataType, error) {
+	switch strings.ToLower(name) {
+	case "void":
+		return DataTypeVoid, nil
+	case "int8":
+		return DataTypeInt8, nil
+	case "int16":
+		return DataTypeInt16, nil
+	case "int32":
+		return DataTypeInt32, nil
+	case "int64":
+		return DataTypeInt64, nil
+	case "uint8":
+		return DataTypeUint8, nil
+	case "uint16":
+		return DataTypeUint16, nil
+	case "uint32":
+		return DataTypeUint32, nil
+	case "uint64":
+		return DataTypeUint64, nil
+	case "float32":
+		return DataTypeFloat32, nil
+	case "float64":
+		return DataTypeFloat64, nil
+	case "float128":
+		return DataTypeFloat128, nil
+	case "string":
+		return DataTypeString, nil
+	case "bool", "boolean":
+		return DataTypeBool, nil
+	case "time", "timestamp":
+		return DataTypeTimestamp, nil
+	case "complexfloat64", "complex64":
+		return DataTypeComplex64, nil
+	case "complexfloat128", "complex128":
+		return DataTypeComplex128, nil
+	case "fixedpoint":
+		return DataTypeFixedPoint, nil
+	case "daqmxrawdata":
+		return DataTypeDAQmxRawData, nil
+	default:
+		return DataTypeVoid, fmt.Errorf("%w: unknown data type name %q", ErrUnsupportedType, name)
+	}
+}
+
+// DataTypeOf returns the [DataType] corresponding to the Go type of v. This is
+// the reverse of the mapping used when reading property values, with the
+// addition that [time.Time] maps to [DataTypeTimestamp].
+//
+// Returns ErrUnsupportedType if v is not one of the types listed in the package
+// documentation.
+func DataTypeOf(v any) (DataType, error) {
+	switch v.(type) {
+	case nil:
+		return DataTypeVoid, nil
+	case int8:
+		return DataTypeInt8, nil
+	case int16:
+		return DataTypeInt16, nil
+	case int32:
+		return DataTypeInt32, nil
+	case int64:
+		return DataTypeInt64, nil
+	case uint8:
+		return DataTypeUint8, nil
+	case uint16:
+		return DataTypeUint16, nil
+	case uint32:
+		return DataTypeUint32, nil
+	case uint64:
+		return DataTypeUint64, nil
+	case float32:
+		return DataTypeFloat32, nil
+	case float64:
+		return DataTypeFloat64, nil
+	case Float128:
+		return DataTypeFloat128, nil
+	case string:
+		return DataTypeString, nil
+	case bool:
+		return DataTypeBool, nil
+	case Timestamp, time.Time:
+		return DataTypeTimestamp, nil
+	case complex64:
+		return DataTypeComplex64, nil
+	case complex128:
+		return DataTypeComplex128, nil
+	default:
+		return DataTypeVoid, fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+	}
+}
+
 func readValue(typeCode DataType, reader io.Reader, byteOrder binary.ByteOrder) (any, error) {
 	switch typeCode {
 	case DataTypeVoid:
@@ -191,7 +349,7 @@ func readValue(typeCode DataType, reader io.Reader, byteOrder binary.ByteOrder)
 	case DataTypeComplex128:
 		return readComplex128(reader, byteOrder)
 	default:
-		return nil, ErrUnsupportedType
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, typeCode)
 	}
 
 	// The NI documentation provides nothing on how fixed points are stored.
@@ -229,30 +387,30 @@ func (f Float128) String() string {
 
 // AsFloat64 converts the 128-bit extended precision float to a primitive float64.
 // This loses a significant amount of precision. To avoid losing any precision
-// at the cost of usability, see [Float128.AsBigFloat].
+// at the cost of usability, see [Float128.AsBigFloat]. NaN values are converted
+// to [math.NaN].
 func (f Float128) AsFloat64() float64 {
-	result, _ := f.AsBigFloat().Float64()
+	bigFloat := f.AsBigFloat()
+	if bigFloat == nil {
+		return math.NaN()
+	}
+
+	result, _ := bigFloat.Float64()
 	return result
 }
 
 // AsBigFloat converts the 128-bit extended precision float to a big.Float.
 // This is the most precise representation of the value, meaning no precision is
-// lost in the conversion to big.Float.
+// lost in the conversion to big.Float. As big.Float cannot represent NaN, this
+// returns nil for NaN values.
 //
 // If you do not require the full precision of the original 128-bit floating
 // point number, you can use the [Float128.AsFloat64] method to convert the
 // float to a 64-bit number, losing precision at the benefit of ease of use.
 func (f Float128) AsBigFloat() *big.Float {
-	// Extract sign bit (bit 127)
-	sign := (f[0] >> 7) & 1
-
-	// Extract exponent (bits 126-112, 15 bits total)
-	exponent := uint16(f[0]&0x7F) << 8
-	exponent |= uint16(f[1])
-
-	// Extract mantissa (bits 111-0, 112 bits)
-	mantissaBits := make([]byte, 14)
-	copy(mantissaBits, f[2:16])
+	sign := f.SignBit()
+	exponent := f.Exponent()
+	mantissaBits := f.MantissaBits()
 
 	// Quad precision has 113 bits of precision according to IEEE
 	result := new(big.Float).SetPrec(113)
@@ -260,7 +418,7 @@ func (f Float128) AsBigFloat() *big.Float {
 	// Handle special case of nan/inf
 	if exponent == 0x7FFF {
 		if isZeroMantissa(mantissaBits) {
-			return result.SetInf(sign == 1)
+			return result.SetInf(sign)
 		} else {
 			// big.Float can't handle NaN values.
 			return nil
@@ -284,7 +442,7 @@ func (f Float128) AsBigFloat() *big.Float {
 		power := new(big.Float).SetMantExp(big.NewFloat(1), -16382)
 		result.Mul(mantissaFloat, power)
 
-		if sign == 1 {
+		if sign {
 			result.Neg(result)
 		}
 
@@ -307,13 +465,92 @@ func (f Float128) AsBigFloat() *big.Float {
 	result.Mul(mantissaFloat, power)
 
 	// Apply sign
-	if sign == 1 {
+	if sign {
 		result.Neg(result)
 	}
 
 	return result
 }
 
+// Bytes returns the 16 bytes of the IEEE 754 quadruple precision
+// representation of f, in little endian order as it is stored in memory.
+func (f Float128) Bytes() [16]byte {
+	return f
+}
+
+// SignBit returns whether the sign bit of f is set, i.e. whether f is negative
+// or negative zero.
+func (f Float128) SignBit() bool {
+	// The value is stored little endian, so the sign is the top bit of the
+	// last byte.
+	return f[15]&0x80 != 0
+}
+
+// Exponent returns the 15-bit biased exponent of f, exactly as it is stored.
+// The exponent of a normal number is this minus 16383, whereas zero and
+// subnormal numbers have an exponent of 0, and infinities and NaNs have an
+// exponent of 0x7FFF.
+func (f Float128) Exponent() uint16 {
+	return uint16(f[15]&0x7F)<<8 | uint16(f[14])
+}
+
+// MantissaBits returns the 112 stored bits of the mantissa of f as 14 big
+// endian bytes, i.e. the fraction without the implicit leading bit.
+func (f Float128) MantissaBits() []byte {
+	mantissaBits := make([]byte, 14)
+	for i := range mantissaBits {
+		mantissaBits[i] = f[13-i]
+	}
+
+	return mantissaBits
+}
+
+// Equals returns whether f and other represent the same number. As with
+// float64, NaN is not equal to anything, including itself, and positive and
+// negative zero are equal. Use [Float128.SameBits] to compare the exact bytes.
+func (f Float128) Equals(other Float128) bool {
+	if f.isNaN() || other.isNaN() {
+		return false
+	}
+
+	// Every number other than zero has exactly one representation.
+	return f.Canonical() == other.Canonical()
+}
+
+// SameBits returns whether f and other have exactly the same bytes. Unlike
+// [Float128.Equals], a NaN is the same as itself and positive and negative
+// zero are different.
+func (f Float128) SameBits(other Float128) bool {
+	return f == other
+}
+
+// Canonical returns the canonical byte form of f, where negative zero is
+// replaced by positive zero and every NaN is replaced by the same quiet NaN.
+// Values which are equal according to [Float128.Equals] have the same
+// canonical form, so it can be used as a map key or hashed.
+func (f Float128) Canonical() Float128 {
+	switch {
+	case f.isNaN():
+		return canonicalFloat128NaN
+	case f.isZero():
+		return Float128{}
+	default:
+		return f
+	}
+}
+
+// canonicalFloat128NaN is the quiet NaN with no payload.
+var canonicalFloat128NaN = Float128{13: 0x80, 14: 0xFF, 15: 0x7F}
+
+func (f Float128) isNaN() bool {
+	return f.Exponent() == 0x7FFF && !isZeroMantissa(f[:14])
+}
+
+func (f Float128) isZero() bool {
+	// Ignore the sign bit.
+	return f[15]&0x7F == 0 && isZeroMantissa(f[:15])
+}
+
 func isZeroMantissa(mantissaBits []byte) bool {
 	for _, b := range mantissaBits {
 		if b != 0 {
@@ -346,6 +583,10 @@ func mantissaToBigInt(mantissaBits []byte) *big.Int {
 // the fractional remainder, where the actual fractional number of seconds is
 // retrieved by dividing by 2^64. There is no timezone support.
 //
+// Timestamp is comparable, so it can be used with == and as a map key. Two
+// timestamps are equal only if both their seconds and remainder are equal,
+// i.e. they are compared at the full TDMS precision.
+//
 // For details, see:
 // https://www.ni.com/en/support/documentation/supplemental/08/labview-timestamp-overview.html
 type Timestamp struct {
@@ -358,20 +599,57 @@ type Timestamp struct {
 	Remainder uint64
 }
 
-// AsTime converts the TDMS timestamp to a [time.Time] value. This removes much
+// tdmsEpochOffset is the number of seconds between the TDMS epoch (January 1,
+// 1904 00:00:00 UTC) and the Unix epoch.
+const tdmsEpochOffset = 2082844800
+
+// NewTimestampFromTime converts a [time.Time] to a TDMS timestamp. This is the
+// inverse of [Timestamp.AsTime], so converting the result back with AsTime
+// gives the same instant. The remainder is the largest fraction of 2^-64ths of
+// a second that doesn't exceed the nanoseconds of t, calculated exactly using
+// the full 128-bit quotient.
+func NewTimestampFromTime(t time.Time) Timestamp {
+	// Nanosecond is always less than 10^9, so the quotient fits in a uint64.
+	remainder, _ := bits.Div64(uint64(t.Nanosecond()), 0, 1e9)
+
+	return Timestamp{
+		Timestamp: t.Unix() + tdmsEpochOffset,
+		Remainder: remainder,
+	}
+}
+
+// AsTime converts the TDMS timestamp to a [time.Time] value in UTC. This removes much
 // of the precision in the TDMS timestamp by converting from uint64 remainder
 // value (which represents 2^-64ths of a second, approximately 0.05 attoseconds)
-// to nanoseconds. The TDMS format retains approximately 1.8 × 10^10 times more
-// information than [time.Time]. This precision loss is not relevant for most
-// purposes, but important to keep in mind for high-precision applications.
+// to nanoseconds, rounding to the nearest nanosecond. The TDMS format retains
+// approximately 1.8 × 10^10 times more information than [time.Time]. This
+// precision loss is not relevant for most purposes, but important to keep in
+// mind for high-precision applications.
+//
+// Use [time.Time.In] to get the time in another location, which changes only
+// how the time is presented and not the instant it represents.
 func (t *Timestamp) AsTime() time.Time {
-	// I'm not sure whether this big.Int stuff is necessary as opposed to doing
-	// `float64(posFractions) * math.Pow(2, -64) * 1e9`. I need to experiment
-	// with some large values to determine.
-	ns := new(big.Int).SetUint64(t.Remainder)
-	ns.Mul(ns, big.NewInt(1e9))
-	ns.Rsh(ns, 64)
-	return time.Unix(t.Timestamp, ns.Int64())
+	return time.Unix(t.Timestamp-tdmsEpochOffset, t.nanoseconds()).UTC()
+}
+
+// nanoseconds returns the remainder as a number of nanoseconds, rounded to the
+// nearest nanosecond. This may be 1e9 if the remainder rounds up to a whole
+// second, which [time.Unix] handles by carrying into the seconds.
+//
+// This is calculated exactly as remainder * 10^9 / 2^64 using the full 128-bit
+// product. Doing this in floating point as remainder * 2^-64 * 10^9 is not good
+// enough, as a float64 can't hold every uint64 remainder exactly, so values
+// close to half a nanosecond can round the wrong way.
+func (t *Timestamp) nanoseconds() int64 {
+	hi, lo := bits.Mul64(t.Remainder, 1e9)
+
+	// hi is the whole number of nanoseconds and lo is the fraction of a
+	// nanosecond in 2^-64ths, so round half up.
+	if lo >= 1<<63 {
+		hi++
+	}
+
+	return int64(hi)
 }
 
 // String implements the [fmt.Stringer] interface, returning the string
@@ -1,3 +1,420 @@
 package tdms
 
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
 // TODO: Tests for all the different data types.
+
+func TestDataTypeOf(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected DataType
+	}{
+		{nil, DataTypeVoid},
+		{int8(1), DataTypeInt8},
+		{int16(1), DataTypeInt16},
+		{int32(1), DataTypeInt32},
+		{int64(1), DataTypeInt64},
+		{uint8(1), DataTypeUint8},
+		{uint16(1), DataTypeUint16},
+		{uint32(1), DataTypeUint32},
+		{uint64(1), DataTypeUint64},
+		{float32(1), DataTypeFloat32},
+		{float64(1), DataTypeFloat64},
+		{Float128{}, DataTypeFloat128},
+		{"value", DataTypeString},
+		{true, DataTypeBool},
+		{Timestamp{}, DataTypeTimestamp},
+		{time.Now(), DataTypeTimestamp},
+		{complex64(1), DataTypeComplex64},
+		{complex128(1), DataTypeComplex128},
+	}
+
+	for _, tt := range tests {
+		dataType, err := DataTypeOf(tt.value)
+		if err != nil {
+			t.Errorf("%T: unexpected error: %v", tt.value, err)
+			continue
+		}
+
+		if dataType != tt.expected {
+			t.Errorf("%T: expected %s, got %s", tt.value, tt.expected, dataType)
+		}
+	}
+
+	for _, value := range []any{1, uint(1), []float64{1}, struct{}{}} {
+		if _, err := DataTypeOf(value); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%T: expected ErrUnsupportedType, got %v", value, err)
+		}
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	// The "with unit" float types are left out as they aren't named separately.
+	roundTrip := append(SupportedDataTypes(), DataTypeVoid, DataTypeFixedPoint, DataTypeDAQmxRawData)
+	for _, dt := range roundTrip {
+		parsed, err := ParseDataType(dt.String())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", dt, err)
+			continue
+		}
+
+		if parsed != dt {
+			t.Errorf("%s: expected round trip, got %s", dt, parsed)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected DataType
+	}{
+		{"int32", DataTypeInt32},
+		{"FLOAT64", DataTypeFloat64},
+		{"boolean", DataTypeBool},
+		{"timestamp", DataTypeTimestamp},
+		{"complex64", DataTypeComplex64},
+		{"complex128", DataTypeComplex128},
+	}
+
+	for _, tt := range tests {
+		parsed, err := ParseDataType(tt.name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		if parsed != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, parsed)
+		}
+	}
+
+	if parsed, err := ParseDataType(DataTypeFloat128WithUnit.String()); err != nil || parsed != DataTypeFloat128 {
+		t.Errorf("expected Float128 with unit to parse as Float128, got %s (%v)", parsed, err)
+	}
+
+	for _, name := range []string{"", "int", "float", "Unknown(0x99)"} {
+		if _, err := ParseDataType(name); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("%q: expected ErrUnsupportedType, got %v", name, err)
+		}
+	}
+}
+
+func TestFloat128AsFloat64(t *testing.T) {
+	for _, value := range []float64{0, 1, -1, 2.5, -1234.5678, 1e-300, 1e300, math.Pi, math.Inf(1), math.Inf(-1)} {
+		if actual := testFloat128(value).AsFloat64(); actual != value {
+			t.Errorf("expected %v, got %v", value, actual)
+		}
+	}
+
+	if actual := testFloat128(math.NaN()).AsFloat64(); !math.IsNaN(actual) {
+		t.Errorf("expected NaN, got %v", actual)
+	}
+
+	// Smallest positive subnormal quad precision value, which is far too small
+	// to be represented as a float64.
+	subnormal := Float128{1}
+	if actual := subnormal.AsBigFloat(); actual.Sign() != 1 || actual.MantExp(nil) != -16493 {
+		t.Errorf("expected 2^-16494, got %v", actual)
+	}
+}
+
+func TestFloat128ByteOrder(t *testing.T) {
+	// Quad precision values as they appear in a little endian file, with the
+	// sign and exponent in the last two bytes.
+	tests := []struct {
+		name     string
+		raw      []byte
+		expected float64
+	}{
+		{"one", []byte{14: 0xFF, 15: 0x3F}, 1},
+		{"negative two", []byte{14: 0x00, 15: 0xC0}, -2},
+		{"three quarters", []byte{13: 0x80, 14: 0xFE, 15: 0x3F}, 0.75},
+	}
+
+	for _, tt := range tests {
+		if actual := Float128(tt.raw).AsFloat64(); actual != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, actual)
+		}
+
+		// A big endian file has the same bytes in reverse.
+		raw := slices.Clone(tt.raw)
+		slices.Reverse(raw)
+		values, err := DecodeValues(raw, DataTypeFloat128, binary.BigEndian, 1)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if actual := values[0].(Float128).AsFloat64(); actual != tt.expected {
+			t.Errorf("%s big endian: expected %v, got %v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestFloat128Bits(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        Float128
+		bytes        [16]byte
+		signBit      bool
+		exponent     uint16
+		mantissaBits []byte
+	}{
+		{"one", testFloat128(1), [16]byte{14: 0xFF, 15: 0x3F}, false, 0x3FFF, make([]byte, 14)},
+		{
+			"negative 2.5",
+			testFloat128(-2.5),
+			[16]byte{13: 0x40, 14: 0x00, 15: 0xC0},
+			true,
+			0x4000,
+			append([]byte{0x40}, make([]byte, 13)...),
+		},
+		{"negative zero", testFloat128(math.Copysign(0, -1)), [16]byte{15: 0x80}, true, 0, make([]byte, 14)},
+		{"infinity", testFloat128(math.Inf(1)), [16]byte{14: 0xFF, 15: 0x7F}, false, 0x7FFF, make([]byte, 14)},
+		{"subnormal", Float128{1}, [16]byte{1}, false, 0, append(make([]byte, 13), 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.value.Bytes(); actual != test.bytes {
+				t.Errorf("expected bytes %x, got %x", test.bytes, actual)
+			}
+			if actual := test.value.SignBit(); actual != test.signBit {
+				t.Errorf("expected sign bit %v, got %v", test.signBit, actual)
+			}
+			if actual := test.value.Exponent(); actual != test.exponent {
+				t.Errorf("expected exponent %#x, got %#x", test.exponent, actual)
+			}
+			if actual := test.value.MantissaBits(); !bytes.Equal(actual, test.mantissaBits) {
+				t.Errorf("expected mantissa bits %x, got %x", test.mantissaBits, actual)
+			}
+		})
+	}
+}
+
+func TestFloat128Equality(t *testing.T) {
+	one, two := testFloat128(1), testFloat128(2)
+	zero, negativeZero := testFloat128(0), testFloat128(math.Copysign(0, -1))
+	nan := testFloat128(math.NaN())
+	otherNaN := nan
+	otherNaN[0] = 1
+
+	tests := []struct {
+		name     string
+		a, b     Float128
+		equals   bool
+		sameBits bool
+	}{
+		{"same value", one, one, true, true},
+		{"different values", one, two, false, false},
+		{"signed zeros", zero, negativeZero, true, false},
+		{"NaN", nan, nan, false, true},
+		{"different NaNs", nan, otherNaN, false, false},
+		{"NaN and number", nan, one, false, false},
+		{"infinity", testFloat128(math.Inf(1)), testFloat128(math.Inf(1)), true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.a.Equals(test.b); actual != test.equals {
+				t.Errorf("expected Equals to be %v, got %v", test.equals, actual)
+			}
+			if actual := test.a.SameBits(test.b); actual != test.sameBits {
+				t.Errorf("expected SameBits to be %v, got %v", test.sameBits, actual)
+			}
+			if test.equals && test.a.Canonical() != test.b.Canonical() {
+				t.Errorf("expected equal values to have the same canonical form")
+			}
+		})
+	}
+
+	if nan.Canonical() != otherNaN.Canonical() {
+		t.Error("expected all NaNs to have the same canonical form")
+	}
+	if !math.IsNaN(nan.Canonical().AsFloat64()) {
+		t.Error("expected canonical NaN to be NaN")
+	}
+
+	counts := map[Float128]int{}
+	for _, f := range []Float128{zero, negativeZero, one} {
+		counts[f.Canonical()]++
+	}
+	if counts[zero] != 2 || counts[one] != 1 {
+		t.Errorf("expected canonical forms to bucket signed zeros together, got %v", counts)
+	}
+}
+
+func TestTimestampMapKey(t *testing.T) {
+	a := Timestamp{Timestamp: 3788905723, Remainder: 1 << 63}
+	b := Timestamp{Timestamp: 3788905723, Remainder: 1<<63 + 1}
+
+	counts := map[Timestamp]int{}
+	for _, ts := range []Timestamp{a, b, a} {
+		counts[ts]++
+	}
+
+	if counts[a] != 2 || counts[b] != 1 {
+		t.Errorf("expected timestamps to be compared at full precision, got %v", counts)
+	}
+}
+
+func TestTimestampNanoseconds(t *testing.T) {
+	// reference rounds remainder * 10^9 / 2^64 to the nearest integer using
+	// arbitrary precision arithmetic.
+	reference := func(remainder uint64) int64 {
+		ns := new(big.Int).SetUint64(remainder)
+		ns.Mul(ns, big.NewInt(1e9))
+		ns.Add(ns, new(big.Int).Lsh(big.NewInt(1), 63))
+		return ns.Rsh(ns, 64).Int64()
+	}
+
+	// floatNanoseconds is the floating point alternative to the exact
+	// calculation.
+	floatNanoseconds := func(remainder uint64) int64 {
+		return int64(math.Round(float64(remainder) * 0x1p-64 * 1e9))
+	}
+
+	tests := []struct {
+		remainder uint64
+		expected  int64
+	}{
+		{0, 0},
+		{1, 0},
+		{1 << 63, 500_000_000},
+		{1<<64 - 1, 1_000_000_000},
+	}
+
+	for _, test := range tests {
+		ts := Timestamp{Remainder: test.remainder}
+		if actual := ts.nanoseconds(); actual != test.expected {
+			t.Errorf("remainder %d: expected %d ns, got %d", test.remainder, test.expected, actual)
+		}
+		if actual := floatNanoseconds(test.remainder); actual != test.expected {
+			t.Errorf("remainder %d: expected floating point method to give %d ns, got %d", test.remainder, test.expected, actual)
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 10000 {
+		remainder := rng.Uint64()
+
+		ts := Timestamp{Remainder: remainder}
+		expected := reference(remainder)
+		if actual := ts.nanoseconds(); actual != expected {
+			t.Fatalf("remainder %d: expected %d ns, got %d", remainder, expected, actual)
+		}
+
+		// The floating point method is never more than a nanosecond out.
+		if diff := floatNanoseconds(remainder) - expected; diff < -1 || diff > 1 {
+			t.Fatalf("remainder %d: floating point method is %d ns out", remainder, diff)
+		}
+	}
+
+	// This is just below 1659.5 ns, but the floating point product rounds to
+	// exactly 1659.5 ns, which then rounds up.
+	remainder := uint64(30612371790321)
+	if ns := (&Timestamp{Remainder: remainder}).nanoseconds(); ns != 1659 {
+		t.Errorf("remainder %d: expected 1659 ns, got %d", remainder, ns)
+	}
+	if ns := floatNanoseconds(remainder); ns != 1660 {
+		t.Errorf("remainder %d: expected floating point method to round the wrong way, got %d", remainder, ns)
+	}
+}
+
+func TestTimestampAsTimeRounds(t *testing.T) {
+	epoch := time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// 0.6 ns must round up to 1 ns rather than being truncated to 0 ns.
+	sixTenthsOfANanosecond := uint64(11068046444)
+
+	tests := []struct {
+		name      string
+		timestamp Timestamp
+		expected  time.Time
+	}{
+		{"rounds down", Timestamp{Remainder: sixTenthsOfANanosecond / 2}, epoch},
+		{"rounds up", Timestamp{Remainder: sixTenthsOfANanosecond}, epoch.Add(time.Nanosecond)},
+		{"rounds up into next second", Timestamp{Timestamp: 1, Remainder: 1<<64 - 1}, epoch.Add(2 * time.Second)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.timestamp.AsTime(); !actual.Equal(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []Timestamp{tests[1].timestamp}},
+		},
+	})
+
+	values, err := testChannel(t, f, "group", "channel").ReadDataTimeAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 1 || !values[0].Equal(tests[1].expected) {
+		t.Errorf("expected channel data to be rounded to %v, got %v", tests[1].expected, values)
+	}
+}
+
+func TestSupportedDataTypes(t *testing.T) {
+	dataTypes := SupportedDataTypes()
+
+	for _, unsupported := range []DataType{DataTypeVoid, DataTypeFixedPoint, DataTypeDAQmxRawData} {
+		if slices.Contains(dataTypes, unsupported) {
+			t.Errorf("expected %s not to be supported", unsupported)
+		}
+	}
+
+	if !slices.IsSorted(dataTypes) {
+		t.Errorf("expected data types to be sorted, got %v", dataTypes)
+	}
+
+	// Callers mustn't be able to change the result for others.
+	dataTypes[0] = DataTypeFixedPoint
+	if SupportedDataTypes()[0] != DataTypeInt8 {
+		t.Errorf("expected modifying result to have no effect")
+	}
+}
+
+func TestNewTimestampFromTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected Timestamp
+	}{
+		{"TDMS epoch", time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC), Timestamp{}},
+		{"Unix epoch", time.Unix(0, 0), Timestamp{Timestamp: tdmsEpochOffset}},
+		{"half a second", time.Unix(1, 5e8), Timestamp{Timestamp: tdmsEpochOffset + 1, Remainder: 1 << 63}},
+		{"before TDMS epoch", time.Date(1903, time.December, 31, 23, 59, 59, 75e7, time.UTC), Timestamp{Timestamp: -1, Remainder: 3 << 62}},
+		{"other location", time.Date(1904, time.January, 1, 1, 0, 0, 0, time.FixedZone("UTC+1", 3600)), Timestamp{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := NewTimestampFromTime(test.time); actual != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 10000 {
+		expected := time.Unix(rng.Int64N(1<<33)-1<<32, rng.Int64N(1e9)).UTC()
+		timestamp := NewTimestampFromTime(expected)
+		if actual := timestamp.AsTime(); !actual.Equal(expected) {
+			t.Fatalf("expected %v to round trip, got %v", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// DecodeValues decodes numValues values of the given data type from a buffer of
+// raw data, laid out exactly as it would be in a single chunk of a TDMS file.
+// This is useful when you already have raw TDMS data from elsewhere, e.g. a
+// DAQmx buffer which you are decoding manually, or when testing interpretation
+// of values in isolation.
+//
+// Fixed-size values are read consecutively from the start of data. Strings are
+// expected in the TDMS raw data format, i.e. numValues uint32 offsets to the
+// end of each string followed by the concatenated string data. Any data beyond
+// the last value is ignored.
+//
+// The values are returned with the same Go types as [Property.Value]. Returns
+// io.ErrUnexpectedEOF if data is too short to hold numValues values and
+// ErrUnsupportedType if the data type cannot be decoded.
+func DecodeValues(data []byte, dataType DataType, order binary.ByteOrder, numValues int) ([]any, error) {
+	if numValues < 0 {
+		return nil, fmt.Errorf("number of values must not be negative, got %d", numValues)
+	}
+
+	dataType = dataType.baseType()
+
+	if dataType == DataTypeString {
+		return decodeStrings(data, order, numValues)
+	}
+
+	dataSize := dataType.Size()
+	if dataSize == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, dataType)
+	}
+
+	if len(data)/dataSize < numValues {
+		return nil, fmt.Errorf(
+			"%w: %d values of type %s need %d bytes but only %d are available",
+			io.ErrUnexpectedEOF,
+			numValues,
+			dataType,
+			numValues*dataSize,
+			len(data),
+		)
+	}
+
+	values := make([]any, numValues)
+	for i := range values {
+		values[i] = interpretValue(dataType, data[i*dataSize:(i+1)*dataSize], order)
+	}
+
+	return values, nil
+}
+
+func decodeStrings(data []byte, order binary.ByteOrder, numValues int) ([]any, error) {
+	if len(data)/4 < numValues {
+		return nil, fmt.Errorf(
+			"%w: %d string offsets need %d bytes but only %d are available",
+			io.ErrUnexpectedEOF,
+			numValues,
+			numValues*4,
+			len(data),
+		)
+	}
+
+	strData := data[numValues*4:]
+
+	values := make([]any, numValues)
+	start := uint32(0)
+	for i := range values {
+		end := order.Uint32(data[i*4:])
+		if end < start {
+			return nil, fmt.Errorf("%w: string offset %d is before previous offset %d", ErrInvalidFileFormat, end, start)
+		}
+		if uint64(end) > uint64(len(strData)) {
+			return nil, fmt.Errorf(
+				"%w: string offset %d is beyond the %d bytes of string data",
+				io.ErrUnexpectedEOF,
+				end,
+				len(strData),
+			)
+		}
+
+		values[i] = interpretString(strData[start:end], order)
+		start = end
+	}
+
+	return values, nil
+}
+
+// interpretValue interprets a single fixed-size value of the given data type.
+// The data type must have a non-zero size.
+func interpretValue(dataType DataType, bytes []byte, order binary.ByteOrder) any {
+	switch dataType {
+	case DataTypeInt8:
+		return interpretInt8(bytes, order)
+	case DataTypeInt16:
+		return interpretInt16(bytes, order)
+	case DataTypeInt32:
+		return interpretInt32(bytes, order)
+	case DataTypeInt64:
+		return interpretInt64(bytes, order)
+	case DataTypeUint8:
+		return interpretUint8(bytes, order)
+	case DataTypeUint16:
+		return interpretUint16(bytes, order)
+	case DataTypeUint32:
+		return interpretUint32(bytes, order)
+	case DataTypeUint64:
+		return interpretUint64(bytes, order)
+	case DataTypeFloat32:
+		return interpretFloat32(bytes, order)
+	case DataTypeFloat64:
+		return interpretFloat64(bytes, order)
+	case DataTypeFloat128:
+		return interpretFloat128(bytes, order)
+	case DataTypeBool:
+		return interpretBool(bytes, order)
+	case DataTypeTimestamp:
+		return interpretTimestamp(bytes, order)
+	case DataTypeComplex64:
+		return interpretComplex64(bytes, order)
+	case DataTypeComplex128:
+		return interpretComplex128(bytes, order)
+	default:
+		panic(fmt.Sprintf("cannot interpret value of type %s", dataType))
+	}
+}
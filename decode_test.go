@@ -0,0 +1,103 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestDecodeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   any
+		dataType DataType
+		expected []any
+	}{
+		{"int16", []int16{-1, 2}, DataTypeInt16, []any{int16(-1), int16(2)}},
+		{"uint64", []uint64{1, 2}, DataTypeUint64, []any{uint64(1), uint64(2)}},
+		{"float64", []float64{0.5, -2}, DataTypeFloat64, []any{0.5, -2.0}},
+		{"float64 with unit", []float64{0.5, -2}, DataTypeFloat64WithUnit, []any{0.5, -2.0}},
+		{"bool", []bool{true, false}, DataTypeBool, []any{true, false}},
+		{"timestamp", []Timestamp{{Timestamp: 5, Remainder: 7}}, DataTypeTimestamp, []any{Timestamp{Timestamp: 5, Remainder: 7}}},
+		{"complex128", []complex128{1 + 2i}, DataTypeComplex128, []any{1 + 2i}},
+		{"string", []string{"hello", "", "world"}, DataTypeString, []any{"hello", "", "world"}},
+	}
+
+	for _, tt := range tests {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			buf := &bytes.Buffer{}
+			writeTestValues(buf, order, tt.values, 0, len(tt.expected))
+
+			values, err := DecodeValues(buf.Bytes(), tt.dataType, order, len(tt.expected))
+			if err != nil {
+				t.Fatalf("%s %v: unexpected error: %v", tt.name, order, err)
+			}
+
+			if !slices.Equal(values, tt.expected) {
+				t.Errorf("%s %v: expected %v, got %v", tt.name, order, tt.expected, values)
+			}
+		}
+	}
+}
+
+func TestDecodeValuesBounds(t *testing.T) {
+	order := binary.LittleEndian
+
+	if _, err := DecodeValues(make([]byte, 15), DataTypeFloat64, order, 2); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF for short buffer, got %v", err)
+	}
+
+	if _, err := DecodeValues(nil, DataTypeFloat64, order, -1); err == nil {
+		t.Errorf("expected error for negative number of values")
+	}
+
+	if _, err := DecodeValues(make([]byte, 16), DataTypeFixedPoint, order, 1); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	writeTestValues(buf, order, []string{"abc", "de"}, 0, 2)
+	data := buf.Bytes()
+
+	if _, err := DecodeValues(data[:len(data)-1], DataTypeString, order, 2); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF for truncated strings, got %v", err)
+	}
+
+	if _, err := DecodeValues(data[:4], DataTypeString, order, 2); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF for truncated offsets, got %v", err)
+	}
+
+	// Offsets must never decrease.
+	order.PutUint32(data[4:], 1)
+	if _, err := DecodeValues(data, DataTypeString, order, 2); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("expected ErrInvalidFileFormat for decreasing offsets, got %v", err)
+	}
+}
+
+func TestDecodeValuesLeavesInputUnchanged(t *testing.T) {
+	order := binary.BigEndian
+
+	buf := &bytes.Buffer{}
+	writeTestValues(buf, order, []Float128{testFloat128(1.5), testFloat128(-3)}, 0, 2)
+	data := buf.Bytes()
+	original := slices.Clone(data)
+
+	// Decoding the same buffer twice must give the same values.
+	for range 2 {
+		values, err := DecodeValues(data, DataTypeFloat128, order, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if expected := []any{testFloat128(1.5), testFloat128(-3)}; !slices.Equal(values, expected) {
+			t.Errorf("expected %v, got %v", expected, values)
+		}
+	}
+
+	if !bytes.Equal(data, original) {
+		t.Errorf("input buffer was modified: expected %x, got %x", original, data)
+	}
+}
@@ -0,0 +1,258 @@
+package tdms
+
+// Digital channels written by NI-DAQmx and LabVIEW store the state of one or
+// more digital lines in each sample, with line 0 in the least significant bit.
+// The names of the lines are stored in the "NI_LineNames" property as a
+// comma-separated list.
+//
+// SignalExpress, which wrote testdata/digital_input.tdms, writes each line of a
+// digital signal to its own Uint8 channel of 0s and 1s, with the position of
+// the line in the signal in the "LineNumber" property and the number of lines
+// in the signal in "SignalWidth". Only the full rate channel has NI_LineNames,
+// while the decimated channels just have LineNumber. DAQmx raw data channels
+// instead have a digital line scaler for each of their lines.
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+const (
+	lineNamesProperty  = "NI_LineNames"
+	lineNumberProperty = "LineNumber"
+)
+
+// DigitalLineNames returns the names of the digital lines stored in this
+// channel, in bit order. A channel with no line names which holds a single line
+// of a digital signal, as given by its LineNumber property, has a single line
+// named after the channel. Returns ErrMissingProperty if the channel has
+// neither property, which usually means that it is not a digital channel.
+func (ch *Channel) DigitalLineNames() ([]string, error) {
+	prop, ok := ch.Properties[lineNamesProperty]
+	if !ok {
+		if _, ok := ch.Properties[lineNumberProperty]; ok {
+			return []string{ch.Name}, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrMissingProperty, lineNamesProperty)
+	}
+
+	value, err := prop.AsString()
+	if err != nil {
+		return nil, err
+	}
+
+	names := strings.Split(value, ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+	}
+
+	return names, nil
+}
+
+// ReadDigitalLines reads all samples from a digital channel and splits them
+// into the state of each line, keyed by line name as given by
+// [Channel.DigitalLineNames].
+//
+// The channel must have an integer or bool data type, or hold DAQmx raw data
+// with a digital line scaler for each line, which is read as by
+// [Channel.ReadDAQmxRawData]. Returns ErrIncorrectType otherwise, or if the
+// channel can't hold all the named lines.
+func (ch *Channel) ReadDigitalLines(options ...ReadOption) (map[string][]bool, error) {
+	names, err := ch.DigitalLineNames()
+	if err != nil {
+		return nil, err
+	}
+
+	if ch.DataType == DataTypeDAQmxRawData {
+		return ch.readDAQmxDigitalLines(names, options)
+	}
+
+	var samples []uint64
+	switch ch.DataType {
+	case DataTypeInt8, DataTypeUint8:
+		samples, err = readAllData(ch, options, ch.DataType, func(bytes []byte, order binary.ByteOrder) uint64 {
+			return uint64(interpretUint8(bytes, order))
+		})
+	case DataTypeInt16, DataTypeUint16:
+		samples, err = readAllData(ch, options, ch.DataType, func(bytes []byte, order binary.ByteOrder) uint64 {
+			return uint64(interpretUint16(bytes, order))
+		})
+	case DataTypeInt32, DataTypeUint32:
+		samples, err = readAllData(ch, options, ch.DataType, func(bytes []byte, order binary.ByteOrder) uint64 {
+			return uint64(interpretUint32(bytes, order))
+		})
+	case DataTypeInt64, DataTypeUint64:
+		samples, err = readAllData(ch, options, ch.DataType, interpretUint64)
+	case DataTypeBool:
+		samples, err = readAllData(ch, options, ch.DataType, func(bytes []byte, order binary.ByteOrder) uint64 {
+			if interpretBool(bytes, order) {
+				return 1
+			}
+			return 0
+		})
+	default:
+		return nil, fmt.Errorf("%w: cannot read digital lines from %s channel", ErrIncorrectType, ch.DataType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	numBits := ch.DataType.Size() * 8
+	if ch.DataType == DataTypeBool {
+		numBits = 1
+	}
+
+	if len(names) > numBits {
+		return nil, fmt.Errorf(
+			"%w: %s channel cannot hold %d digital lines",
+			ErrIncorrectType,
+			ch.DataType,
+			len(names),
+		)
+	}
+
+	lines := make(map[string][]bool, len(names))
+	for bit, name := range names {
+		line := make([]bool, len(samples))
+		for i, sample := range samples {
+			line[i] = sample&(1<<bit) != 0
+		}
+		lines[name] = line
+	}
+
+	return lines, nil
+}
+
+// readDAQmxDigitalLines reads the lines of a DAQmx raw data channel, taking
+// each named line from the digital line scaler in the same position in the
+// channel's raw data index.
+func (ch *Channel) readDAQmxDigitalLines(names []string, options []ReadOption) (map[string][]bool, error) {
+	obj, ok := ch.f.objects[ch.path]
+	if !ok || obj.index == nil || obj.index.scalerType != daqmxScalerTypeDigitalLine {
+		return nil, fmt.Errorf("%w: DAQmx channel %s doesn't have digital line scalers", ErrIncorrectType, ch.path)
+	}
+
+	scalers := obj.index.scalers
+	if len(names) > len(scalers) {
+		return nil, fmt.Errorf(
+			"%w: DAQmx channel with %d digital line scalers cannot hold %d digital lines",
+			ErrIncorrectType,
+			len(scalers),
+			len(names),
+		)
+	}
+
+	data, err := ch.ReadDAQmxRawData(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[string][]bool, len(names))
+	for i, name := range names {
+		values := data[scalers[i].scaleID]
+		line := make([]bool, len(values))
+		for j, value := range values {
+			line[j] = value != 0
+		}
+		lines[name] = line
+	}
+
+	return lines, nil
+}
+
+// Bitset is a compact sequence of bools, stored 8 per byte, as returned by
+// [Channel.ReadDataBoolBitset].
+type Bitset struct {
+	words []uint64
+	n     uint64
+}
+
+// NewBitset returns a bitset of n bools which are all false.
+func NewBitset(n uint64) *Bitset {
+	return &Bitset{
+		words: make([]uint64, (n+63)/64),
+		n:     n,
+	}
+}
+
+// Len returns the number of bools in the bitset.
+func (b *Bitset) Len() uint64 {
+	return b.n
+}
+
+// Get returns the bool at index i. It panics if i is out of range.
+func (b *Bitset) Get(i uint64) bool {
+	if i >= b.n {
+		panic(fmt.Sprintf("bitset index %d out of range with length %d", i, b.n))
+	}
+
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Set sets the bool at index i. It panics if i is out of range.
+func (b *Bitset) Set(i uint64, value bool) {
+	if i >= b.n {
+		panic(fmt.Sprintf("bitset index %d out of range with length %d", i, b.n))
+	}
+
+	if value {
+		b.words[i/64] |= 1 << (i % 64)
+	} else {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Count returns the number of bools in the bitset which are true.
+func (b *Bitset) Count() uint64 {
+	count := 0
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+
+	return uint64(count)
+}
+
+// ReadDataBoolBitset reads all values from a bool channel into a [Bitset],
+// which uses an eighth of the memory of the []bool returned by
+// [Channel.ReadDataBoolAll]. Returns ErrIncorrectType if the channel isn't a
+// bool channel.
+//
+// If the file contains fewer values than it declares, the values which were
+// read are returned along with an error wrapping ErrLengthMismatch, and the
+// rest of the bitset is false.
+func (ch *Channel) ReadDataBoolBitset(options ...ReadOption) (*Bitset, error) {
+	if ch.DataType != DataTypeBool {
+		return nil, fmt.Errorf("%w: cannot read %s channel as bools", ErrIncorrectType, ch.DataType)
+	}
+
+	bitset := NewBitset(ch.totalNumValues)
+
+	i := uint64(0)
+	for batch, err := range ch.ReadDataAsBoolBatch(options...) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range batch {
+			if value {
+				bitset.words[i/64] |= 1 << (i % 64)
+			}
+			i++
+		}
+	}
+
+	if i != ch.totalNumValues {
+		return bitset, fmt.Errorf(
+			"%w: channel %s declares %d values but only %d could be read",
+			ErrLengthMismatch,
+			ch.path,
+			ch.totalNumValues,
+			i,
+		)
+	}
+
+	return bitset, nil
+}
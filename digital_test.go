@@ -0,0 +1,230 @@
+package tdms
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadDigitalLines(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'port'",
+				values: []uint8{0b000, 0b001, 0b110, 0b111},
+				props: []Property{
+					{Name: "NI_LineNames", TypeCode: DataTypeString, Value: "Dev1/port0/line0, Dev1/port0/line1, Dev1/port0/line2"},
+				},
+			},
+			{path: "/'group'/'analog'", values: []float64{1, 2, 3, 4}},
+		},
+	})
+
+	ch := testChannel(t, f, "group", "port")
+
+	names, err := ch.DigitalLineNames()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedNames := []string{"Dev1/port0/line0", "Dev1/port0/line1", "Dev1/port0/line2"}
+	if !slices.Equal(names, expectedNames) {
+		t.Fatalf("expected line names %v, got %v", expectedNames, names)
+	}
+
+	lines, err := ch.ReadDigitalLines()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedLines := map[string][]bool{
+		"Dev1/port0/line0": {false, true, false, true},
+		"Dev1/port0/line1": {false, false, true, true},
+		"Dev1/port0/line2": {false, false, true, true},
+	}
+
+	for name, expected := range expectedLines {
+		if !slices.Equal(lines[name], expected) {
+			t.Errorf("line %s: expected %v, got %v", name, expected, lines[name])
+		}
+	}
+
+	analog := testChannel(t, f, "group", "analog")
+	if _, err := analog.ReadDigitalLines(); !errors.Is(err, ErrMissingProperty) {
+		t.Errorf("expected ErrMissingProperty for analog channel, got %v", err)
+	}
+}
+
+func TestReadDigitalLinesTestData(t *testing.T) {
+	f, err := Open("testdata/digital_input.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	// SignalExpress writes each line to its own channel of 0s and 1s, and
+	// only the full rate channel has line names.
+	tests := []struct {
+		group    string
+		lineName string
+		count    int
+	}{
+		{"07/09/2012 06:58:23 PM - Digital Input - All Data", "Dev1/port3/line7", 20000},
+		{"07/09/2012 06:58:23 PM - Digital Input - Decimated Data_Level2", "Dev1_port3_line7 - line 0", 8},
+	}
+
+	for _, tt := range tests {
+		ch := testChannel(t, f, tt.group, "Dev1_port3_line7 - line 0")
+
+		names, err := ch.DigitalLineNames()
+		if err != nil || !slices.Equal(names, []string{tt.lineName}) {
+			t.Errorf("%s: expected line names [%s], got %v (%v)", tt.group, tt.lineName, names, err)
+		}
+
+		lines, err := ch.ReadDigitalLines()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.group, err)
+		}
+
+		if len(lines[tt.lineName]) != tt.count {
+			t.Errorf("%s: expected %d samples, got %d", tt.group, tt.count, len(lines[tt.lineName]))
+		}
+	}
+
+	ch := testChannel(t, f, tests[0].group, "Dev1_port3_line7 - line 0")
+	lines, err := ch.ReadDigitalLines()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []bool{false, true, false, true}; !slices.Equal(lines["Dev1/port3/line7"][:4], expected) {
+		t.Errorf("expected line to start with %v, got %v", expected, lines["Dev1/port3/line7"][:4])
+	}
+}
+
+func TestReadDigitalLinesDAQmx(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path: "/'group'/'port'",
+				daqmx: &testDAQmx{
+					numValues: 3,
+					widths:    []uint32{1},
+					scalers: []daqmxScaler{
+						{dataType: DataTypeUint8, rawByteOffsetWithinStride: 0, scaleID: 0},
+						{dataType: DataTypeUint8, rawByteOffsetWithinStride: 2, scaleID: 1},
+					},
+					digital: true,
+				},
+				props: []Property{
+					{Name: "NI_LineNames", TypeCode: DataTypeString, Value: "line0, line2"},
+				},
+			},
+			{
+				path: "/'group'/'too few scalers'",
+				daqmx: &testDAQmx{
+					numValues: 3,
+					widths:    []uint32{1},
+					scalers:   []daqmxScaler{{dataType: DataTypeUint8}},
+					digital:   true,
+				},
+				props: []Property{
+					{Name: "NI_LineNames", TypeCode: DataTypeString, Value: "line0, line1"},
+				},
+			},
+		},
+		// Both channels read from the same raw buffer.
+		daqmxData: []byte{0b001, 0b100, 0b101},
+	})
+
+	lines, err := testChannel(t, f, "group", "port").ReadDigitalLines()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][]bool{
+		"line0": {true, false, true},
+		"line2": {false, true, true},
+	}
+	for name, values := range expected {
+		if !slices.Equal(lines[name], values) {
+			t.Errorf("line %s: expected %v, got %v", name, values, lines[name])
+		}
+	}
+
+	if _, err := testChannel(t, f, "group", "too few scalers").ReadDigitalLines(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestReadDataBoolBitset(t *testing.T) {
+	values := make([]bool, 130)
+	for i := range values {
+		values[i] = i%3 == 0
+	}
+
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'bools'", values: values},
+				{path: "/'group'/'ints'", values: []int32{1}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'bools'", values: []bool{true, false}},
+			},
+		},
+	)
+	expected := append(slices.Clone(values), true, false)
+
+	for _, batchSize := range []int{1, 7, 1024} {
+		bitset, err := testChannel(t, f, "group", "bools").ReadDataBoolBitset(BatchSize(batchSize))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if bitset.Len() != uint64(len(expected)) {
+			t.Fatalf("expected length %d, got %d", len(expected), bitset.Len())
+		}
+
+		count := uint64(0)
+		for i, value := range expected {
+			if bitset.Get(uint64(i)) != value {
+				t.Errorf("batch size %d: expected value %d to be %v", batchSize, i, value)
+			}
+			if value {
+				count++
+			}
+		}
+
+		if bitset.Count() != count {
+			t.Errorf("batch size %d: expected count %d, got %d", batchSize, count, bitset.Count())
+		}
+	}
+
+	if _, err := testChannel(t, f, "group", "ints").ReadDataBoolBitset(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestBitset(t *testing.T) {
+	bitset := NewBitset(70)
+	bitset.Set(0, true)
+	bitset.Set(69, true)
+	bitset.Set(64, true)
+	bitset.Set(64, false)
+
+	if !bitset.Get(0) || !bitset.Get(69) || bitset.Get(64) || bitset.Count() != 2 {
+		t.Errorf("unexpected bitset state %v", bitset.words)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for out of range index")
+		}
+	}()
+	bitset.Get(70)
+}
@@ -0,0 +1,53 @@
+package tdms
+
+// LabVIEW and DAQmx write hints for displaying a channel in these standard
+// properties.
+const (
+	displayNameProperty  = "NI_ChannelName"
+	displayUnitProperty  = "NI_UnitDescription"
+	displayXNameProperty = "wf_xname"
+	displayXUnitProperty = "wf_xunit_string"
+)
+
+// DisplayInfo holds the hints for displaying a channel, e.g. when labelling
+// the axes of a plot, taken from its standard display properties.
+type DisplayInfo struct {
+	// Name is the name to display for the channel, from the NI_ChannelName
+	// property. If the property isn't present, this is the channel's name.
+	Name string
+
+	// Unit is the unit of the channel's values, from the NI_UnitDescription
+	// property. If the property isn't present, this is the unit given by
+	// [Channel.Unit], or empty if there is none.
+	Unit string
+
+	// XName is the name of the x-axis, from the wf_xname property, e.g.
+	// "Time". This is empty if the property isn't present.
+	XName string
+
+	// XUnit is the unit of the x-axis, from the wf_xunit_string property, e.g.
+	// "s". This is empty if the property isn't present.
+	XUnit string
+}
+
+// DisplayInfo returns the hints for displaying this channel. Properties which
+// aren't present, or aren't strings, are left empty unless there is a
+// fallback, as described on the fields of [DisplayInfo].
+func (ch *Channel) DisplayInfo() DisplayInfo {
+	info := DisplayInfo{Name: ch.Name}
+
+	if name, ok := ch.GetTyped(displayNameProperty).AsString(); ok {
+		info.Name = name
+	}
+
+	if unit, ok := ch.GetTyped(displayUnitProperty).AsString(); ok {
+		info.Unit = unit
+	} else if unit, ok := ch.Unit(); ok {
+		info.Unit = unit
+	}
+
+	info.XName, _ = ch.GetTyped(displayXNameProperty).AsString()
+	info.XUnit, _ = ch.GetTyped(displayXUnitProperty).AsString()
+
+	return info
+}
@@ -0,0 +1,47 @@
+package tdms
+
+import "testing"
+
+func TestDisplayInfo(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'full'",
+				values: []float64{1},
+				props: []Property{
+					{Name: "NI_ChannelName", TypeCode: DataTypeString, Value: "Pressure"},
+					{Name: "NI_UnitDescription", TypeCode: DataTypeString, Value: "kPa"},
+					{Name: "unit_string", TypeCode: DataTypeString, Value: "Pa"},
+					{Name: "wf_xname", TypeCode: DataTypeString, Value: "Time"},
+					{Name: "wf_xunit_string", TypeCode: DataTypeString, Value: "s"},
+				},
+			},
+			{
+				path:   "/'group'/'fallback'",
+				values: []float64{1},
+				props: []Property{
+					{Name: "unit_string", TypeCode: DataTypeString, Value: "V"},
+					{Name: "wf_xname", TypeCode: DataTypeInt32, Value: int32(1)},
+				},
+			},
+			{path: "/'group'/'plain'", values: []float64{1}},
+		},
+	})
+
+	tests := []struct {
+		channel  string
+		expected DisplayInfo
+	}{
+		{"full", DisplayInfo{Name: "Pressure", Unit: "kPa", XName: "Time", XUnit: "s"}},
+		{"fallback", DisplayInfo{Name: "fallback", Unit: "V"}},
+		{"plain", DisplayInfo{Name: "plain"}},
+	}
+
+	for _, tt := range tests {
+		info := testChannel(t, f, "group", tt.channel).DisplayInfo()
+		if info != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.channel, tt.expected, info)
+		}
+	}
+}
@@ -134,8 +134,10 @@
 //
 // When opening a [File] from a filename with [File.Open], the file is
 // determined to be an index file (i.e. containing all metadata and no raw data)
-// if the filename ends with `.tdms_index`. Otherwise, it's supposed to be a
-// standard TDMS file with data in.
+// from the magic bytes at the start of the file. To require one or the other,
+// use [OpenWith] with the [AsIndex] option:
+//
+//	file, err := tdms.OpenWith("data.idx", tdms.AsIndex(true))
 //
 // As well as opening files with [File.Open], you can also open files with
 // [File.New], passing any type that implements the `io.ReadSeeker` interface
@@ -0,0 +1,150 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+)
+
+// Document is the whole contents of a TDMS file loaded into memory, as
+// returned by [File.ReadAll].
+type Document struct {
+	// Properties are the properties of the file itself.
+	Properties map[string]Property
+
+	// Groups maps the name of each group to its contents.
+	Groups map[string]DocumentGroup
+}
+
+// DocumentGroup is the contents of a single group in a [Document].
+type DocumentGroup struct {
+	Properties map[string]Property
+
+	// Channels maps the name of each channel in the group to its contents.
+	Channels map[string]DocumentChannel
+}
+
+// DocumentChannel is the contents of a single channel in a [Document].
+type DocumentChannel struct {
+	DataType   DataType
+	Properties map[string]Property
+
+	// Data holds every value of the channel, with the same Go types as
+	// [Property.Value], e.g. float64 for a [DataTypeFloat64] channel.
+	Data []any
+}
+
+type readAllOptions struct {
+	maxValues   uint64
+	readOptions []ReadOption
+}
+
+// ReadAllOption configures how a file is loaded by [File.ReadAll].
+type ReadAllOption func(*readAllOptions)
+
+// MaxValues limits the total number of values across all channels that
+// [File.ReadAll] will load. If the file has more values than this, ReadAll
+// fails before reading any data. Zero means no limit, which is the default.
+func MaxValues(n uint64) ReadAllOption {
+	return func(opts *readAllOptions) {
+		opts.maxValues = n
+	}
+}
+
+// ReadAllData sets the read options used when [File.ReadAll] reads the data of
+// each channel.
+func ReadAllData(options ...ReadOption) ReadAllOption {
+	return func(opts *readAllOptions) {
+		opts.readOptions = options
+	}
+}
+
+// ReadAll loads every property and every channel's data into a [Document].
+// This is convenient for scripting and exploring small to medium files, but
+// each value is boxed in an interface, taking several times as much memory as
+// the raw data, so it is not suitable for large files. Use [MaxValues] to
+// guard against unexpectedly large files, or the ReadData methods of [Channel]
+// to stream the data instead.
+//
+// Returns ErrUnsupportedType if any channel has a data type which can't be
+// read, ErrMetadataOnly if the file was opened with the MetadataOnly option,
+// or ErrTooManyValues if the file has more values than the limit set by
+// MaxValues.
+func (t *File) ReadAll(options ...ReadAllOption) (*Document, error) {
+	opts := readAllOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if t.opts.metadataOnly {
+		return nil, ErrMetadataOnly
+	}
+
+	if opts.maxValues > 0 {
+		total := uint64(0)
+		for _, group := range t.Groups {
+			for _, ch := range group.Channels {
+				total += ch.NumValues()
+			}
+		}
+
+		if total > opts.maxValues {
+			return nil, fmt.Errorf("%w: file has %d values, more than the limit of %d", ErrTooManyValues, total, opts.maxValues)
+		}
+	}
+
+	doc := &Document{
+		Properties: maps.Clone(t.Properties),
+		Groups:     make(map[string]DocumentGroup, len(t.Groups)),
+	}
+
+	for groupName, group := range t.Groups {
+		docGroup := DocumentGroup{
+			Properties: maps.Clone(group.Properties),
+			Channels:   make(map[string]DocumentChannel, len(group.Channels)),
+		}
+
+		for channelName, ch := range group.Channels {
+			data, err := ch.ReadDataAnyAll(opts.readOptions...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read channel %s: %w", ch.path, err)
+			}
+
+			docGroup.Channels[channelName] = DocumentChannel{
+				DataType:   ch.DataType,
+				Properties: maps.Clone(ch.Properties),
+				Data:       data,
+			}
+		}
+
+		doc.Groups[groupName] = docGroup
+	}
+
+	return doc, nil
+}
+
+// ReadDataAnyAll reads all values from the channel into a single slice,
+// whatever its data type, with the same Go types as [Property.Value]. Prefer
+// the typed ReadData methods where the data type is known, as boxing each value
+// takes several times as much memory.
+//
+// Returns ErrUnsupportedType if the channel has a data type which can't be
+// read.
+func (ch *Channel) ReadDataAnyAll(options ...ReadOption) ([]any, error) {
+	dataType := ch.DataType
+
+	switch {
+	case dataType == DataTypeString:
+		return readAllData(ch, options, dataType, func(bytes []byte, order binary.ByteOrder) any {
+			return interpretString(bytes, order)
+		})
+	case dataType == DataTypeVoid && ch.totalNumValues == 0:
+		return []any{}, nil
+	case dataType.Size() == 0:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, dataType)
+	default:
+		return readAllData(ch, options, dataType, func(bytes []byte, order binary.ByteOrder) any {
+			return interpretValue(dataType, bytes, order)
+		})
+	}
+}
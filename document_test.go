@@ -0,0 +1,82 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadAll(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "test"}}},
+				{path: "/'group'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+				{path: "/'group'/'floats'", values: []float64{1.5, 2.5}},
+				{path: "/'group'/'strings'", values: []string{"a", "bc"}},
+				{path: "/'group'/'empty'"},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'floats'", values: []float64{3.5}},
+			},
+		},
+	)
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc, err := f.ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if name := doc.Properties["name"].Value; name != "test" {
+		t.Errorf("expected name property test, got %v", name)
+	}
+
+	group, ok := doc.Groups["group"]
+	if !ok {
+		t.Fatalf("expected group in document")
+	}
+
+	if gain := group.Properties["gain"].Value; gain != int32(2) {
+		t.Errorf("expected gain property 2, got %v", gain)
+	}
+
+	expected := map[string][]any{
+		"floats":  {1.5, 2.5, 3.5},
+		"strings": {"a", "bc"},
+		"empty":   {},
+	}
+
+	if len(group.Channels) != len(expected) {
+		t.Errorf("expected %d channels, got %d", len(expected), len(group.Channels))
+	}
+
+	for name, values := range expected {
+		if actual := group.Channels[name].Data; !slices.Equal(actual, values) {
+			t.Errorf("channel %s: expected %v, got %v", name, values, actual)
+		}
+	}
+
+	if dataType := group.Channels["floats"].DataType; dataType != DataTypeFloat64 {
+		t.Errorf("expected float64 channel, got %s", dataType)
+	}
+
+	if _, err := f.ReadAll(MaxValues(4)); !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("expected ErrTooManyValues, got %v", err)
+	}
+
+	if _, err := f.ReadAll(MaxValues(4), ReadAllData(BatchSize(1))); !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("expected ErrTooManyValues, got %v", err)
+	}
+
+	if _, err := f.ReadAll(MaxValues(5), ReadAllData(BatchSize(1))); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
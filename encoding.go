@@ -0,0 +1,60 @@
+package tdms
+
+import "unicode/utf8"
+
+// StringDecoder converts strings from some other encoding to UTF-8. TDMS
+// strings are meant to be UTF-8, but some older writers use other encodings,
+// such as Latin-1, which read as invalid UTF-8.
+//
+// A *Decoder from golang.org/x/text/encoding satisfies this interface, so any
+// encoding from that module can be used, e.g.
+// charmap.Windows1252.NewDecoder(). [Latin1] is provided for the most common
+// case without needing the dependency.
+type StringDecoder interface {
+	// Bytes returns the UTF-8 encoding of the given encoded bytes.
+	Bytes(b []byte) ([]byte, error)
+}
+
+// Latin1 decodes ISO 8859-1 (Latin-1) strings, where each byte is the Unicode
+// code point of the same value.
+var Latin1 StringDecoder = latin1Decoder{}
+
+type latin1Decoder struct{}
+
+func (latin1Decoder) Bytes(b []byte) ([]byte, error) {
+	decoded := make([]byte, 0, len(b))
+	for _, c := range b {
+		decoded = utf8.AppendRune(decoded, rune(c))
+	}
+
+	return decoded, nil
+}
+
+func decodeString(decoder StringDecoder, s string) (string, error) {
+	decoded, err := decoder.Bytes([]byte(s))
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
+
+// StringEncoding decodes the values of string channels using the given
+// decoder, so that strings written in another encoding are read as valid
+// UTF-8. By default, strings are read as-is. Use [PropertyStringEncoding] to
+// decode string properties as well.
+func StringEncoding(decoder StringDecoder) ReadOption {
+	return func(opts *readOptions) {
+		opts.stringDecoder = decoder
+	}
+}
+
+// PropertyStringEncoding decodes the names and values of string properties
+// using the given decoder when the file is opened, so that strings written in
+// another encoding are read as valid UTF-8. By default, strings are read
+// as-is. Use [StringEncoding] to decode string channel data as well.
+func PropertyStringEncoding(decoder StringDecoder) OpenOption {
+	return func(opts *openOptions) {
+		opts.stringDecoder = decoder
+	}
+}
@@ -0,0 +1,61 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type failingDecoder struct{}
+
+func (failingDecoder) Bytes(b []byte) ([]byte, error) {
+	return nil, errors.New("cannot decode")
+}
+
+func TestStringEncoding(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'", props: []Property{
+				{Name: "caf\xe9", TypeCode: DataTypeString, Value: "cr\xe8me br\xfbl\xe9e"},
+			}},
+			{path: "/'group'/'channel'", values: []string{"na\xefve", "plain", "\xbfqu\xe9?"}},
+		},
+	})
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), PropertyStringEncoding(Latin1))
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+
+	if value, err := f.Groups["group"].Properties["café"].AsString(); err != nil || value != "crème brûlée" {
+		t.Errorf("expected decoded property, got %q (%v)", value, err)
+	}
+
+	ch := testChannel(t, f, "group", "channel")
+
+	values, err := ch.ReadDataStringAll(StringEncoding(Latin1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"naïve", "plain", "¿qué?"}; !slices.Equal(values, expected) {
+		t.Errorf("expected %q, got %q", expected, values)
+	}
+
+	// Without the option, the raw bytes are returned.
+	values, err = ch.ReadDataStringAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values[0] != "na\xefve" {
+		t.Errorf("expected raw bytes without decoder, got %q", values[0])
+	}
+
+	if _, err := ch.ReadDataStringAll(StringEncoding(failingDecoder{})); err == nil {
+		t.Error("expected error from failing decoder")
+	}
+
+	if _, err := New(bytes.NewReader(data), false, int64(len(data)), PropertyStringEncoding(failingDecoder{})); err == nil {
+		t.Error("expected error from failing property decoder")
+	}
+}
@@ -12,12 +12,36 @@ var (
 	// ErrInvalidFileFormat indicates that the TDMS file structure is malformed or doesn't conform to the specification.
 	ErrInvalidFileFormat = errors.New("invalid file format")
 
+	// ErrIndexMismatch indicates that a TDMS index file was read as a data file or vice versa.
+	ErrIndexMismatch = errors.New("index file mismatch")
+
 	// ErrInvalidPath indicates that an object path within the TDMS file is not properly formatted.
 	ErrInvalidPath = errors.New("invalid object path")
 
+	// ErrNotFound indicates that a requested group or channel does not exist in the file.
+	ErrNotFound = errors.New("not found")
+
 	// ErrUnsupportedType indicates that the data type encountered is not supported by this library.
 	ErrUnsupportedType = errors.New("unsupported data type")
 
+	// ErrUnsupportedScaling indicates that a channel's scaling cannot be applied, e.g. because it uses the DAQmx advanced API.
+	ErrUnsupportedScaling = errors.New("unsupported scaling")
+
+	// ErrMetadataOnly indicates that channel data was read from a file opened with the MetadataOnly option.
+	ErrMetadataOnly = errors.New("file opened for metadata only")
+
+	// ErrLengthMismatch indicates that the number of values read from a channel differs from the number declared in the file.
+	ErrLengthMismatch = errors.New("channel length mismatch")
+
+	// ErrMissingProperty indicates that a property required for an operation is not present on the object.
+	ErrMissingProperty = errors.New("missing property")
+
 	// ErrIncorrectType indicates that a type assertion or conversion failed because the actual type differs from the expected type.
 	ErrIncorrectType = errors.New("incorrect data type")
+
+	// ErrTooManyValues indicates that loading data would exceed a limit on the number of values set by the caller.
+	ErrTooManyValues = errors.New("too many values")
+
+	// ErrIncompleteFile indicates that a file was incompletely written, when opened with the ErrorOnIncomplete policy.
+	ErrIncompleteFile = errors.New("incomplete file")
 )
@@ -1,11 +1,18 @@
 package tdms
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"log/slog"
 	"maps"
 	"os"
+	"slices"
 	"strings"
+	"time"
 )
 
 // File represents a parsed TDMS file. Use [Open] to open a file by path, or
@@ -14,18 +21,54 @@ type File struct {
 	// Groups contains all groups in the TDMS file, indexed by group name.
 	Groups map[string]Group
 
-	// Properties contains all properties associated with the root file object.
+	// Properties contains all properties associated with the root file object,
+	// i.e. the object with the path "/". These are merged across segments in
+	// the same way as for any other object, so a property set on the root
+	// object in a later segment overrides the value from earlier segments.
+	// This map belongs to the File, so changing it does not affect any other
+	// object in the file.
 	Properties map[string]Property
 
 	// IsIncomplete indicates whether the file was incompletely written, typically
 	// because LabVIEW crashed while writing the final segment.
 	IsIncomplete bool
 
+	// Truncated indicates that reading the metadata stopped before the end of
+	// the file because the number of segments given by [MaxSegments] had been
+	// read. The groups, channels and properties only reflect those segments.
+	Truncated bool
+
 	f        io.ReadSeeker
 	size     int64
 	isIndex  bool
 	segments []segment
 
+	// path is the filename the file was opened from, if any. When the metadata
+	// is read from a separate index file, this is the data file.
+	path string
+
+	// data is the reader that raw data is read from. This is the same as f
+	// unless the metadata is read from a separate index file.
+	data     io.ReadSeeker
+	dataSize int64
+
+	opts openOptions
+
+	// defaultBatchSize is used by readers when no BatchSize option is given.
+	// If zero, a default based on the data type is used.
+	defaultBatchSize int
+
+	// stats are collected while reading the metadata.
+	stats ParseStats
+
+	// If detectIndex is set, isIndex is updated to match the magic bytes of
+	// the first segment rather than requiring them to match.
+	detectIndex bool
+
+	// resume is where the final segment which was read starts, so that the
+	// metadata can be read again from there once more has been written.
+	resume resumePoint
+
 	// This does not hold pointers – we want these to be separate instances from
 	// those held by the individual segment as we want to be able to modify this
 	// independently to represent the object's properties at the top-level
@@ -43,39 +86,478 @@ type Group struct {
 	// Channels contains all channels in this group, indexed by channel name.
 	Channels map[string]Channel
 
-	// Properties contains all properties associated with this group.
+	// Properties contains all properties associated with this group. This map
+	// belongs to the Group, so changing it does not affect any other object in
+	// the file.
 	Properties map[string]Property
 
 	f *File
 }
 
+// Property returns the property of the root file object with the given name,
+// and whether it exists.
+func (t *File) Property(name string) (Property, bool) {
+	prop, ok := t.Properties[name]
+	return prop, ok
+}
+
+// HasProperty returns whether the root file object has a property with the
+// given name.
+func (t *File) HasProperty(name string) bool {
+	_, ok := t.Properties[name]
+	return ok
+}
+
+// RootObjectPath returns the path of the root file object, whose properties
+// are the ones in [File.Properties].
+func (t *File) RootObjectPath() string {
+	return formatPath("", "")
+}
+
+type objectPathOptions struct {
+	unescaped bool
+}
+
+// ObjectPathOption configures the paths returned by [File.ObjectPaths].
+type ObjectPathOption func(*objectPathOptions)
+
+// UnescapedPaths makes [File.ObjectPaths] return paths without the quotes
+// around each name, e.g. /group/channel instead of /'group'/'channel'. These
+// are easier to display, but are ambiguous if any name contains a slash, so
+// they can't be turned back into group and channel names.
+func UnescapedPaths() ObjectPathOption {
+	return func(opts *objectPathOptions) {
+		opts.unescaped = true
+	}
+}
+
+// ObjectPaths returns the path of every object in the file, including the
+// root object and groups as well as channels, in the order in which they first
+// appear in the file. The root object is only included if it is written in
+// the file. Paths are returned exactly as written in the file, e.g.
+// /'group'/'channel', unless the UnescapedPaths option is given.
+func (t *File) ObjectPaths(options ...ObjectPathOption) []string {
+	opts := objectPathOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	paths := make([]string, 0, len(t.objects))
+	seen := make(map[string]bool, len(t.objects))
+	for _, segment := range t.segments {
+		for _, path := range segment.metadata.objectOrder {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			if opts.unescaped {
+				path = unescapePath(path)
+			}
+
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// unescapePath returns the path with the quotes around each name removed. The
+// path must have been successfully parsed when the file was read.
+func unescapePath(path string) string {
+	groupName, channelName, err := parsePath(path)
+	if err != nil || groupName == "" {
+		return path
+	}
+
+	if channelName == "" {
+		return "/" + groupName
+	}
+
+	return "/" + groupName + "/" + channelName
+}
+
+// ObjectView is a flat view of a single object in the file, which may be the
+// root object, a group or a channel, as returned by [File.Objects].
+type ObjectView struct {
+	// Path is the path of the object exactly as written in the file, e.g.
+	// /'group'/'channel'.
+	Path string
+
+	// DataType is the type of the raw data of the object, or DataTypeVoid if
+	// the object has never had any raw data.
+	DataType DataType
+
+	// NumValues is the total number of values of the object across all
+	// segments.
+	NumValues uint64
+
+	// Properties contains the properties of the object, merged across
+	// segments. This map is shared with the file, so it must not be modified.
+	Properties map[string]Property
+}
+
+// Objects returns a flat view of every object in the file, in the order in
+// which they first appear, built directly from the metadata without going
+// through the groups and channels. Combined with the NoGroupTree option, this
+// is the cheapest way to get all of the metadata of a file.
+func (t *File) Objects() []ObjectView {
+	numValues := t.countValues()
+
+	paths := t.ObjectPaths()
+	objects := make([]ObjectView, len(paths))
+	for i, path := range paths {
+		obj := t.objects[path]
+
+		objects[i] = ObjectView{
+			Path:       path,
+			DataType:   DataTypeVoid,
+			NumValues:  numValues[path],
+			Properties: obj.properties,
+		}
+
+		if obj.index != nil {
+			objects[i].DataType = obj.index.dataType
+		}
+	}
+
+	return objects
+}
+
+// CompleteDataBoundary returns the absolute offset in the data file up to
+// which the raw data was completely written. For a complete file, this is the
+// end of the raw data of the final segment with any data. If the file is
+// incomplete or has been truncated, this is the end of the last value which
+// was completely written, so anything after it belongs to values which can't
+// be read, see [Channel.CompleteValueCount] and [Channel.LostValueCount].
+// Returns 0 if there is no raw data, e.g. an index file opened without its data
+// file.
+func (t *File) CompleteDataBoundary() int64 {
+	if !t.hasRawData() {
+		return 0
+	}
+
+	for i := len(t.segments) - 1; i >= 0; i-- {
+		segment := t.segments[i]
+		m := segment.metadata
+		if !segment.leadIn.containsRawData || m.numChunks == 0 {
+			continue
+		}
+
+		if m.finalChunkSize == 0 {
+			return m.rawDataOffset + int64(m.numChunks*m.chunkSize)
+		}
+
+		if m.finalChunkValues > 0 {
+			return m.rawDataOffset + int64((m.numChunks-1)*m.chunkSize+m.finalChunkSize)
+		}
+
+		// Only part of the final chunk was written, so find the end of the
+		// last complete value of any object in it.
+		lastChunkIdx := m.numChunks - 1
+		lastChunkOffset := int64(lastChunkIdx * m.chunkSize)
+		boundary := m.rawDataOffset + lastChunkOffset
+
+		for _, obj := range m.objects {
+			if obj.index == nil {
+				continue
+			}
+
+			numValues, size := m.chunkValues(obj.index, lastChunkIdx, segment.leadIn.isInterleaved)
+			if numValues == 0 {
+				continue
+			}
+
+			end := obj.index.offset + lastChunkOffset + int64(size)
+			if segment.leadIn.isInterleaved {
+				dataSize := int64(obj.index.dataType.Size())
+				end = obj.index.offset + lastChunkOffset + int64(numValues-1)*(dataSize+obj.index.stride) + dataSize
+			}
+
+			boundary = max(boundary, end)
+		}
+
+		return boundary
+	}
+
+	return 0
+}
+
+// Property returns the property of this group with the given name, and whether
+// it exists.
+func (g *Group) Property(name string) (Property, bool) {
+	prop, ok := g.Properties[name]
+	return prop, ok
+}
+
+// Channel returns the channel with the given name in the group with the given
+// name. Returns ErrNotFound if there is no such group or channel.
+//
+// The positions of a channel's data in the file are only worked out the first
+// time its data is read, so looking up and reading a handful of channels in a
+// very wide file only does the work for those channels.
+func (t *File) Channel(groupName, channelName string) (*Channel, error) {
+	group, ok := t.Groups[groupName]
+	if !ok {
+		return nil, fmt.Errorf("%w: group %s", ErrNotFound, groupName)
+	}
+
+	ch, ok := group.Channels[channelName]
+	if !ok {
+		return nil, fmt.Errorf("%w: channel %s in group %s", ErrNotFound, channelName, groupName)
+	}
+
+	return &ch, nil
+}
+
+// NumSegments returns the number of segments in the file.
+func (t *File) NumSegments() int {
+	return len(t.segments)
+}
+
+// SegmentChannel returns the channel with the given name in the group with the
+// given name, restricted to the data written in the segment with the given
+// index, so that reading it returns only the values from that segment. The
+// properties of the channel are those of the whole file.
+//
+// Returns ErrNotFound if there is no such segment, group or channel. A channel
+// with no data in the segment has no values.
+func (t *File) SegmentChannel(segmentIndex int, groupName, channelName string) (*Channel, error) {
+	if segmentIndex < 0 || segmentIndex >= len(t.segments) {
+		return nil, fmt.Errorf("%w: segment %d of %d", ErrNotFound, segmentIndex, len(t.segments))
+	}
+
+	ch, err := t.Channel(groupName, channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := slices.Collect(t.segmentDataChunks(segmentIndex, ch.path))
+
+	ch.totalNumValues = 0
+	for _, chunk := range chunks {
+		ch.totalNumValues += chunk.numValues
+	}
+
+	ch.lazyChunks = &lazyDataChunks{}
+	if !t.opts.metadataOnly {
+		ch.lazyChunks.chunks = chunks
+	}
+
+	return ch, nil
+}
+
+// SegmentChannelData reads all of the float64 values of a channel that were
+// written in the segment with the given index. Use [File.SegmentChannel] to
+// read the data as other types.
+func (t *File) SegmentChannelData(segmentIndex int, groupName, channelName string, options ...ReadOption) ([]float64, error) {
+	ch, err := t.SegmentChannel(segmentIndex, groupName, channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.ReadDataFloat64All(options...)
+}
+
+// SetDefaultBatchSize sets the batch size used when reading data from any
+// channel in this file without the BatchSize option, which still takes
+// precedence. Set to zero to go back to the built-in defaults, which depend on
+// the data type. This must not be called while data is being read.
+func (t *File) SetDefaultBatchSize(n int) {
+	t.defaultBatchSize = max(n, 0)
+}
+
 // New creates a [File] from the given [io.ReadSeeker]. Set isIndex to true when
 // reading a .tdms_index file. The size parameter must be the total byte length
 // of the data accessible through reader.
-func New(reader io.ReadSeeker, isIndex bool, size int64) (*File, error) {
-	// Properties can be overwritten from one segment to the next, so in order
-	// to know the objects and properties, we need to read the metadata for each
-	// segment upfront. For ease of use, we do this here.
-	f := &File{
-		Groups:     make(map[string]Group),
-		Properties: make(map[string]Property),
-		f:          reader,
-		size:       size,
-		isIndex:    isIndex,
-		objects:    make(map[string]object),
+//
+// If the magic bytes at the start of the data show that the file is the other
+// kind, New returns an error wrapping ErrIndexMismatch.
+func New(reader io.ReadSeeker, isIndex bool, size int64, options ...OpenOption) (*File, error) {
+	return NewCtx(context.Background(), reader, isIndex, size, options...)
+}
+
+// NewCtx is like [New], but stops reading the metadata if ctx is done, checking
+// it before each segment, and returns ctx.Err(). This stops opening a file
+// with many segments through a slow reader, e.g. one backed by object storage,
+// from hanging indefinitely.
+func NewCtx(ctx context.Context, reader io.ReadSeeker, isIndex bool, size int64, options ...OpenOption) (*File, error) {
+	opts := openOptions{isIndex: isIndex, isIndexSet: true}
+	for _, opt := range options {
+		opt(&opts)
 	}
 
-	if err := f.readMetadata(); err != nil {
+	f := newFile(reader, size, opts)
+	if err := f.readMetadata(ctx); err != nil {
 		return nil, err
 	}
 
 	return f, nil
 }
 
-// Open opens and parses the TDMS file at the given path. If the filename ends
-// with ".tdms_index", it is treated as an index file. The caller must call
-// [File.Close] when done.
+// newFile creates a File without reading any of its metadata.
+func newFile(reader io.ReadSeeker, size int64, opts openOptions) *File {
+	if opts.logger == nil {
+		opts.logger = slog.New(slog.DiscardHandler)
+	}
+
+	// Properties can be overwritten from one segment to the next, so in order
+	// to know the objects and properties, we need to read the metadata for each
+	// segment upfront. For ease of use, the constructors do this straight away.
+	return &File{
+		Groups:      make(map[string]Group),
+		Properties:  make(map[string]Property),
+		f:           reader,
+		size:        size,
+		isIndex:     opts.isIndex,
+		detectIndex: !opts.isIndexSet,
+		data:        reader,
+		dataSize:    size,
+		opts:        opts,
+		objects:     make(map[string]object),
+	}
+}
+
+type openOptions struct {
+	isIndex       bool
+	isIndexSet    bool
+	metadataOnly  bool
+	noGroupTree   bool
+	maxSegments   int
+	incomplete    IncompleteHandling
+	scanForMagic  bool
+	strict        bool
+	logger        *slog.Logger
+	stringDecoder StringDecoder
+}
+
+// OpenOption configures how a file is opened by [OpenWith], [OpenWithIndex]
+// or [New].
+type OpenOption func(*openOptions)
+
+// AsIndex forces the file to be treated as an index file (if isIndex is true)
+// or as a standard data file (if isIndex is false), instead of detecting this
+// from the magic bytes of the file. If the magic bytes don't match, opening the
+// file fails with ErrIndexMismatch.
+func AsIndex(isIndex bool) OpenOption {
+	return func(opts *openOptions) {
+		opts.isIndex = isIndex
+		opts.isIndexSet = true
+	}
+}
+
+// ScanForMagic searches the start of the file for the magic bytes of the
+// first segment instead of requiring them at the very start, so that a file
+// with a few bytes of junk prepended, e.g. by a broken transfer, can still be
+// opened. Only the first 64 KiB are searched. The offset at which the first
+// segment was found is reported by [File.ParseStats].
+func ScanForMagic() OpenOption {
+	return func(opts *openOptions) {
+		opts.scanForMagic = true
+	}
+}
+
+// MaxSegments stops reading the metadata of the file after n segments, setting
+// [File.Truncated] if there are more. The channels then only have the values
+// from the segments which were read. This bounds the time and memory taken to
+// open files with a huge number of segments, e.g. for a quick preview. If n is
+// zero or less, every segment is read.
+func MaxSegments(n int) OpenOption {
+	return func(opts *openOptions) {
+		opts.maxSegments = n
+	}
+}
+
+// Strict makes opening a file fail with ErrInvalidFileFormat if the metadata
+// of any segment takes up more bytes than the raw data offset in its lead in
+// allows. This means the metadata and the raw data offset disagree, usually
+// because the file is corrupt, and the raw data would be read from the wrong
+// place. Without it, this is only logged as a warning. Metadata which takes up
+// fewer bytes is always allowed, as writers may pad the metadata.
+func Strict() OpenOption {
+	return func(opts *openOptions) {
+		opts.strict = true
+	}
+}
+
+// NoGroupTree skips building the Groups of the file and the channels within
+// them, leaving Groups empty. The root object properties are still read into
+// [File.Properties]. Use [File.Objects] to get a flat view of every object
+// instead. This avoids allocating maps for every group and channel, which is
+// useful for dumping the metadata of files with a huge number of objects.
+func NoGroupTree() OpenOption {
+	return func(opts *openOptions) {
+		opts.noGroupTree = true
+	}
+}
+
+// WithLogger sets the logger used to report recoverable problems found while
+// parsing and reading the file, which are otherwise silent, e.g. an incomplete
+// final segment or a channel whose data type isn't supported. Problems which
+// may lose data are logged as warnings, and other details as debug messages.
+// By default, nothing is logged.
+func WithLogger(logger *slog.Logger) OpenOption {
+	return func(opts *openOptions) {
+		opts.logger = logger
+	}
+}
+
+// IncompleteHandling is how an incomplete file is handled, see
+// [IncompletePolicy].
+type IncompleteHandling int
+
+const (
+	// ReadPartial reads every value which was completely written, including
+	// the complete values of a chunk of raw data which was cut short. This is
+	// the default.
+	ReadPartial IncompleteHandling = iota
+
+	// DropIncomplete reads only whole chunks of raw data, dropping any chunk
+	// which was cut short along with the complete values within it, so that
+	// every channel has the same number of chunks of data.
+	DropIncomplete
+
+	// ErrorOnIncomplete fails to open a file which was incompletely written,
+	// or whose raw data is cut short, with ErrIncompleteFile.
+	ErrorOnIncomplete
+)
+
+// IncompletePolicy sets how a file which was incompletely written is handled,
+// e.g. because LabVIEW crashed or the file was copied while it was being
+// written. By default, every value which was completely written can be read,
+// see [ReadPartial].
+func IncompletePolicy(policy IncompleteHandling) OpenOption {
+	return func(opts *openOptions) {
+		opts.incomplete = policy
+	}
+}
+
+// MetadataOnly reads only the groups, channels and properties of the file,
+// without keeping track of where the data for each channel is. This uses much
+// less memory for files with many segments, which is useful when cataloguing
+// large numbers of files. The number of values in each channel is still
+// available, but reading channel data fails with ErrMetadataOnly.
+func MetadataOnly() OpenOption {
+	return func(opts *openOptions) {
+		opts.metadataOnly = true
+	}
+}
+
+// Open opens and parses the TDMS file at the given path. Whether the file is an
+// index file is detected from the magic bytes at the start of the file. The
+// caller must call [File.Close] when done.
 func Open(filename string) (*File, error) {
+	return OpenWith(filename)
+}
+
+// OpenWith opens and parses the TDMS file at the given path, configured by the
+// given options. The caller must call [File.Close] when done.
+func OpenWith(filename string, options ...OpenOption) (*File, error) {
+	opts := openOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
@@ -87,12 +569,13 @@ func Open(filename string) (*File, error) {
 		return nil, fmt.Errorf("failed to get file info for %s: %w", filename, err)
 	}
 
-	f, err := New(
-		file,
-		strings.HasSuffix(filename, ".tdms_index"),
-		fileInfo.Size(),
-	)
-	if err != nil {
+	if !opts.isIndexSet {
+		opts.isIndex = strings.HasSuffix(filename, ".tdms_index")
+	}
+
+	f := newFile(file, fileInfo.Size(), opts)
+	f.path = filename
+	if err := f.readMetadata(context.Background()); err != nil {
 		_ = file.Close()
 		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
 	}
@@ -100,22 +583,173 @@ func Open(filename string) (*File, error) {
 	return f, nil
 }
 
-// Close closes the underlying file if the File was created via [Open]. It is
-// safe to call on Files created via [New] (it is a no-op in that case).
+// OpenWithIndex opens the TDMS data file at dataFilename, reading the metadata
+// from its companion index file at indexFilename instead of from the data file
+// itself. As the index file holds only the metadata, this is much faster to
+// open for large files with many segments. Channel data is still read from the
+// data file. The file is configured by the given options as for [OpenWith],
+// except that the AsIndex option has no effect. The caller must call
+// [File.Close] when done, which closes both files.
+func OpenWithIndex(dataFilename, indexFilename string, options ...OpenOption) (*File, error) {
+	opts := openOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	// The metadata always comes from the index file.
+	opts.isIndex = true
+	opts.isIndexSet = true
+
+	indexFile, err := os.Open(indexFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index file %s: %w", indexFilename, err)
+	}
+
+	indexFileInfo, err := indexFile.Stat()
+	if err != nil {
+		_ = indexFile.Close()
+		return nil, fmt.Errorf("failed to get file info for %s: %w", indexFilename, err)
+	}
+
+	dataFile, err := os.Open(dataFilename)
+	if err != nil {
+		_ = indexFile.Close()
+		return nil, fmt.Errorf("failed to open file %s: %w", dataFilename, err)
+	}
+
+	dataFileInfo, err := dataFile.Stat()
+	if err != nil {
+		_ = indexFile.Close()
+		_ = dataFile.Close()
+		return nil, fmt.Errorf("failed to get file info for %s: %w", dataFilename, err)
+	}
+
+	f := newFile(indexFile, indexFileInfo.Size(), opts)
+	f.data = dataFile
+	f.dataSize = dataFileInfo.Size()
+	f.path = dataFilename
+
+	if err := f.readMetadata(context.Background()); err != nil {
+		_ = indexFile.Close()
+		_ = dataFile.Close()
+		return nil, fmt.Errorf("failed to read index file %s: %w", indexFilename, err)
+	}
+
+	return f, nil
+}
+
+// Path returns the filename the file was opened from, or false if it was
+// created via [New]. For a file opened via [OpenWithIndex], this is the data
+// file rather than the index file.
+func (t *File) Path() (string, bool) {
+	return t.path, t.path != ""
+}
+
+// OpenIndex opens the index file which sits alongside this data file, e.g.
+// "data.tdms_index" for "data.tdms". The index holds only the metadata, so
+// channel data can't be read from the returned File. The caller must call
+// [File.Close] on it when done.
+//
+// Returns ErrNotFound if the file wasn't opened from a path, or
+// ErrIndexMismatch if it is itself an index file.
+func (t *File) OpenIndex(options ...OpenOption) (*File, error) {
+	if t.path == "" {
+		return nil, fmt.Errorf("%w: file was not opened from a path", ErrNotFound)
+	}
+
+	if t.isIndex && !t.hasRawData() {
+		return nil, fmt.Errorf("%w: %s is already an index file", ErrIndexMismatch, t.path)
+	}
+
+	return OpenWith(strings.TrimSuffix(t.path, ".gz")+"_index", options...)
+}
+
+// Close closes the underlying files if the File was created via [Open],
+// [OpenWith] or [OpenWithIndex]. It is safe to call on Files created via [New]
+// (it is a no-op in that case).
 func (t *File) Close() error {
+	var err error
 	if file, ok := t.f.(*os.File); ok && file != nil {
-		return file.Close()
+		err = file.Close()
 	}
 
-	return nil
+	if t.data != t.f {
+		if file, ok := t.data.(*os.File); ok && file != nil {
+			err = errors.Join(err, file.Close())
+		}
+	}
+
+	return err
+}
+
+// hasRawData returns whether the raw data for the file is available, which is
+// not the case when reading an index file by itself.
+func (t *File) hasRawData() bool {
+	return !t.isIndex || t.data != t.f
+}
+
+// magicScanLimit is how far into the file [ScanForMagic] searches for the
+// magic bytes of the first segment.
+const magicScanLimit = 64 * 1024
+
+// skipToMagic finds the magic bytes of the first segment within the start of
+// the file and seeks to them, returning the offset of the segment from the
+// start of the data. Segments of an index file hold offsets into the data
+// file, which isn't affected by junk at the start of the index file, so the
+// returned offset is zero in that case.
+func (t *File) skipToMagic() (int64, error) {
+	buf := make([]byte, min(t.size, magicScanLimit))
+	n, err := io.ReadFull(t.f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, errors.Join(ErrReadFailed, err)
+	}
+	buf = buf[:n]
+
+	offset := bytes.Index(buf, tdmsMagicBytes)
+	isIndexMagic := false
+	if indexOffset := bytes.Index(buf, tdmsIndexMagicBytes); indexOffset >= 0 && (offset < 0 || indexOffset < offset) {
+		offset = indexOffset
+		isIndexMagic = true
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: no TDMS magic bytes found in the first %d bytes", ErrInvalidFileFormat, len(buf))
+	}
+
+	if offset > 0 {
+		t.opts.logger.Warn("skipping junk before the first segment", "offset", offset)
+	}
+
+	t.stats.MagicOffset = int64(offset)
+	if _, err := t.f.Seek(int64(offset), io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek to first segment: %w", err)
+	}
+
+	if isIndexMagic {
+		return 0, nil
+	}
+
+	return int64(offset), nil
 }
 
-// readMetadata reads the metadata for each segment in the file.
-func (t *File) readMetadata() error {
+// resumePoint is the position of a segment's lead in, along with the parse
+// statistics from before the segment was read.
+type resumePoint struct {
+	// offset is the offset of the segment in the data file.
+	offset int64
+
+	// leadInOffset is the offset of the lead in in the file the metadata is
+	// read from, which differs from offset for index files.
+	leadInOffset int64
+
+	stats ParseStats
+}
+
+// readMetadata reads the metadata for each segment in the file, stopping if
+// ctx is done.
+func (t *File) readMetadata(ctx context.Context) error {
 	t.segments = make([]segment, 0)
 
-	var prevSegment *segment
-	i := 0
 	currentOffset := int64(0)
 
 	_, err := t.f.Seek(0, io.SeekStart)
@@ -123,25 +757,135 @@ func (t *File) readMetadata() error {
 		return fmt.Errorf("failed to seek to beginning of metadata file: %w", err)
 	}
 
-	for {
+	if t.opts.scanForMagic {
+		if currentOffset, err = t.skipToMagic(); err != nil {
+			return err
+		}
+	}
+
+	return t.readSegments(ctx, currentOffset)
+}
+
+// resumeMetadata reads the metadata of the file, which is prev after more has
+// been written to it, carrying on from the final segment of prev. Only that
+// segment can have changed, e.g. by being finished, so the segments before it
+// are taken from prev rather than being read again.
+func (t *File) resumeMetadata(ctx context.Context, prev *File) error {
+	if prev.Truncated || len(prev.segments) == 0 {
+		return t.readMetadata(ctx)
+	}
+
+	// The segments are cloned so that appending to them doesn't overwrite
+	// prev's final segment if reading fails.
+	t.segments = slices.Clone(prev.segments[:len(prev.segments)-1])
+	t.stats = prev.resume.stats
+
+	// Merging the objects of every segment gives the same objects as reading
+	// them did, as each segment's objects already include anything they
+	// carried over from the previous segment.
+	for _, segment := range t.segments {
+		if !segment.leadIn.containsMetadata {
+			continue
+		}
+
+		for _, path := range segment.metadata.objectOrder {
+			obj := segment.metadata.objects[path]
+			t.mergeObject(&obj)
+		}
+	}
+
+	if _, err := t.f.Seek(prev.resume.leadInOffset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to segment %d: %w", len(t.segments), err)
+	}
+
+	return t.readSegments(ctx, prev.resume.offset)
+}
+
+// readSegments reads the metadata of each segment from the one at
+// currentOffset, where f must be positioned, to the end of the file, adding
+// them to the segments already read. The groups and channels are then built
+// from all of the segments.
+func (t *File) readSegments(ctx context.Context, currentOffset int64) error {
+	start := time.Now()
+	defer func() {
+		t.stats.Segments = len(t.segments)
+		t.stats.Objects = len(t.objects)
+		t.stats.Duration += time.Since(start)
+	}()
+
+	var prevSegment *segment
+	if len(t.segments) > 0 {
+		prevSegment = &t.segments[len(t.segments)-1]
+	}
+
+	for i := len(t.segments); ; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		t.resume = resumePoint{offset: currentOffset, leadInOffset: currentOffset, stats: t.stats}
+		if t.isIndex || t.detectIndex {
+			leadInOffset, err := t.f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("failed to get position of segment %d: %w", i, err)
+			}
+			t.resume.leadInOffset = leadInOffset
+		}
+
 		leadIn, err := t.readSegmentLeadIn()
 		if err != nil {
 			return fmt.Errorf("failed to read segment %d lead in: %w", i, err)
 		}
 
+		t.stats.MetadataBytes += int64(leadInSize)
 		if leadIn.containsMetadata {
-			metadata, err := t.readSegmentMetadata(currentOffset, leadIn, prevSegment)
+			t.stats.MetadataBytes += int64(leadIn.rawDataOffset)
+		}
+
+		// Every segment is tracked, including those without metadata, which
+		// reuse the objects and raw data indices of the previous segment. This
+		// is common in streaming files where the metadata is written once and
+		// each later write only appends raw data.
+		var m *metadata
+		switch {
+		case leadIn.containsMetadata:
+			metadataStart, err := t.f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("failed to get position of segment %d metadata: %w", i, err)
+			}
+
+			m, err = t.readSegmentMetadata(currentOffset, leadIn, prevSegment)
 			if err != nil {
 				return fmt.Errorf("failed to read segment %d metadata: %w", i, err)
 			}
 
-			prevSegment = &segment{
-				offset:   currentOffset,
-				leadIn:   leadIn,
-				metadata: metadata,
+			if err := t.checkMetadataSize(i, leadIn, metadataStart); err != nil {
+				return err
 			}
+		case prevSegment != nil:
+			t.opts.logger.Debug("segment has no metadata, using the objects of the previous segment", "segment", i)
+			m = t.carryOverMetadata(currentOffset, leadIn, prevSegment)
+		case leadIn.containsRawData:
+			return fmt.Errorf(
+				"%w: segment %d contains raw data but no metadata, and there is no previous segment to take it from",
+				ErrInvalidFileFormat,
+				i,
+			)
+		default:
+			// An empty segment before any objects have been written.
+			m = &metadata{objects: make(map[string]object)}
+		}
+
+		prevSegment = &segment{
+			offset:   currentOffset,
+			leadIn:   leadIn,
+			metadata: m,
+		}
+
+		t.segments = append(t.segments, *prevSegment)
 
-			t.segments = append(t.segments, *prevSegment)
+		if t.opts.incomplete == ErrorOnIncomplete && m.isIncomplete() {
+			return fmt.Errorf("%w: raw data of segment %d ends part way through a chunk", ErrIncompleteFile, i)
 		}
 
 		// The next segment offset is the offset from the end of the lead in.
@@ -149,18 +893,40 @@ func (t *File) readMetadata() error {
 
 		if leadIn.nextSegmentOffset == segmentIncomplete {
 			// Special value indicates that LabVIEW crashes while writing the final segment.
+			if t.opts.incomplete == ErrorOnIncomplete {
+				return fmt.Errorf("%w: segment %d was not completely written", ErrIncompleteFile, i)
+			}
+
 			t.IsIncomplete = true
+			t.opts.logger.Warn("file is incomplete, the final segment was not completely written", "segment", i)
 			break
 		}
 
-		if currentOffset >= t.size {
+		// If we're reading an index file, there's no data so one segment's
+		// metadata leads directly into the next segment's lead in. The offsets
+		// in the lead in refer to the data file, so we need to check our
+		// position in the index file itself to know when we've reached the
+		// end.
+		endOffset := currentOffset
+		if t.isIndex {
+			endOffset, err = t.f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return fmt.Errorf("failed to get position after segment %d: %w", i, err)
+			}
+		}
+
+		if endOffset >= t.size {
 			// We've reached the end of the file, all segments are read.
 			t.IsIncomplete = false
 			break
 		}
 
-		// If we're reading an index file, there's no data so one segment's
-		// metadata leads directly into the next segment's lead in.
+		if t.opts.maxSegments > 0 && len(t.segments) >= t.opts.maxSegments {
+			// There are more segments, but we've been asked not to read them.
+			t.Truncated = true
+			break
+		}
+
 		if !t.isIndex {
 			_, err := t.f.Seek(currentOffset, io.SeekStart)
 			if err != nil {
@@ -169,13 +935,22 @@ func (t *File) readMetadata() error {
 		}
 	}
 
+	if t.opts.noGroupTree {
+		if root, ok := t.objects[t.RootObjectPath()]; ok {
+			maps.Copy(t.Properties, root.properties)
+		}
+		return nil
+	}
+
 	// Now that we have all the channels, parse the object paths and fill the
 	// file, group, and channel fields accordingly.
 
 	// We hold the channels in a list and add them all to their respective
 	// groups at the end, to avoid processing a channel before we've added the
-	// corresponding group.
+	// corresponding group. They are keyed by path, as channels in different
+	// groups can have the same name.
 	channels := make(map[string]Channel, len(t.objects))
+	numValues := t.countValues()
 
 	for _, obj := range t.objects {
 		groupName, channelName, err := parsePath(obj.path)
@@ -191,68 +966,152 @@ func (t *File) readMetadata() error {
 			// This is a group object, so add it to the file's groups.
 			t.Groups[groupName] = Group{
 				Name:       groupName,
-				Properties: obj.properties,
+				Properties: maps.Clone(obj.properties),
 				Channels:   make(map[string]Channel),
 				f:          t,
 			}
 		} else {
 			// This is a channel object, so add it to the group's channels.
 
-			// Pre-compute the positions and metadata for each data chunk that
-			// this channel has, if any. This makes reading data for this
-			// channel much simpler.
-			chunks := make([]dataChunk, 0, len(t.segments))
-			for _, segment := range t.segments {
-				if !segment.leadIn.containsRawData {
-					continue
-				}
-
-				obj, ok := segment.metadata.objects[obj.path]
-				if !ok || obj.index == nil {
-					continue
-				}
-
-				for chunkIdx := range segment.metadata.numChunks {
-					chunks = append(chunks, dataChunk{
-						offset:        obj.index.offset + int64(chunkIdx*segment.metadata.chunkSize),
-						isInterleaved: segment.leadIn.isInterleaved,
-						order:         segment.leadIn.byteOrder,
-						size:          obj.index.totalSize,
-						numValues:     obj.index.numValues,
-						stride:        obj.index.stride,
-					})
+			// The positions of the data chunks are computed when the channel
+			// is first read, so that wide files don't pay for channels which
+			// are never read.
+			chunks := &lazyDataChunks{}
+			if !t.opts.metadataOnly {
+				path := obj.path
+				chunks.build = func() []dataChunk {
+					return slices.Collect(t.objectDataChunks(path))
 				}
 			}
 
-			totalNumValues := uint64(0)
-			for _, chunk := range chunks {
-				totalNumValues += chunk.numValues
+			// Channels which have never had any raw data written have no data
+			// type.
+			dataType := DataTypeVoid
+			rawTypeCode := uint32(DataTypeVoid)
+			if obj.index != nil {
+				dataType = obj.index.dataType
+				rawTypeCode = obj.index.rawDataType
 			}
 
-			channels[channelName] = Channel{
+			channels[obj.path] = Channel{
 				Name:           channelName,
 				GroupName:      groupName,
-				DataType:       obj.index.dataType,
-				Properties:     obj.properties,
+				DataType:       dataType,
+				Properties:     maps.Clone(obj.properties),
 				f:              t,
 				path:           obj.path,
-				dataChunks:     chunks,
-				totalNumValues: totalNumValues,
+				rawTypeCode:    rawTypeCode,
+				lazyChunks:     chunks,
+				totalNumValues: numValues[obj.path],
 			}
 		}
 	}
 
-	for channelName, channel := range channels {
+	for _, channel := range channels {
+		if report := inspectChannel(channel); !report.Supported {
+			t.opts.logger.Warn("channel data can't be read", "channel", channel.path, "reason", report.Reason)
+		}
+
+		// Writers aren't required to write a group object before the
+		// channels under it, so a group with no object of its own gets an
+		// empty one.
 		if _, exists := t.Groups[channel.GroupName]; !exists {
-			return fmt.Errorf("%w: channel %s sits under non-existent group %s",
-				ErrInvalidFileFormat,
-				channelName,
-				channel.GroupName,
-			)
+			t.opts.logger.Debug("channel has no group object, creating an empty group", "channel", channel.path, "group", channel.GroupName)
+			t.Groups[channel.GroupName] = Group{
+				Name:       channel.GroupName,
+				Properties: make(map[string]Property),
+				Channels:   make(map[string]Channel),
+				f:          t,
+			}
 		}
 
-		t.Groups[channel.GroupName].Channels[channelName] = channel
+		t.Groups[channel.GroupName].Channels[channel.Name] = channel
 	}
 
 	return nil
 }
+
+// countValues returns the total number of values of every object with raw data
+// across all segments. The values are counted in a single pass over the
+// segments, rather than a pass per object, and without visiting each chunk.
+func (t *File) countValues() map[string]uint64 {
+	numValues := make(map[string]uint64, len(t.objects))
+	for _, segment := range t.segments {
+		if !segment.leadIn.containsRawData {
+			continue
+		}
+
+		m := segment.metadata
+		if m.numChunks == 0 {
+			continue
+		}
+
+		for path, obj := range m.objects {
+			if obj.index == nil {
+				continue
+			}
+
+			// Every chunk has the same number of values except possibly the
+			// last.
+			lastChunkValues, _ := m.chunkValues(obj.index, m.numChunks-1, segment.leadIn.isInterleaved)
+			numValues[path] += obj.index.numValues*(m.numChunks-1) + lastChunkValues
+		}
+	}
+
+	return numValues
+}
+
+// objectDataChunks returns an iterator over the positions and metadata of each
+// raw data chunk of the object with the given path, across all segments.
+func (t *File) objectDataChunks(path string) iter.Seq[dataChunk] {
+	return func(yield func(dataChunk) bool) {
+		for segmentIdx := range t.segments {
+			for chunk := range t.segmentDataChunks(segmentIdx, path) {
+				if !yield(chunk) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// segmentDataChunks returns an iterator over the positions and metadata of each
+// raw data chunk of the object with the given path in a single segment.
+func (t *File) segmentDataChunks(segmentIdx int, path string) iter.Seq[dataChunk] {
+	return func(yield func(dataChunk) bool) {
+		segment := &t.segments[segmentIdx]
+		if !segment.leadIn.containsRawData {
+			return
+		}
+
+		obj, ok := segment.metadata.objects[path]
+		if !ok || obj.index == nil {
+			return
+		}
+
+		for chunkIdx := range segment.metadata.numChunks {
+			numValues, size := segment.metadata.chunkValues(obj.index, chunkIdx, segment.leadIn.isInterleaved)
+			if numValues == 0 {
+				continue
+			}
+
+			chunk := dataChunk{
+				offset:        segment.metadata.chunkOffset(obj.index, chunkIdx, segment.leadIn.isInterleaved),
+				isInterleaved: segment.leadIn.isInterleaved,
+				order:         segment.leadIn.byteOrder,
+				size:          size,
+				numValues:     numValues,
+				stride:        obj.index.stride,
+				segmentIndex:  segmentIdx,
+			}
+
+			if obj.index.scalerType != daqmxScalerTypeNone {
+				chunk.daqmx = obj.index
+			}
+
+			if !yield(chunk) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,899 @@
+package tdms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	return filename
+}
+
+func TestOpenDetectsIndexFromMagicBytes(t *testing.T) {
+	segment := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []int32{1, 2, 3}},
+		},
+	}
+
+	indexFilename := writeTestFile(t, "data.idx", buildTestIndexFile(segment))
+	dataFilename := writeTestFile(t, "data.tdms_index", buildTestFile(segment))
+
+	for _, filename := range []string{indexFilename, dataFilename} {
+		f, err := Open(filename)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", filepath.Base(filename), err)
+		}
+
+		ch := testChannel(t, f, "group", "channel")
+		if ch.NumValues() != 3 {
+			t.Errorf("%s: expected 3 values, got %d", filepath.Base(filename), ch.NumValues())
+		}
+
+		_ = f.Close()
+	}
+
+	if _, err := OpenWith(indexFilename, AsIndex(false)); !errors.Is(err, ErrIndexMismatch) {
+		t.Errorf("expected ErrIndexMismatch forcing index file to be read as data file, got %v", err)
+	}
+
+	if _, err := OpenWith(dataFilename, AsIndex(true)); !errors.Is(err, ErrIndexMismatch) {
+		t.Errorf("expected ErrIndexMismatch forcing data file to be read as index file, got %v", err)
+	}
+
+	f, err := OpenWith(indexFilename, AsIndex(true))
+	if err != nil {
+		t.Fatalf("unexpected error forcing index mode: %v", err)
+	}
+	_ = f.Close()
+}
+
+func TestNewIndexMismatch(t *testing.T) {
+	segment := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		isIndex bool
+	}{
+		{"data file marked as index", buildTestFile(segment), true},
+		{"index file marked as data", buildTestIndexFile(segment), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(bytes.NewReader(tt.data), tt.isIndex, int64(len(tt.data)))
+			if !errors.Is(err, ErrIndexMismatch) {
+				t.Errorf("expected ErrIndexMismatch, got %v", err)
+			}
+
+			// A file which is actually corrupt is not reported as a mismatch.
+			corrupt := append([]byte("TDSx"), tt.data[4:]...)
+			_, err = New(bytes.NewReader(corrupt), tt.isIndex, int64(len(corrupt)))
+			if !errors.Is(err, ErrInvalidFileFormat) || errors.Is(err, ErrIndexMismatch) {
+				t.Errorf("expected ErrInvalidFileFormat for corrupt magic bytes, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenWithIndex(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2, 3}},
+				{path: "/'group'/'b'", values: []float64{0.5, 1.5}},
+			},
+			numChunks: 2,
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{4, 5}},
+			},
+		},
+	}
+
+	dataFilename := writeTestFile(t, "data.tdms", buildTestFile(segments...))
+	indexFilename := writeTestFile(t, "data.tdms_index", buildTestIndexFile(segments...))
+
+	f, err := OpenWithIndex(dataFilename, indexFilename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+	if err != nil {
+		t.Fatalf("unexpected error reading channel a: %v", err)
+	}
+
+	if expected := []int32{1, 2, 3, 1, 2, 3, 4, 5}; !slices.Equal(a, expected) {
+		t.Errorf("channel a: expected %v, got %v", expected, a)
+	}
+
+	b, err := testChannel(t, f, "group", "b").ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error reading channel b: %v", err)
+	}
+
+	if expected := []float64{0.5, 1.5, 0.5, 1.5}; !slices.Equal(b, expected) {
+		t.Errorf("channel b: expected %v, got %v", expected, b)
+	}
+
+	// Options apply as they do when opening a single file, while AsIndex
+	// can't make the index file be read as a data file.
+	metadataOnly, err := OpenWithIndex(dataFilename, indexFilename, MetadataOnly(), AsIndex(false))
+	if err != nil {
+		t.Fatalf("unexpected error opening with options: %v", err)
+	}
+	defer func() { _ = metadataOnly.Close() }()
+
+	ch := testChannel(t, metadataOnly, "group", "a")
+	if n := ch.NumValues(); n != 8 {
+		t.Errorf("expected 8 values with MetadataOnly, got %d", n)
+	}
+	if _, err := ch.ReadDataInt32All(); !errors.Is(err, ErrMetadataOnly) {
+		t.Errorf("expected ErrMetadataOnly, got %v", err)
+	}
+}
+
+func TestPathAndOpenIndex(t *testing.T) {
+	segment := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int32{1, 2, 3}},
+		},
+	}
+
+	dataFilename := writeTestFile(t, "data.tdms", buildTestFile(segment))
+	indexFilename := dataFilename + "_index"
+	if err := os.WriteFile(indexFilename, buildTestIndexFile(segment), 0o600); err != nil {
+		t.Fatalf("failed to write test index file: %v", err)
+	}
+
+	f, err := Open(dataFilename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if path, ok := f.Path(); !ok || path != dataFilename {
+		t.Errorf("expected path %s, got %q (%t)", dataFilename, path, ok)
+	}
+
+	index, err := f.OpenIndex()
+	if err != nil {
+		t.Fatalf("unexpected error opening index: %v", err)
+	}
+	defer func() { _ = index.Close() }()
+
+	if path, _ := index.Path(); path != indexFilename {
+		t.Errorf("expected index path %s, got %q", indexFilename, path)
+	}
+
+	if n := testChannel(t, index, "group", "a").NumValues(); n != 3 {
+		t.Errorf("expected 3 values in index, got %d", n)
+	}
+
+	if _, err := index.OpenIndex(); !errors.Is(err, ErrIndexMismatch) {
+		t.Errorf("expected ErrIndexMismatch opening index of index, got %v", err)
+	}
+
+	inMemory := openTestFile(t, segment)
+	if path, ok := inMemory.Path(); ok || path != "" {
+		t.Errorf("expected no path, got %q (%t)", path, ok)
+	}
+
+	if _, err := inMemory.OpenIndex(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPropertiesAreNotShared(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", props: []Property{{Name: "unit", TypeCode: DataTypeString, Value: "V"}}},
+				{path: "/'group'/'b'", props: []Property{{Name: "unit", TypeCode: DataTypeString, Value: "V"}}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+			},
+			appendObjects: true,
+		},
+	)
+
+	a := testChannel(t, f, "group", "a")
+	a.Properties["unit"] = Property{Name: "unit", TypeCode: DataTypeString, Value: "mV"}
+	delete(a.Properties, "gain")
+
+	b := testChannel(t, f, "group", "b")
+	if unit, _ := b.Properties["unit"].AsString(); unit != "V" {
+		t.Errorf("expected unit of b to be unaffected, got %s", unit)
+	}
+
+	fileObj := f.objects["/'group'/'a'"]
+	if unit, _ := fileObj.properties["unit"].AsString(); unit != "V" {
+		t.Errorf("expected file object unit to be unaffected, got %s", unit)
+	}
+	if _, ok := fileObj.properties["gain"]; !ok {
+		t.Errorf("expected file object to keep gain property")
+	}
+
+	// The property added in the second segment mustn't leak back into the
+	// first segment's view of the object.
+	if _, ok := f.segments[0].metadata.objects["/'group'/'a'"].properties["gain"]; ok {
+		t.Errorf("expected first segment's properties to be unaffected by second segment")
+	}
+
+	if prop, ok := testChannel(t, f, "group", "a").Property("gain"); ok {
+		t.Errorf("expected gain to be deleted from channel, got %v", prop)
+	}
+}
+
+func TestMetadataOnly(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'", props: []Property{{Name: "author", TypeCode: DataTypeString, Value: "me"}}},
+				{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'channel'", values: []float64{4, 5}},
+			},
+			appendObjects: true,
+		},
+	)
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), MetadataOnly())
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	if author, _ := f.Groups["group"].Properties["author"].AsString(); author != "me" {
+		t.Errorf("expected group author me, got %s", author)
+	}
+
+	ch := testChannel(t, f, "group", "channel")
+	if ch.totalNumValues != 8 {
+		t.Errorf("expected 8 values, got %d", ch.totalNumValues)
+	}
+	if chunks := ch.dataChunks(); len(chunks) != 0 {
+		t.Errorf("expected no data chunks, got %d", len(chunks))
+	}
+
+	if _, err := ch.ReadDataFloat64All(); !errors.Is(err, ErrMetadataOnly) {
+		t.Errorf("expected ErrMetadataOnly, got %v", err)
+	}
+}
+
+func TestFileChannel(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1, 2}},
+			{path: "/'group'/'b'", values: []float64{3, 4}},
+		},
+		numChunks: 2,
+	})
+
+	if _, err := f.Channel("missing", "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing group, got %v", err)
+	}
+	if _, err := f.Channel("group", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for missing channel, got %v", err)
+	}
+
+	a, err := f.Channel("group", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a.NumValues() != 4 {
+		t.Errorf("expected 4 values, got %d", a.NumValues())
+	}
+
+	// The data chunks of both channels are only built once they're needed.
+	for _, name := range []string{"a", "b"} {
+		if f.Groups["group"].Channels[name].lazyChunks.build == nil {
+			t.Errorf("expected data chunks of %s not to have been built yet", name)
+		}
+	}
+
+	values, err := a.ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{1, 2, 1, 2}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	// Reading a copy of the channel builds the chunks for every copy.
+	if f.Groups["group"].Channels["a"].lazyChunks.build != nil {
+		t.Errorf("expected data chunks of a to have been built")
+	}
+	if f.Groups["group"].Channels["b"].lazyChunks.build == nil {
+		t.Errorf("expected data chunks of b not to have been built yet")
+	}
+}
+
+func TestRootObjectProperties(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/", props: []Property{
+					{Name: "name", TypeCode: DataTypeString, Value: "first"},
+					{Name: "author", TypeCode: DataTypeString, Value: "me"},
+				}},
+				{path: "/'group'", props: []Property{{Name: "group only", TypeCode: DataTypeBool, Value: true}}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "second"}}},
+			},
+			appendObjects: true,
+		},
+	)
+
+	if path := f.RootObjectPath(); path != "/" {
+		t.Errorf("expected root object path /, got %s", path)
+	}
+
+	if !f.HasProperty("author") {
+		t.Errorf("expected author property from root object")
+	}
+	if f.HasProperty("group only") {
+		t.Errorf("expected group property not to be a file property")
+	}
+
+	if name, _ := f.Properties["name"].AsString(); name != "second" {
+		t.Errorf("expected name from the latest segment, got %s", name)
+	}
+
+	if len(f.Properties) != 2 {
+		t.Errorf("expected only the root object properties, got %v", f.Properties)
+	}
+}
+
+func TestImplicitGroup(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'explicit'", props: []Property{{Name: "kept", TypeCode: DataTypeBool, Value: true}}},
+			{path: "/'explicit'/'a'", values: []int32{1}},
+			{path: "/'implicit'/'b'", values: []int32{2, 3}},
+		},
+	})
+
+	group, ok := f.Groups["implicit"]
+	if !ok {
+		t.Fatalf("expected group to be created for channel without a group object, got %v", f.Groups)
+	}
+
+	if group.Name != "implicit" || len(group.Properties) != 0 {
+		t.Errorf("expected empty group named implicit, got %s with %v", group.Name, group.Properties)
+	}
+
+	values, err := testChannel(t, f, "implicit", "b").ReadDataInt32All()
+	if err != nil {
+		t.Fatalf("unexpected error reading channel: %v", err)
+	}
+	if !slices.Equal(values, []int32{2, 3}) {
+		t.Errorf("expected [2 3], got %v", values)
+	}
+
+	if _, ok := f.Groups["explicit"].Properties["kept"]; !ok {
+		t.Errorf("expected explicit group to keep its properties")
+	}
+}
+
+func TestSameChannelNameInGroups(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'a'"},
+			{path: "/'a'/'channel'", values: []int32{1}},
+			{path: "/'b'"},
+			{path: "/'b'/'channel'", values: []int32{2, 3}},
+		},
+	})
+
+	for group, expected := range map[string][]int32{"a": {1}, "b": {2, 3}} {
+		values, err := testChannel(t, f, group, "channel").ReadDataInt32All()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", group, err)
+		}
+		if !slices.Equal(values, expected) {
+			t.Errorf("%s: expected %v, got %v", group, expected, values)
+		}
+	}
+}
+
+func TestObjectPaths(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/"},
+				{path: "/'b group'"},
+				{path: "/'b group'/'it''s'", values: []int32{1}},
+				{path: "/'a group'"},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'a group'/'x/y'", values: []int32{2}},
+				{path: "/'b group'/'it''s'", values: []int32{3}},
+			},
+		},
+	)
+
+	expected := []string{"/", "/'b group'", "/'b group'/'it''s'", "/'a group'", "/'a group'/'x/y'"}
+	if paths := f.ObjectPaths(); !slices.Equal(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+
+	expected = []string{"/", "/b group", "/b group/it's", "/a group", "/a group/x/y"}
+	if paths := f.ObjectPaths(UnescapedPaths()); !slices.Equal(paths, expected) {
+		t.Errorf("expected unescaped %v, got %v", expected, paths)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'big'", values: []uint64{1 << 60, 1<<60 + 1}},
+				{path: "/'group'/'fixed'", values: []uint8{}, dataType: DataTypeFixedPoint},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'big'", values: []uint64{1, 2}},
+			},
+			noMetadata: true,
+			incomplete: true,
+			truncate:   4,
+		},
+	)
+
+	logs := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := testChannel(t, f, "group", "big").ReadDataAsFloat64Coerced(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"level=DEBUG msg=\"segment has no metadata",
+		"level=WARN msg=\"file is incomplete",
+		"level=WARN msg=\"raw data ends part way through a chunk",
+		"level=WARN msg=\"channel data can't be read\" channel=/'group'/'fixed'",
+	} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Errorf("expected logs to contain %q, got:\n%s", expected, logs.String())
+		}
+	}
+
+	if n := strings.Count(logs.String(), "loses precision"); n != 1 {
+		t.Errorf("expected precision loss to be logged once, got %d times", n)
+	}
+}
+
+func TestCompleteDataBoundary(t *testing.T) {
+	objects := []testObject{
+		{path: "/'group'"},
+		{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+		{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5}},
+	}
+
+	tests := []struct {
+		name        string
+		segment     testSegment
+		boundaryCut int64
+		expectedA   uint64
+		expectedB   uint64
+		lostA       uint64
+		lostB       uint64
+	}{
+		{"complete", testSegment{objects: objects, numChunks: 2}, 0, 8, 8, 0, 0},
+		// The last value of b is missing 4 of its 8 bytes.
+		{"truncated", testSegment{objects: objects, numChunks: 2, incomplete: true, truncate: 4}, 4, 8, 7, 0, 1},
+		// The last row is missing all of b and 2 bytes of a.
+		{"interleaved", testSegment{objects: objects, interleaved: true, incomplete: true, truncate: 10}, 2, 3, 3, 1, 1},
+		// All of b is missing from the final chunk.
+		{"chunk boundary", testSegment{objects: objects, numChunks: 2, incomplete: true, truncate: 32}, 0, 8, 4, 0, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := buildTestFile(test.segment)
+			f, err := New(bytes.NewReader(data), false, int64(len(data)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if expected, actual := int64(len(data))-test.boundaryCut, f.CompleteDataBoundary(); actual != expected {
+				t.Errorf("expected boundary %d, got %d", expected, actual)
+			}
+
+			a := testChannel(t, f, "group", "a")
+			if n := a.CompleteValueCount(); n != test.expectedA {
+				t.Errorf("channel a: expected %d complete values, got %d", test.expectedA, n)
+			}
+			if n := a.LostValueCount(); n != test.lostA {
+				t.Errorf("channel a: expected %d lost values, got %d", test.lostA, n)
+			}
+
+			b := testChannel(t, f, "group", "b")
+			if n := b.CompleteValueCount(); n != test.expectedB {
+				t.Errorf("channel b: expected %d complete values, got %d", test.expectedB, n)
+			}
+			if n := b.LostValueCount(); n != test.lostB {
+				t.Errorf("channel b: expected %d lost values, got %d", test.lostB, n)
+			}
+		})
+	}
+}
+
+func TestLostValueCountDropIncomplete(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+		},
+		numChunks:  2,
+		incomplete: true,
+		truncate:   2,
+	})
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), IncompletePolicy(DropIncomplete))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The three complete values of the final chunk are dropped along with
+	// the cut off one.
+	ch := testChannel(t, f, "group", "a")
+	if n := ch.CompleteValueCount(); n != 4 {
+		t.Errorf("expected 4 complete values, got %d", n)
+	}
+	if n := ch.LostValueCount(); n != 4 {
+		t.Errorf("expected 4 lost values, got %d", n)
+	}
+}
+
+func TestObjects(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "test"}}},
+				{path: "/'group'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+				{path: "/'group'/'a'", values: []int32{1, 2, 3}},
+				{path: "/'group'/'empty'"},
+			},
+			numChunks: 2,
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{4}},
+			},
+			incomplete: true,
+			truncate:   2,
+		},
+	}
+	data := buildTestFile(segments...)
+
+	expected := []ObjectView{
+		{Path: "/", DataType: DataTypeVoid, Properties: map[string]Property{"name": {Name: "name", TypeCode: DataTypeString, Value: "test"}}},
+		{Path: "/'group'", DataType: DataTypeVoid, Properties: map[string]Property{"gain": {Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+		{Path: "/'group'/'a'", DataType: DataTypeInt32, NumValues: 6, Properties: map[string]Property{}},
+		{Path: "/'group'/'empty'", DataType: DataTypeVoid, Properties: map[string]Property{}},
+	}
+
+	for _, options := range [][]OpenOption{nil, {NoGroupTree()}} {
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), options...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		objects := f.Objects()
+		if len(objects) != len(expected) {
+			t.Fatalf("expected %d objects, got %d", len(expected), len(objects))
+		}
+
+		for i, obj := range objects {
+			e := expected[i]
+			if obj.Path != e.Path || obj.DataType != e.DataType || obj.NumValues != e.NumValues || len(obj.Properties) != len(e.Properties) {
+				t.Errorf("object %d: expected %+v, got %+v", i, e, obj)
+			}
+
+			for name, prop := range e.Properties {
+				if actual := obj.Properties[name]; actual.Value != prop.Value || actual.TypeCode != prop.TypeCode {
+					t.Errorf("object %d: expected property %+v, got %+v", i, prop, actual)
+				}
+			}
+		}
+
+		if name, _ := f.Properties["name"].AsString(); name != "test" {
+			t.Errorf("expected root properties to be read, got %v", f.Properties)
+		}
+
+		if expectedGroups := len(options) == 0; (len(f.Groups) > 0) != expectedGroups {
+			t.Errorf("expected groups %v, got %v", expectedGroups, f.Groups)
+		}
+	}
+}
+
+func TestSegmentChannelData(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []float64{1, 2}},
+				{path: "/'group'/'b'", values: []float64{10}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []float64{3, 4, 5}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []float64{6}},
+			},
+			numChunks: 2,
+		},
+	)
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if f.NumSegments() != 3 {
+		t.Fatalf("expected 3 segments, got %d", f.NumSegments())
+	}
+
+	tests := []struct {
+		segment  int
+		channel  string
+		expected []float64
+	}{
+		{0, "a", []float64{1, 2}},
+		{1, "a", []float64{3, 4, 5}},
+		{2, "a", []float64{6, 6}},
+		{0, "b", []float64{10}},
+		{1, "b", []float64{}},
+	}
+
+	for _, test := range tests {
+		values, err := f.SegmentChannelData(test.segment, "group", test.channel)
+		if err != nil {
+			t.Errorf("segment %d channel %s: unexpected error: %v", test.segment, test.channel, err)
+			continue
+		}
+
+		if !slices.Equal(values, test.expected) {
+			t.Errorf("segment %d channel %s: expected %v, got %v", test.segment, test.channel, test.expected, values)
+		}
+	}
+
+	for _, segment := range []int{-1, 3} {
+		if _, err := f.SegmentChannelData(segment, "group", "a"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("segment %d: expected ErrNotFound, got %v", segment, err)
+		}
+	}
+
+	// The whole channel is unaffected.
+	ch, err := f.Channel("group", "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values, err := ch.ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []float64{1, 2, 3, 4, 5, 6, 6}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestScanForMagic(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+			},
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}},
+			},
+		},
+	}
+
+	junk := []byte{0xef, 0xbb, 0xbf, 'T', 'D', 'S'}
+	data := append(slices.Clone(junk), buildTestFile(segments...)...)
+
+	if _, err := New(bytes.NewReader(data), false, int64(len(data))); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("expected ErrInvalidFileFormat without ScanForMagic, got %v", err)
+	}
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), ScanForMagic())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if offset := f.ParseStats().MagicOffset; offset != int64(len(junk)) {
+		t.Errorf("expected magic offset %d, got %d", len(junk), offset)
+	}
+
+	a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+	if err != nil {
+		t.Fatalf("unexpected error reading a: %v", err)
+	}
+
+	if expected := []int32{1, 2, 3}; !slices.Equal(a, expected) {
+		t.Errorf("expected %v, got %v", expected, a)
+	}
+
+	// A file without junk is unaffected.
+	clean := buildTestFile(segments...)
+	f, err = New(bytes.NewReader(clean), false, int64(len(clean)), ScanForMagic())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if offset := f.ParseStats().MagicOffset; offset != 0 {
+		t.Errorf("expected magic offset 0, got %d", offset)
+	}
+
+	garbage := bytes.Repeat([]byte{'x'}, 100)
+	if _, err := New(bytes.NewReader(garbage), false, int64(len(garbage)), ScanForMagic()); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("expected ErrInvalidFileFormat for file without magic bytes, got %v", err)
+	}
+}
+
+func TestMaxSegments(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []float64{1, 2}},
+			},
+		},
+		{
+			objects:    []testObject{{path: "/'group'/'a'", values: []float64{3, 4}}},
+			noMetadata: true,
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'b'", values: []int32{5}},
+			},
+		},
+	}
+	data := buildTestFile(segments...)
+
+	tests := []struct {
+		maxSegments int
+		truncated   bool
+		numSegments int
+		numValues   uint64
+		hasB        bool
+	}{
+		{1, true, 1, 2, false},
+		{2, true, 2, 4, false},
+		{3, false, 3, 4, true},
+		{0, false, 3, 4, true},
+	}
+
+	for _, tt := range tests {
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), MaxSegments(tt.maxSegments))
+		if err != nil {
+			t.Fatalf("%d: failed to parse test file: %v", tt.maxSegments, err)
+		}
+
+		if f.Truncated != tt.truncated {
+			t.Errorf("%d: expected truncated %v, got %v", tt.maxSegments, tt.truncated, f.Truncated)
+		}
+
+		if f.NumSegments() != tt.numSegments {
+			t.Errorf("%d: expected %d segments, got %d", tt.maxSegments, tt.numSegments, f.NumSegments())
+		}
+
+		ch := testChannel(t, f, "group", "a")
+		values, err := ch.ReadDataFloat64All()
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", tt.maxSegments, err)
+		}
+		if uint64(len(values)) != tt.numValues {
+			t.Errorf("%d: expected %d values, got %v", tt.maxSegments, tt.numValues, values)
+		}
+
+		if _, err := f.Channel("group", "b"); (err == nil) != tt.hasB {
+			t.Errorf("%d: expected channel b to exist: %v, got error %v", tt.maxSegments, tt.hasB, err)
+		}
+	}
+}
+
+// cancelReader cancels a context once it has been read from.
+type cancelReader struct {
+	io.ReadSeeker
+	cancel context.CancelFunc
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return r.ReadSeeker.Read(p)
+}
+
+func TestNewCtx(t *testing.T) {
+	data := buildTestFile(
+		testSegment{objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1}},
+		}},
+		testSegment{objects: []testObject{
+			{path: "/'group'/'a'", values: []float64{2}},
+		}},
+	)
+
+	f, err := NewCtx(context.Background(), bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.NumSegments() != 2 {
+		t.Errorf("expected 2 segments, got %d", f.NumSegments())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewCtx(ctx, bytes.NewReader(data), false, int64(len(data))); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for cancelled context, got %v", err)
+	}
+
+	// Cancelling part way through stops before the next segment.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	r := &cancelReader{ReadSeeker: bytes.NewReader(data), cancel: cancel}
+	if _, err := NewCtx(ctx, r, false, int64(len(data))); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled when cancelled while reading, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package tdms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Follow watches a file which is still being written, e.g. by an ongoing
+// acquisition, re-reading its metadata every poll interval and yielding each
+// channel when it first appears and whenever it has gained new values. The
+// channels already in the file are yielded straight away.
+//
+// Each yielded channel is complete up to the latest read of the metadata, so
+// use a [ReadState] with e.g. [Channel.ReadDataFloat64Into] to read only the
+// values added since the channel was last yielded. The metadata is re-read
+// whenever the file grows, and also while [File.IsIncomplete] is set, so that
+// the final segment is picked up once the writer finishes it.
+//
+// A segment which is only partly written when the metadata is re-read is
+// picked up on a later poll. Any other error is yielded and stops the
+// iteration, which otherwise only stops when ctx is cancelled or the loop is
+// broken out of. The poll interval must be positive, otherwise an error is
+// yielded straight away. The File must not be used by other goroutines while
+// it is being followed.
+func (t *File) Follow(ctx context.Context, poll time.Duration) iter.Seq2[*Channel, error] {
+	return func(yield func(*Channel, error) bool) {
+		if poll <= 0 {
+			yield(nil, fmt.Errorf("poll interval must be positive, got %v", poll))
+			return
+		}
+
+		seen := make(map[string]uint64)
+
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		for {
+			for _, groupName := range slices.Sorted(maps.Keys(t.Groups)) {
+				group := t.Groups[groupName]
+				for _, channelName := range slices.Sorted(maps.Keys(group.Channels)) {
+					ch := group.Channels[channelName]
+
+					numValues, ok := seen[ch.path]
+					if ok && ch.NumValues() <= numValues {
+						continue
+					}
+					seen[ch.path] = ch.NumValues()
+
+					if !yield(&ch, nil) {
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := t.refresh(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+					t.opts.logger.Debug("file is part way through being written, retrying", "error", err)
+					continue
+				}
+
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// refresh re-reads the metadata of the file if it has grown since it was last
+// read, or if it was incomplete. Only the final segment which was read and any
+// segments after it are read again. The metadata is read into a new File which
+// only replaces this one if it is read successfully, so that a failed read
+// leaves the file as it was.
+func (t *File) refresh(ctx context.Context) error {
+	size, err := t.f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	dataSize := size
+	if t.data != t.f {
+		if dataSize, err = t.data.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+
+	if size == t.size && dataSize == t.dataSize && !t.IsIncomplete {
+		return nil
+	}
+
+	fresh := newFile(t.f, size, t.opts)
+	fresh.isIndex = t.isIndex
+	fresh.detectIndex = false
+	fresh.path = t.path
+	fresh.data = t.data
+	fresh.dataSize = dataSize
+	fresh.defaultBatchSize = t.defaultBatchSize
+
+	if err := fresh.resumeMetadata(ctx, t); err != nil {
+		return err
+	}
+
+	*t = *fresh
+
+	// The groups and channels were made by the new File, so point them back
+	// at this one.
+	for groupName, group := range t.Groups {
+		group.f = t
+		for channelName, ch := range group.Channels {
+			ch.f = t
+			group.Channels[channelName] = ch
+		}
+		t.Groups[groupName] = group
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package tdms
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	first := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1, 2}},
+		},
+	}
+	second := testSegment{
+		objects: []testObject{
+			{path: "/'group'/'a'", values: []float64{3}},
+		},
+		appendObjects: true,
+	}
+	third := testSegment{
+		objects: []testObject{
+			{path: "/'group'/'b'", values: []int32{4, 5}},
+		},
+	}
+
+	firstBytes := first.bytes()
+	incompleteSecond := second
+	incompleteSecond.incomplete = true
+	secondBytes := incompleteSecond.bytes()
+
+	filename := writeTestFile(t, "follow.tdms", firstBytes)
+
+	f, err := Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	writer, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("failed to open file for writing: %v", err)
+	}
+	defer writer.Close()
+
+	write := func(data []byte) {
+		if _, err := writer.Write(data); err != nil {
+			t.Errorf("failed to write to file: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var yielded []string
+	var state ReadState
+	var values []float64
+
+	for ch, err := range f.Follow(ctx, time.Millisecond) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		yielded = append(yielded, ch.Name)
+
+		if ch.Name == "a" {
+			dst := make([]float64, 10)
+			n, err := ch.ReadDataFloat64Into(dst, &state)
+			if err != nil {
+				t.Fatalf("failed to read new values: %v", err)
+			}
+			values = append(values, dst[:n]...)
+		}
+
+		switch len(yielded) {
+		case 1:
+			// Write the lead in of the next segment in two parts, so that
+			// the file is part way through a segment when it's re-read.
+			write(secondBytes[:10])
+			time.AfterFunc(20*time.Millisecond, func() { write(secondBytes[10:]) })
+		case 2:
+			if !f.IsIncomplete {
+				t.Errorf("expected file to be incomplete")
+			}
+
+			// Finish the segment by filling in its next segment offset,
+			// as the writer does when it closes the file, then add another
+			// segment with a new channel.
+			patch, err := os.OpenFile(filename, os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatalf("failed to open file for writing: %v", err)
+			}
+			_, err = patch.WriteAt(second.bytes()[12:20], int64(len(firstBytes))+12)
+			_ = patch.Close()
+			if err != nil {
+				t.Fatalf("failed to finish segment: %v", err)
+			}
+
+			write(third.bytes())
+		case 3:
+			cancel()
+		}
+	}
+
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("expected iteration to stop when cancelled, got %v", ctx.Err())
+	}
+
+	if expected := []string{"a", "a", "b"}; !slices.Equal(yielded, expected) {
+		t.Errorf("expected channels %v to be yielded, got %v", expected, yielded)
+	}
+
+	if expected := []float64{1, 2, 3}; !slices.Equal(values, expected) {
+		t.Errorf("expected values %v, got %v", expected, values)
+	}
+
+	if f.IsIncomplete {
+		t.Errorf("expected file to be complete once the segment is finished")
+	}
+
+	ch := testChannel(t, f, "group", "b")
+	if ch.NumValues() != 2 {
+		t.Errorf("expected new channel to have 2 values, got %d", ch.NumValues())
+	}
+}
+
+func TestRefreshResumes(t *testing.T) {
+	first := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'a'",
+				values: []float64{1, 2},
+				props:  []Property{{Name: "unit", TypeCode: DataTypeString, Value: "V"}},
+			},
+		},
+	}
+	second := testSegment{
+		objects: []testObject{
+			{path: "/'group'/'a'", values: []float64{3}},
+		},
+		appendObjects: true,
+	}
+	third := testSegment{
+		objects: []testObject{
+			{
+				path:   "/'group'/'a'",
+				values: []float64{4},
+				props:  []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}},
+			},
+			{path: "/'group'/'b'", values: []int32{5, 6}},
+		},
+	}
+
+	incompleteSecond := second
+	incompleteSecond.incomplete = true
+
+	for _, isIndex := range []bool{false, true} {
+		t.Run(fmt.Sprintf("index %v", isIndex), func(t *testing.T) {
+			build := buildTestFile
+			if isIndex {
+				build = buildTestIndexFile
+			}
+
+			initial := build(first, incompleteSecond)
+			f, err := New(bytes.NewReader(initial), isIndex, int64(len(initial)))
+			if err != nil {
+				t.Fatalf("failed to parse test file: %v", err)
+			}
+			if !f.IsIncomplete {
+				t.Fatalf("expected file to be incomplete")
+			}
+
+			// Corrupting the first segment checks that it isn't read again.
+			data := buildTestFile(first, second, third)
+			metadata := build(first, second, third)
+			corrupt := slices.Clone(metadata)
+			copy(corrupt, "XXXX")
+
+			f.f = bytes.NewReader(corrupt)
+			f.data = bytes.NewReader(data)
+			f.dataSize = int64(len(data))
+			if err := f.refresh(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected, err := New(bytes.NewReader(metadata), isIndex, int64(len(metadata)))
+			if err != nil {
+				t.Fatalf("failed to parse test file: %v", err)
+			}
+
+			if f.IsIncomplete {
+				t.Errorf("expected file to be complete")
+			}
+			if f.NumSegments() != 3 {
+				t.Errorf("expected 3 segments, got %d", f.NumSegments())
+			}
+			if paths := f.ObjectPaths(); !slices.Equal(paths, expected.ObjectPaths()) {
+				t.Errorf("expected object paths %v, got %v", expected.ObjectPaths(), paths)
+			}
+
+			a := testChannel(t, f, "group", "a")
+			if !maps.Equal(a.Properties, testChannel(t, expected, "group", "a").Properties) {
+				t.Errorf("expected properties to be merged across segments, got %v", a.Properties)
+			}
+
+			values, err := a.ReadDataFloat64All()
+			if err != nil || !slices.Equal(values, []float64{1, 2, 3, 4}) {
+				t.Errorf("expected values [1 2 3 4], got %v (%v)", values, err)
+			}
+
+			if n := testChannel(t, f, "group", "b").NumValues(); n != 2 {
+				t.Errorf("expected new channel to have 2 values, got %d", n)
+			}
+
+			stats, expectedStats := f.ParseStats(), expected.ParseStats()
+			stats.Duration, expectedStats.Duration = 0, 0
+			if stats != expectedStats {
+				t.Errorf("expected stats %+v, got %+v", expectedStats, stats)
+			}
+		})
+	}
+}
+
+func TestFollowInvalidPoll(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1}},
+		},
+	})
+
+	for _, poll := range []time.Duration{0, -time.Second} {
+		var errs []error
+		for ch, err := range f.Follow(context.Background(), poll) {
+			if ch != nil {
+				t.Errorf("poll %v: expected no channels, got %s", poll, ch.Name)
+			}
+			errs = append(errs, err)
+		}
+
+		if len(errs) != 1 || errs[0] == nil {
+			t.Errorf("poll %v: expected a single error, got %v", poll, errs)
+		}
+	}
+}
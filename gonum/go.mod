@@ -0,0 +1,11 @@
+module github.com/drewsilcock/go-tdms/gonum
+
+// Support the most recent 2 Go versions (this is what the Go team support).
+go 1.24.0
+
+require github.com/drewsilcock/go-tdms v0.1.0
+
+require gonum.org/v1/gonum v0.17.0
+
+// Always build against the tdms package in this repository.
+replace github.com/drewsilcock/go-tdms => ../
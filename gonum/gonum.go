@@ -0,0 +1,33 @@
+// Package gonum reads TDMS channel data into [gonum] types, so that linear
+// algebra can be run on acquired data straight away.
+//
+// This is a separate module from the tdms package so that only programs which
+// use it depend on gonum.
+//
+// [gonum]: https://www.gonum.org
+package gonum
+
+import (
+	"github.com/drewsilcock/go-tdms"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadDataVecDense reads all values from a channel of any real numeric type
+// into a vector, converting them to float64 as by
+// [tdms.Channel.ReadDataAsFloat64Coerced]. The values read are used as the
+// backing data of the vector, so no extra copy is made. A channel with no
+// values gives an empty vector.
+func ReadDataVecDense(ch *tdms.Channel, options ...tdms.ReadOption) (*mat.VecDense, error) {
+	values, err := ch.ReadDataAsFloat64Coerced(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// NewVecDense panics for a zero length, but the zero value is a valid
+	// empty vector.
+	if len(values) == 0 {
+		return &mat.VecDense{}, nil
+	}
+
+	return mat.NewVecDense(len(values), values), nil
+}
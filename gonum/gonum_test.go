@@ -0,0 +1,48 @@
+package gonum
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/drewsilcock/go-tdms"
+)
+
+func TestReadDataVecDense(t *testing.T) {
+	tests := []struct {
+		filename, groupName, channelName string
+	}{
+		{"../testdata/big_endian.tdms", "Measured Data", "Phase sweep"},
+		{"../testdata/raw_timestamps.tdms", "Untitled", "Untitled"},
+	}
+
+	for _, tt := range tests {
+		f, err := tdms.Open(tt.filename)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", tt.filename, err)
+		}
+		defer f.Close()
+
+		ch, err := f.Channel(tt.groupName, tt.channelName)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.filename, err)
+		}
+
+		expected, err := ch.ReadDataAsFloat64Coerced()
+		if err != nil {
+			t.Fatalf("%s: unexpected error reading values: %v", tt.filename, err)
+		}
+
+		vec, err := ReadDataVecDense(ch)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.filename, err)
+		}
+
+		if vec.Len() != int(ch.NumValues()) {
+			t.Errorf("%s: expected %d values, got %d", tt.filename, ch.NumValues(), vec.Len())
+		}
+
+		if actual := vec.RawVector().Data; !slices.Equal(actual, expected) {
+			t.Errorf("%s: vector doesn't match channel values", tt.filename)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package tdms
+
+// This file contains helpers for building TDMS files in memory so that tests
+// can exercise specific layouts without needing to generate files on disk.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testObject struct {
+	path string
+
+	// values is the raw data for a single chunk of this object, e.g. []float64
+	// or []string. If nil, the object is written without a raw data index.
+	values any
+
+	// dataType overrides the data type written in the raw data index. If
+	// zero, it is inferred from the type of values.
+	dataType DataType
+
+	// reuseIndex writes the "matches previous value" raw data index header
+	// instead of a full raw data index.
+	reuseIndex bool
+
+	// omitStringOffsets writes the total size of string values without
+	// their offsets, as some writers do.
+	omitStringOffsets bool
+
+	// daqmx writes a DAQmx format changing scaler raw data index instead of
+	// a standard one. The raw data itself is given by testSegment.daqmxData.
+	daqmx *testDAQmx
+
+	props []Property
+}
+
+type testSegment struct {
+	objects []testObject
+
+	// noMetadata writes only raw data for the objects, relying on the
+	// metadata of the previous segment.
+	noMetadata bool
+
+	// appendObjects omits the new object list flag.
+	appendObjects bool
+
+	interleaved bool
+	bigEndian   bool
+
+	// numChunks is the number of times the raw data is repeated. Defaults to
+	// 1.
+	numChunks int
+
+	// padding is the number of bytes written between the metadata and the raw
+	// data.
+	padding int
+
+	// incomplete marks the segment as incomplete, as if LabVIEW crashed while
+	// writing it.
+	incomplete bool
+
+	// truncate is the number of bytes removed from the end of the segment.
+	truncate int
+
+	// shortChunkValues, if non-zero, adds a final chunk after the others with
+	// only the first shortChunkValues values of each object.
+	shortChunkValues int
+
+	// daqmxData is the contents of the DAQmx raw buffers for a single chunk,
+	// which comes after the raw data of the other objects.
+	daqmxData []byte
+}
+
+type testDAQmx struct {
+	numValues int
+	widths    []uint32
+	scalers   []daqmxScaler
+	digital   bool
+}
+
+func (s testSegment) byteOrder() binary.ByteOrder {
+	if s.bigEndian {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+func (s testSegment) bytes() []byte {
+	order := s.byteOrder()
+
+	metadata := &bytes.Buffer{}
+	if !s.noMetadata {
+		writeTestUint32(metadata, order, uint32(len(s.objects)))
+		for _, obj := range s.objects {
+			writeTestString(metadata, order, obj.path)
+
+			switch {
+			case obj.daqmx != nil:
+				writeTestDAQmxIndex(metadata, order, obj.daqmx)
+			case obj.values == nil:
+				writeTestUint32(metadata, order, rawIndexHeaderNoRawData)
+			case obj.reuseIndex:
+				writeTestUint32(metadata, order, rawIndexHeaderMatchesPreviousValue)
+			default:
+				dataType := obj.dataType
+				if dataType == DataTypeVoid {
+					dataType = testDataTypeOf(obj.values)
+				}
+
+				numValues, size := testValuesLen(obj.values, order)
+
+				indexLen := uint32(20)
+				if dataType == DataTypeString {
+					indexLen = 28
+				}
+
+				writeTestUint32(metadata, order, indexLen)
+				writeTestUint32(metadata, order, uint32(dataType))
+				writeTestUint32(metadata, order, 1)
+				_ = binary.Write(metadata, order, uint64(numValues))
+				if dataType == DataTypeString {
+					if obj.omitStringOffsets {
+						size -= 4 * numValues
+					}
+					_ = binary.Write(metadata, order, uint64(size))
+				}
+			}
+
+			writeTestUint32(metadata, order, uint32(len(obj.props)))
+			for _, prop := range obj.props {
+				writeTestString(metadata, order, prop.Name)
+				writeTestUint32(metadata, order, uint32(prop.TypeCode))
+				writeTestValue(metadata, order, prop.Value)
+			}
+		}
+	}
+
+	// writeChunk writes the raw data of a chunk with the first numValues
+	// values of each object, or all the values if numValues is negative.
+	writeChunk := func(chunk *bytes.Buffer, numValues int) {
+		if s.interleaved {
+			// Every object with data must have the same number of values.
+			if numValues < 0 {
+				for _, obj := range s.objects {
+					if obj.values != nil {
+						numValues, _ = testValuesLen(obj.values, order)
+						break
+					}
+				}
+			}
+
+			for i := range numValues {
+				for _, obj := range s.objects {
+					if obj.values != nil {
+						writeTestValues(chunk, order, obj.values, i, i+1)
+					}
+				}
+			}
+		} else {
+			for _, obj := range s.objects {
+				if obj.values != nil {
+					objValues, _ := testValuesLen(obj.values, order)
+					if numValues >= 0 {
+						objValues = min(objValues, numValues)
+					}
+					writeTestValues(chunk, order, obj.values, 0, objValues)
+				}
+			}
+		}
+	}
+
+	chunk := &bytes.Buffer{}
+	writeChunk(chunk, -1)
+	chunk.Write(s.daqmxData)
+
+	numChunks := max(s.numChunks, 1)
+	rawData := bytes.Repeat(chunk.Bytes(), numChunks)
+	if s.shortChunkValues > 0 {
+		shortChunk := &bytes.Buffer{}
+		writeChunk(shortChunk, s.shortChunkValues)
+		rawData = append(rawData, shortChunk.Bytes()...)
+	}
+
+	toc := uint32(0)
+	if !s.noMetadata {
+		toc |= tocContainsMetadata
+		if !s.appendObjects {
+			toc |= tocContainsNewObjectList
+		}
+	}
+	if len(rawData) > 0 {
+		toc |= tocContainsRawData
+	}
+	if s.interleaved {
+		toc |= tocDataIsInterleaved
+	}
+	if s.bigEndian {
+		toc |= tocIsBigEndian
+	}
+	if s.daqmxData != nil {
+		toc |= tocContainsDAQMXRawData
+	}
+
+	rawDataOffset := uint64(metadata.Len() + s.padding)
+	nextSegmentOffset := rawDataOffset + uint64(len(rawData))
+	if s.incomplete {
+		nextSegmentOffset = segmentIncomplete
+	}
+
+	out := &bytes.Buffer{}
+	out.Write(tdmsMagicBytes)
+	writeTestUint32(out, binary.LittleEndian, toc)
+	writeTestUint32(out, order, 4713)
+	_ = binary.Write(out, order, nextSegmentOffset)
+	_ = binary.Write(out, order, rawDataOffset)
+	out.Write(metadata.Bytes())
+	out.Write(make([]byte, s.padding))
+	out.Write(rawData)
+
+	return out.Bytes()[:out.Len()-s.truncate]
+}
+
+// writeTestDAQmxIndex writes a DAQmx raw data index, where the data type of
+// each scaler is a TDMS data type which is converted to the DAQmx code.
+func writeTestDAQmxIndex(buf *bytes.Buffer, order binary.ByteOrder, daqmx *testDAQmx) {
+	header := rawIndexHeaderFormatChangingScaler
+	if daqmx.digital {
+		header = rawIndexHeaderDigitalLineScaler
+	}
+
+	writeTestUint32(buf, order, header)
+	writeTestUint32(buf, order, uint32(DataTypeDAQmxRawData))
+	writeTestUint32(buf, order, 1)
+	_ = binary.Write(buf, order, uint64(daqmx.numValues))
+
+	writeTestUint32(buf, order, uint32(len(daqmx.scalers)))
+	for _, scaler := range daqmx.scalers {
+		code := uint32(0)
+		for code < 10 && daqmxDataType(code) != scaler.dataType {
+			code++
+		}
+
+		writeTestUint32(buf, order, code)
+		writeTestUint32(buf, order, scaler.rawBufferIndex)
+		writeTestUint32(buf, order, scaler.rawByteOffsetWithinStride)
+		if daqmx.digital {
+			buf.WriteByte(byte(scaler.sampleFormatBitmap))
+		} else {
+			writeTestUint32(buf, order, scaler.sampleFormatBitmap)
+		}
+		writeTestUint32(buf, order, scaler.scaleID)
+	}
+
+	writeTestUint32(buf, order, uint32(len(daqmx.widths)))
+	for _, width := range daqmx.widths {
+		writeTestUint32(buf, order, width)
+	}
+}
+
+// buildTestFile concatenates the given segments into the bytes of a TDMS file.
+func buildTestFile(segments ...testSegment) []byte {
+	out := &bytes.Buffer{}
+	for _, s := range segments {
+		out.Write(s.bytes())
+	}
+	return out.Bytes()
+}
+
+// buildTestIndexFile builds the .tdms_index companion for the TDMS file with
+// the given segments, which contains the lead in and metadata of each segment
+// without the raw data.
+func buildTestIndexFile(segments ...testSegment) []byte {
+	out := &bytes.Buffer{}
+	for _, s := range segments {
+		segmentBytes := s.bytes()
+		rawDataOffset := s.byteOrder().Uint64(segmentBytes[20:28])
+
+		out.Write(tdmsIndexMagicBytes)
+		out.Write(segmentBytes[4 : leadInSize+rawDataOffset-uint64(s.padding)])
+	}
+	return out.Bytes()
+}
+
+// openTestFile builds a TDMS file from the given segments and parses it.
+func openTestFile(t testing.TB, segments ...testSegment) *File {
+	t.Helper()
+
+	data := buildTestFile(segments...)
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	return f
+}
+
+func testChannel(t testing.TB, f *File, groupName, channelName string) *Channel {
+	t.Helper()
+
+	group, ok := f.Groups[groupName]
+	if !ok {
+		t.Fatalf("group %s not found", groupName)
+	}
+
+	ch, ok := group.Channels[channelName]
+	if !ok {
+		t.Fatalf("channel %s/%s not found", groupName, channelName)
+	}
+
+	return &ch
+}
+
+// testDataTypeOf returns the data type of the elements of the slice values.
+func testDataTypeOf(values any) DataType {
+	dataType, err := DataTypeOf(reflect.Zero(reflect.TypeOf(values).Elem()).Interface())
+	if err != nil {
+		panic("unsupported test values type")
+	}
+	return dataType
+}
+
+// testValuesLen returns the number of values and the size in bytes of the
+// values when written to a single chunk.
+func testValuesLen(values any, order binary.ByteOrder) (int, int) {
+	numValues := reflect.ValueOf(values).Len()
+	if _, ok := values.([]string); ok {
+		buf := &bytes.Buffer{}
+		writeTestValues(buf, order, values, 0, numValues)
+		return numValues, buf.Len()
+	}
+	return numValues, numValues * testDataTypeOf(values).Size()
+}
+
+// writeTestValues writes values[start:end] in the raw data format.
+func writeTestValues(buf *bytes.Buffer, order binary.ByteOrder, values any, start, end int) {
+	switch v := values.(type) {
+	case []string:
+		offset := uint32(0)
+		for _, s := range v[start:end] {
+			offset += uint32(len(s))
+			writeTestUint32(buf, order, offset)
+		}
+		for _, s := range v[start:end] {
+			buf.WriteString(s)
+		}
+	case []Timestamp, []Float128:
+		// Neither is laid out in memory as it is in the file.
+		slice := reflect.ValueOf(values)
+		for i := start; i < end; i++ {
+			writeTestValue(buf, order, slice.Index(i).Interface())
+		}
+	default:
+		_ = binary.Write(buf, order, reflect.ValueOf(values).Slice(start, end).Interface())
+	}
+}
+
+// writeTestValue writes a single property value.
+func writeTestValue(buf *bytes.Buffer, order binary.ByteOrder, value any) {
+	switch v := value.(type) {
+	case string:
+		writeTestString(buf, order, v)
+	case bool:
+		if v {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case Timestamp:
+		if order == binary.BigEndian {
+			_ = binary.Write(buf, order, v.Timestamp)
+			_ = binary.Write(buf, order, v.Remainder)
+		} else {
+			_ = binary.Write(buf, order, v.Remainder)
+			_ = binary.Write(buf, order, v.Timestamp)
+		}
+	case Float128:
+		b := v
+		if order == binary.BigEndian {
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+		}
+		buf.Write(b[:])
+	case float64:
+		_ = binary.Write(buf, order, math.Float64bits(v))
+	default:
+		_ = binary.Write(buf, order, v)
+	}
+}
+
+func writeTestUint32(buf *bytes.Buffer, order binary.ByteOrder, value uint32) {
+	_ = binary.Write(buf, order, value)
+}
+
+func writeTestString(buf *bytes.Buffer, order binary.ByteOrder, value string) {
+	writeTestUint32(buf, order, uint32(len(value)))
+	buf.WriteString(value)
+}
+
+// testFloat128 converts a float64 to the equivalent quad precision value.
+func testFloat128(value float64) Float128 {
+	bits := math.Float64bits(value)
+	sign := bits >> 63
+	exponent := (bits >> 52) & 0x7FF
+	mantissa := bits & (1<<52 - 1)
+
+	switch {
+	case exponent == 0x7FF:
+		exponent = 0x7FFF
+	case exponent != 0:
+		exponent = exponent - 1023 + 16383
+	case mantissa != 0:
+		panic("subnormal float64 values are not supported")
+	}
+
+	// The 52-bit mantissa becomes the top of the 112-bit quad mantissa, so the
+	// lower 64 bits only contain the bottom 4 bits of the float64 mantissa.
+	high := sign<<63 | exponent<<48 | mantissa>>4
+	low := mantissa << 60
+
+	var f Float128
+	binary.LittleEndian.PutUint64(f[:8], low)
+	binary.LittleEndian.PutUint64(f[8:], high)
+	return f
+}
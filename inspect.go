@@ -0,0 +1,312 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Report describes the features of a TDMS file which this library can't
+// handle, as found by [Inspect].
+type Report struct {
+	// Problems lists any problems which stopped the metadata of the file from
+	// being read, e.g. an unsupported version or property type. If there are
+	// any problems, Channels only contains the channels found before the first
+	// problem, which may be none of them.
+	Problems []string
+
+	// IsIncomplete indicates whether the file was incompletely written. The
+	// data that was written can still be read, but the final segment may be
+	// missing values.
+	IsIncomplete bool
+
+	// Channels lists the support status of each channel in the file, sorted by
+	// group name and then channel name.
+	Channels []ChannelReport
+}
+
+// ChannelReport describes whether the data of a single channel can be read.
+type ChannelReport struct {
+	// GroupName is the name of the group that contains this channel.
+	GroupName string
+
+	// Name is the name of this channel.
+	Name string
+
+	// DataType is the type of data stored in this channel.
+	DataType DataType
+
+	// Supported indicates whether the data of this channel can be read.
+	Supported bool
+
+	// Reason explains why the channel isn't supported. It is empty for
+	// supported channels.
+	Reason string
+}
+
+// Supported returns whether the whole file can be read, i.e. its metadata was
+// read without any problems and every channel is supported. An incomplete file
+// is still supported, as the data that was written can be read.
+func (r Report) Supported() bool {
+	if len(r.Problems) > 0 {
+		return false
+	}
+
+	for _, ch := range r.Channels {
+		if !ch.Supported {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Inspect reads the metadata of the TDMS file at the given path without any of
+// its data, and reports any features it uses which aren't supported by this
+// library, e.g. fixed point or DAQmx channels. This lets you check whether a
+// file can be read before committing to reading it.
+//
+// Unsupported versions and property types stop the metadata from being read,
+// so they are listed in the problems of the report rather than returned as
+// errors. Any other failure to read the file, e.g. because it doesn't exist or
+// is not a valid TDMS file, is returned as an error.
+func Inspect(filename string) (Report, error) {
+	f, err := OpenWith(filename, MetadataOnly())
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedVersion) || errors.Is(err, ErrUnsupportedType) {
+			return Report{Problems: []string{err.Error()}}, nil
+		}
+
+		return Report{}, err
+	}
+	defer f.Close()
+
+	report := Report{IsIncomplete: f.IsIncomplete}
+
+	for _, groupName := range slices.Sorted(maps.Keys(f.Groups)) {
+		group := f.Groups[groupName]
+		for _, channelName := range slices.Sorted(maps.Keys(group.Channels)) {
+			report.Channels = append(report.Channels, inspectChannel(group.Channels[channelName]))
+		}
+	}
+
+	return report, nil
+}
+
+func inspectChannel(ch Channel) ChannelReport {
+	report := ChannelReport{
+		GroupName: ch.GroupName,
+		Name:      ch.Name,
+		DataType:  ch.DataType,
+		Supported: true,
+	}
+
+	switch {
+	case ch.DataType == DataTypeFixedPoint:
+		report.Supported = false
+		report.Reason = "fixed point data is not supported"
+	case ch.DataType == DataTypeDAQmxRawData:
+		report.Supported = false
+		report.Reason = "DAQmx raw data can only be read unscaled with ReadDAQmxRawData"
+	case ch.DataType != DataTypeVoid && !slices.Contains(SupportedDataTypes(), ch.DataType):
+		report.Supported = false
+		report.Reason = fmt.Sprintf("data type %s is not supported", ch.DataType)
+	}
+
+	return report
+}
+
+// Summary is a cheap overview of a TDMS file, as found by [Summarize] from
+// only the first segment of the file.
+type Summary struct {
+	// Properties are the properties of the file itself, as written in the
+	// first segment.
+	Properties map[string]Property
+
+	// Groups lists the groups in the first segment, sorted by name.
+	Groups []GroupSummary
+
+	// Approximate indicates that the file has more segments than the first, so
+	// it may have groups, channels and properties which aren't in the summary,
+	// property values which are changed later, and more channel values than
+	// the summary counts. If false, the summary is exact.
+	Approximate bool
+}
+
+// GroupSummary is a summary of a single group in a [Summary].
+type GroupSummary struct {
+	Name       string
+	Properties map[string]Property
+
+	// Channels lists the channels in the group, sorted by name.
+	Channels []ChannelSummary
+}
+
+// ChannelSummary is a summary of a single channel in a [Summary].
+type ChannelSummary struct {
+	Name     string
+	DataType DataType
+
+	// NumValues is the number of values of the channel in the first segment
+	// only. Unless the summary is exact, the channel may have more values, and
+	// the data type of a channel with no values in the first segment is
+	// [DataTypeVoid].
+	NumValues uint64
+}
+
+// Summarize reads only the metadata of the first segment of the TDMS file at
+// the given path, giving the names of its groups and channels, their data
+// types and the file level properties. This is much faster than [Open] for
+// files with many segments, e.g. when cataloguing a directory of thousands of
+// files, at the cost of the summary being approximate: see
+// [Summary.Approximate].
+func Summarize(filename string) (Summary, error) {
+	f, err := OpenWith(filename, MetadataOnly(), MaxSegments(1))
+	if err != nil {
+		return Summary{}, err
+	}
+	defer f.Close()
+
+	summary := Summary{
+		Properties:  f.Properties,
+		Approximate: f.Truncated,
+	}
+
+	for _, groupName := range slices.Sorted(maps.Keys(f.Groups)) {
+		group := f.Groups[groupName]
+
+		groupSummary := GroupSummary{
+			Name:       groupName,
+			Properties: group.Properties,
+		}
+
+		for _, channelName := range slices.Sorted(maps.Keys(group.Channels)) {
+			ch := group.Channels[channelName]
+			groupSummary.Channels = append(groupSummary.Channels, ChannelSummary{
+				Name:      channelName,
+				DataType:  ch.DataType,
+				NumValues: ch.NumValues(),
+			})
+		}
+
+		summary.Groups = append(summary.Groups, groupSummary)
+	}
+
+	return summary, nil
+}
+
+// SegmentInfo describes the structure of a single segment of a TDMS file, as
+// returned by [File.Segments].
+type SegmentInfo struct {
+	// Offset is the absolute offset of the start of the segment's lead in.
+	Offset int64
+
+	// RawDataOffset is the absolute offset of the segment's raw data. It is
+	// zero if the segment has no raw data.
+	RawDataOffset int64
+
+	// ByteOrder is the byte order of the segment's metadata and raw data.
+	ByteOrder binary.ByteOrder
+
+	// ContainsMetadata indicates whether the segment has metadata of its own.
+	// If not, it reuses the metadata of the previous segment.
+	ContainsMetadata bool
+
+	// ContainsRawData indicates whether the segment has raw data.
+	ContainsRawData bool
+
+	// ContainsDAQmxRawData indicates whether the segment's raw data was
+	// written by DAQmx.
+	ContainsDAQmxRawData bool
+
+	// IsInterleaved indicates whether the values of the segment's channels
+	// are interleaved rather than stored one channel after another.
+	IsInterleaved bool
+
+	// NewObjectList indicates whether the segment's metadata replaces the
+	// list of objects of the previous segment rather than adding to it.
+	NewObjectList bool
+
+	// NumObjects is the number of objects in the segment's object list,
+	// including those carried over from previous segments.
+	NumObjects int
+
+	// NumChunks is the number of chunks of raw data in the segment, including
+	// a short or incomplete final chunk.
+	NumChunks uint64
+
+	// IsIncomplete indicates whether the final chunk of raw data was only
+	// partially written.
+	IsIncomplete bool
+}
+
+// Segments returns the structure of each segment of the file, in the order in
+// which they appear.
+func (t *File) Segments() []SegmentInfo {
+	infos := make([]SegmentInfo, 0, len(t.segments))
+
+	for _, segment := range t.segments {
+		info := SegmentInfo{
+			Offset:               segment.offset,
+			ByteOrder:            segment.leadIn.byteOrder,
+			ContainsMetadata:     segment.leadIn.containsMetadata,
+			ContainsRawData:      segment.leadIn.containsRawData,
+			ContainsDAQmxRawData: segment.leadIn.containsDAQMXRawData,
+			IsInterleaved:        segment.leadIn.isInterleaved,
+			NewObjectList:        segment.leadIn.newObjectList,
+			NumObjects:           len(segment.metadata.objectOrder),
+			NumChunks:            segment.metadata.numChunks,
+			IsIncomplete:         segment.metadata.isIncomplete(),
+		}
+
+		if info.ContainsRawData {
+			info.RawDataOffset = segment.metadata.rawDataOffset
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// ParseStats describes the work done reading the metadata of a file, as
+// returned by [File.ParseStats]. This helps to explain why some files are slow
+// to open, e.g. because they have a huge number of tiny segments or of
+// properties.
+type ParseStats struct {
+	// Segments is the number of segments read.
+	Segments int
+
+	// Objects is the number of distinct objects in the file, including the
+	// root object and groups.
+	Objects int
+
+	// ObjectsRead is the number of objects read from the metadata of all
+	// segments. Objects written in more than one segment are counted each
+	// time.
+	ObjectsRead int
+
+	// PropertiesRead is the number of properties read from the metadata of
+	// all segments. Properties written in more than one segment are counted
+	// each time.
+	PropertiesRead int
+
+	// MetadataBytes is the number of bytes of lead ins and metadata read.
+	MetadataBytes int64
+
+	// MagicOffset is the offset of the first segment, which is only non-zero
+	// if the file was opened with [ScanForMagic] and has junk at the start.
+	MagicOffset int64
+
+	// Duration is how long it took to read the metadata.
+	Duration time.Duration
+}
+
+// ParseStats returns statistics about reading the metadata of the file when it
+// was opened.
+func (t *File) ParseStats() ParseStats {
+	return t.stats
+}
@@ -0,0 +1,339 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestInspectSupportedFile(t *testing.T) {
+	report, err := Inspect("testdata/standard.tdms")
+	if err != nil {
+		t.Fatalf("failed to inspect file: %v", err)
+	}
+
+	if !report.Supported() {
+		t.Errorf("expected file to be supported, got %+v", report)
+	}
+
+	if len(report.Channels) == 0 {
+		t.Error("expected channels in report")
+	}
+}
+
+func TestInspectDAQmx(t *testing.T) {
+	report, err := Inspect("testdata/raw.tdms")
+	if err != nil {
+		t.Fatalf("failed to inspect file: %v", err)
+	}
+
+	if report.Supported() {
+		t.Error("expected DAQmx file to be unsupported")
+	}
+
+	for _, ch := range report.Channels {
+		if ch.DataType == DataTypeDAQmxRawData && (ch.Supported || ch.Reason == "") {
+			t.Errorf("expected DAQmx channel %s/%s to be unsupported with a reason", ch.GroupName, ch.Name)
+		}
+	}
+}
+
+func TestInspectFixedPoint(t *testing.T) {
+	filename := writeTestFile(t, "test.tdms", buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'fixed'", values: []uint8{}, dataType: DataTypeFixedPoint},
+			{path: "/'group'/'float'", values: []float64{1, 2, 3}},
+		},
+	}))
+
+	report, err := Inspect(filename)
+	if err != nil {
+		t.Fatalf("failed to inspect file: %v", err)
+	}
+
+	if report.Supported() {
+		t.Error("expected file with fixed point channel to be unsupported")
+	}
+
+	if len(report.Channels) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(report.Channels))
+	}
+
+	fixed, float := report.Channels[0], report.Channels[1]
+	if fixed.Name != "fixed" || fixed.Supported || fixed.Reason == "" {
+		t.Errorf("expected fixed point channel to be unsupported, got %+v", fixed)
+	}
+	if float.Name != "float" || !float.Supported || float.Reason != "" {
+		t.Errorf("expected float channel to be supported, got %+v", float)
+	}
+}
+
+func TestInspectProblems(t *testing.T) {
+	segment := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+		},
+	}
+
+	unsupportedVersion := buildTestFile(segment)
+	binary.LittleEndian.PutUint32(unsupportedVersion[8:], 4711)
+
+	segment.objects[1].props = []Property{{Name: "fixed", TypeCode: DataTypeFixedPoint, Value: uint32(0)}}
+	unsupportedProperty := buildTestFile(segment)
+
+	for name, data := range map[string][]byte{
+		"unsupported version":       unsupportedVersion,
+		"unsupported property type": unsupportedProperty,
+	} {
+		t.Run(name, func(t *testing.T) {
+			report, err := Inspect(writeTestFile(t, "test.tdms", data))
+			if err != nil {
+				t.Fatalf("expected problem to be reported rather than returned, got %v", err)
+			}
+
+			if report.Supported() || len(report.Problems) != 1 {
+				t.Errorf("expected a single problem, got %+v", report)
+			}
+		})
+	}
+}
+
+func TestInspectIncomplete(t *testing.T) {
+	filename := writeTestFile(t, "test.tdms", buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{1, 2, 3}},
+		},
+		incomplete: true,
+	}))
+
+	report, err := Inspect(filename)
+	if err != nil {
+		t.Fatalf("failed to inspect file: %v", err)
+	}
+
+	if !report.IsIncomplete {
+		t.Error("expected file to be reported as incomplete")
+	}
+	if !report.Supported() {
+		t.Errorf("expected incomplete file to be supported, got %+v", report)
+	}
+}
+
+func TestInspectMissingFile(t *testing.T) {
+	if _, err := Inspect(filepath.Join(t.TempDir(), "missing.tdms")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestSegments(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+			},
+			numChunks: 2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}},
+				{path: "/'group'/'b'", values: []int32{3}},
+			},
+			appendObjects: true,
+			interleaved:   true,
+			bigEndian:     true,
+		},
+		testSegment{
+			noMetadata: true,
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{4}},
+				{path: "/'group'/'b'", values: []int32{5}},
+			},
+			interleaved: true,
+			bigEndian:   true,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{6, 7}},
+			},
+			incomplete: true,
+			truncate:   2,
+		},
+	)
+
+	expected := []SegmentInfo{
+		{ByteOrder: binary.LittleEndian, ContainsMetadata: true, ContainsRawData: true, NewObjectList: true, NumObjects: 2, NumChunks: 2},
+		{ByteOrder: binary.BigEndian, ContainsMetadata: true, ContainsRawData: true, IsInterleaved: true, NumObjects: 3, NumChunks: 1},
+		{ByteOrder: binary.BigEndian, ContainsRawData: true, IsInterleaved: true, NumObjects: 3, NumChunks: 1},
+		{ByteOrder: binary.LittleEndian, ContainsMetadata: true, ContainsRawData: true, NewObjectList: true, NumObjects: 1, NumChunks: 1, IsIncomplete: true},
+	}
+
+	segments := f.Segments()
+	if len(segments) != len(expected) {
+		t.Fatalf("expected %d segments, got %d", len(expected), len(segments))
+	}
+
+	for i, segment := range segments {
+		if i == 0 && segment.Offset != 0 {
+			t.Errorf("expected first segment at offset 0, got %d", segment.Offset)
+		}
+		if i > 0 && segment.Offset <= segments[i-1].RawDataOffset {
+			t.Errorf("segment %d: expected offset after previous raw data, got %d", i, segment.Offset)
+		}
+		if segment.RawDataOffset <= segment.Offset {
+			t.Errorf("segment %d: expected raw data after lead in, got %d", i, segment.RawDataOffset)
+		}
+
+		segment.Offset = 0
+		segment.RawDataOffset = 0
+		if segment != expected[i] {
+			t.Errorf("segment %d: expected %+v, got %+v", i, expected[i], segment)
+		}
+	}
+}
+
+func TestSegmentsDAQmx(t *testing.T) {
+	f, err := Open("testdata/raw.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	segments := f.Segments()
+	if len(segments) == 0 || !segments[0].ContainsDAQmxRawData {
+		t.Errorf("expected DAQmx raw data in first segment, got %+v", segments)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	first := testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "first"}}},
+			{path: "/'b'"},
+			{path: "/'b'/'y'", values: []float64{1, 2}},
+			{path: "/'b'/'x'", values: []int32{1, 2}},
+			{path: "/'a'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+		},
+	}
+	second := testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "second"}}},
+			{path: "/'b'/'y'", values: []float64{3}},
+			{path: "/'b'/'x'", values: []int32{3}},
+			{path: "/'c'"},
+		},
+	}
+
+	expectedGroups := []GroupSummary{
+		{Name: "a", Properties: map[string]Property{"gain": {Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+		{Name: "b", Properties: map[string]Property{}, Channels: []ChannelSummary{
+			{Name: "x", DataType: DataTypeInt32, NumValues: 2},
+			{Name: "y", DataType: DataTypeFloat64, NumValues: 2},
+		}},
+	}
+
+	for _, segments := range [][]testSegment{{first}, {first, second}} {
+		summary, err := Summarize(writeTestFile(t, "test.tdms", buildTestFile(segments...)))
+		if err != nil {
+			t.Fatalf("failed to summarize file: %v", err)
+		}
+
+		if expected := len(segments) > 1; summary.Approximate != expected {
+			t.Errorf("%d segments: expected approximate %v, got %v", len(segments), expected, summary.Approximate)
+		}
+
+		if name := summary.Properties["name"].Value; name != "first" {
+			t.Errorf("%d segments: expected properties of first segment, got name %v", len(segments), name)
+		}
+
+		if !slices.EqualFunc(summary.Groups, expectedGroups, func(a, b GroupSummary) bool {
+			return a.Name == b.Name && maps.Equal(a.Properties, b.Properties) && slices.Equal(a.Channels, b.Channels)
+		}) {
+			t.Errorf("%d segments: expected groups %+v, got %+v", len(segments), expectedGroups, summary.Groups)
+		}
+	}
+}
+
+func TestSummarizeTestData(t *testing.T) {
+	summary, err := Summarize("testdata/standard.tdms")
+	if err != nil {
+		t.Fatalf("failed to summarize file: %v", err)
+	}
+
+	f, err := Open("testdata/standard.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	if !summary.Approximate || len(f.Segments()) < 2 {
+		t.Errorf("expected approximate summary of multi-segment file")
+	}
+
+	for _, group := range summary.Groups {
+		for _, ch := range group.Channels {
+			full := testChannel(t, f, group.Name, ch.Name)
+			if ch.DataType != full.DataType || ch.NumValues > full.NumValues() {
+				t.Errorf("%s/%s: summary %+v doesn't match channel with %d values of %s", group.Name, ch.Name, ch, full.NumValues(), full.DataType)
+			}
+		}
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	data := buildTestFile(
+		testSegment{
+			objects: []testObject{
+				{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "test"}}},
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1}, props: []Property{
+					{Name: "x", TypeCode: DataTypeInt32, Value: int32(1)},
+					{Name: "y", TypeCode: DataTypeInt32, Value: int32(2)},
+				}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{2}, props: []Property{
+					{Name: "x", TypeCode: DataTypeInt32, Value: int32(3)},
+				}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}},
+			},
+			noMetadata: true,
+		},
+	)
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := f.ParseStats()
+
+	// Each segment has a lead in and one int32 value of raw data, and
+	// everything else is metadata.
+	expectedMetadataBytes := int64(len(data) - 3*4)
+
+	if stats.Segments != 3 || stats.Objects != 3 || stats.ObjectsRead != 4 || stats.PropertiesRead != 4 ||
+		stats.MetadataBytes != expectedMetadataBytes {
+		t.Errorf(
+			"expected 3 segments, 3 objects, 4 objects read, 4 properties read and %d metadata bytes, got %+v",
+			expectedMetadataBytes,
+			stats,
+		)
+	}
+
+	if stats.Duration < 0 {
+		t.Errorf("expected non-negative duration, got %v", stats.Duration)
+	}
+}
@@ -0,0 +1,190 @@
+package tdms
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// WriteJSON writes the values of the channel to w as a JSON array, reading
+// them in batches so that the whole channel is never held in memory. This is
+// suited to streaming a channel straight into e.g. an HTTP response body.
+//
+// Numbers and bools are written bare, strings are quoted, timestamps are
+// written as RFC 3339 strings in UTC, and complex values as objects of the
+// form {"real":1,"imag":2}. As JSON has no way to represent them, NaN and
+// infinite floats are written as null. Float128 values are converted to
+// float64, and so may lose precision.
+//
+// If reading the channel fails part way through, the array written so far is
+// left unterminated and the error is returned. Returns ErrUnsupportedType if
+// the channel has a data type which can't be read.
+func (ch *Channel) WriteJSON(w io.Writer, options ...ReadOption) error {
+	bw := bufio.NewWriter(w)
+
+	var err error
+	switch ch.DataType {
+	case DataTypeInt8:
+		err = writeJSONValues(bw, ch, options, DataTypeInt8, interpretInt8, appendJSONInt)
+	case DataTypeInt16:
+		err = writeJSONValues(bw, ch, options, DataTypeInt16, interpretInt16, appendJSONInt)
+	case DataTypeInt32:
+		err = writeJSONValues(bw, ch, options, DataTypeInt32, interpretInt32, appendJSONInt)
+	case DataTypeInt64:
+		err = writeJSONValues(bw, ch, options, DataTypeInt64, interpretInt64, appendJSONInt)
+	case DataTypeUint8:
+		err = writeJSONValues(bw, ch, options, DataTypeUint8, interpretUint8, appendJSONUint)
+	case DataTypeUint16:
+		err = writeJSONValues(bw, ch, options, DataTypeUint16, interpretUint16, appendJSONUint)
+	case DataTypeUint32:
+		err = writeJSONValues(bw, ch, options, DataTypeUint32, interpretUint32, appendJSONUint)
+	case DataTypeUint64:
+		err = writeJSONValues(bw, ch, options, DataTypeUint64, interpretUint64, appendJSONUint)
+	case DataTypeFloat32:
+		err = writeJSONValues(bw, ch, options, DataTypeFloat32, interpretFloat32, appendJSONFloat32)
+	case DataTypeFloat64:
+		err = writeJSONValues(bw, ch, options, DataTypeFloat64, interpretFloat64, appendJSONFloat64)
+	case DataTypeFloat128:
+		err = writeJSONValues(bw, ch, options, DataTypeFloat128, interpretFloat128AsFloat64, appendJSONFloat64)
+	case DataTypeString:
+		err = writeJSONValues(bw, ch, options, DataTypeString, interpretString, appendJSONString)
+	case DataTypeBool:
+		err = writeJSONValues(bw, ch, options, DataTypeBool, interpretBool, strconv.AppendBool)
+	case DataTypeTimestamp:
+		err = writeJSONValues(bw, ch, options, DataTypeTimestamp, interpretTime, appendJSONTime)
+	case DataTypeComplex64:
+		err = writeJSONValues(bw, ch, options, DataTypeComplex64, interpretComplex64, appendJSONComplex64)
+	case DataTypeComplex128:
+		err = writeJSONValues(bw, ch, options, DataTypeComplex128, interpretComplex128, appendJSONComplex128)
+	case DataTypeVoid:
+		if ch.totalNumValues != 0 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedType, ch.DataType)
+		}
+		_, err = bw.WriteString("[]")
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, ch.DataType)
+	}
+
+	if err != nil {
+		_ = bw.Flush()
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeJSONValues writes the values of the channel as a JSON array, using
+// appendValue to format each value.
+func writeJSONValues[T any](
+	w *bufio.Writer,
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	appendValue func([]byte, T) []byte,
+) error {
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+
+	var buf []byte
+	first := true
+	for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+		if err != nil {
+			return err
+		}
+
+		for _, value := range batch {
+			buf = buf[:0]
+			if !first {
+				buf = append(buf, ',')
+			}
+			first = false
+
+			buf = appendValue(buf, value)
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.WriteByte(']')
+}
+
+func appendJSONInt[T int8 | int16 | int32 | int64](buf []byte, value T) []byte {
+	return strconv.AppendInt(buf, int64(value), 10)
+}
+
+func appendJSONUint[T uint8 | uint16 | uint32 | uint64](buf []byte, value T) []byte {
+	return strconv.AppendUint(buf, uint64(value), 10)
+}
+
+func appendJSONFloat(buf []byte, value float64, bitSize int) []byte {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return append(buf, "null"...)
+	}
+
+	return strconv.AppendFloat(buf, value, 'g', -1, bitSize)
+}
+
+func appendJSONFloat32(buf []byte, value float32) []byte {
+	return appendJSONFloat(buf, float64(value), 32)
+}
+
+func appendJSONFloat64(buf []byte, value float64) []byte {
+	return appendJSONFloat(buf, value, 64)
+}
+
+func appendJSONComplex(buf []byte, realValue, imagValue float64, bitSize int) []byte {
+	buf = append(buf, `{"real":`...)
+	buf = appendJSONFloat(buf, realValue, bitSize)
+	buf = append(buf, `,"imag":`...)
+	buf = appendJSONFloat(buf, imagValue, bitSize)
+	return append(buf, '}')
+}
+
+func appendJSONComplex64(buf []byte, value complex64) []byte {
+	return appendJSONComplex(buf, float64(real(value)), float64(imag(value)), 32)
+}
+
+func appendJSONComplex128(buf []byte, value complex128) []byte {
+	return appendJSONComplex(buf, real(value), imag(value), 64)
+}
+
+func appendJSONTime(buf []byte, value time.Time) []byte {
+	buf = append(buf, '"')
+	buf = value.UTC().AppendFormat(buf, time.RFC3339Nano)
+	return append(buf, '"')
+}
+
+// appendJSONString appends s as a quoted JSON string. Invalid UTF-8 is
+// replaced with the Unicode replacement character.
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	buf = append(buf, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf = append(buf, '\\', byte(r))
+		case r == '\n':
+			buf = append(buf, '\\', 'n')
+		case r == '\r':
+			buf = append(buf, '\\', 'r')
+		case r == '\t':
+			buf = append(buf, '\\', 't')
+		case r < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xF])
+		default:
+			// Ranging over a string gives utf8.RuneError for invalid bytes,
+			// which is appended as the replacement character.
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+
+	return append(buf, '"')
+}
@@ -0,0 +1,80 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWriteJSON(t *testing.T) {
+	timestamp := NewTimestampFromTime(time.Date(2024, 3, 1, 12, 30, 0, 500_000_000, time.UTC))
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'int'", values: []int16{-1, 0, 300}},
+			{path: "/'group'/'uint'", values: []uint64{math.MaxUint64}},
+			{path: "/'group'/'float'", values: []float64{0.5, math.NaN(), math.Inf(1), 1e300}},
+			{path: "/'group'/'float32'", values: []float32{0.1}},
+			{path: "/'group'/'string'", values: []string{"plain", `"quoted" \\`, "line\nbreak\x01", "\xff"}},
+			{path: "/'group'/'bool'", values: []bool{true, false}},
+			{path: "/'group'/'time'", values: []Timestamp{timestamp}},
+			{path: "/'group'/'complex'", values: []complex64{complex(1, -2.5)}},
+			{path: "/'group'/'empty'"},
+		},
+		numChunks: 2,
+	})
+
+	tests := []struct {
+		channel  string
+		expected string
+	}{
+		{"int", `[-1,0,300,-1,0,300]`},
+		{"uint", `[18446744073709551615,18446744073709551615]`},
+		{"float", `[0.5,null,null,1e+300,0.5,null,null,1e+300]`},
+		{"float32", `[0.1,0.1]`},
+		{"string", `["plain","\"quoted\" \\\\","line\nbreak\u0001","` + "�" + `","plain","\"quoted\" \\\\","line\nbreak\u0001","` + "�" + `"]`},
+		{"bool", `[true,false,true,false]`},
+		{"time", `["2024-03-01T12:30:00.5Z","2024-03-01T12:30:00.5Z"]`},
+		{"complex", `[{"real":1,"imag":-2.5},{"real":1,"imag":-2.5}]`},
+		{"empty", `[]`},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := testChannel(t, f, "group", tt.channel).WriteJSON(&buf, BatchSize(3)); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.channel, err)
+			continue
+		}
+
+		if buf.String() != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.channel, tt.expected, buf.String())
+		}
+
+		if !json.Valid(buf.Bytes()) {
+			t.Errorf("%s: output is not valid JSON: %s", tt.channel, buf.String())
+		}
+	}
+}
+
+func TestWriteJSONMetadataOnly(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1}},
+		},
+	})
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)), MetadataOnly())
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := testChannel(t, f, "group", "a").WriteJSON(&buf); !errors.Is(err, ErrMetadataOnly) {
+		t.Errorf("expected ErrMetadataOnly, got %v", err)
+	}
+}
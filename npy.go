@@ -0,0 +1,162 @@
+package tdms
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// npyMagic starts every NumPy .npy file, and is followed by the version.
+const npyMagic = "\x93NUMPY"
+
+// WriteNPY writes the values of the channel to w as a version 1.0 NumPy .npy
+// file holding a one dimensional array, which numpy.load can read directly.
+// The values are read in batches and written as they are read, so the whole
+// channel is never held in memory.
+//
+// The values are always written little endian, whatever the byte order of the
+// file, with the NumPy type matching the channel's data type, e.g. "<f8" for
+// float64 or "|u1" for uint8. Float128 values are converted to float64, as
+// NumPy has no portable 128-bit float, and timestamps are written as
+// datetime64[ns], i.e. nanoseconds since the Unix epoch, which only covers the
+// years 1678 to 2262.
+//
+// If reading the channel fails part way through, the file written so far is
+// left incomplete and the error is returned. Returns ErrUnsupportedType for
+// string channels, and any other data type which can't be read.
+func (ch *Channel) WriteNPY(w io.Writer, options ...ReadOption) error {
+	bw := bufio.NewWriter(w)
+
+	var err error
+	switch ch.DataType {
+	case DataTypeInt8:
+		err = writeNPYValues(bw, ch, options, "|i1", DataTypeInt8, interpretInt8)
+	case DataTypeInt16:
+		err = writeNPYValues(bw, ch, options, "<i2", DataTypeInt16, interpretInt16)
+	case DataTypeInt32:
+		err = writeNPYValues(bw, ch, options, "<i4", DataTypeInt32, interpretInt32)
+	case DataTypeInt64:
+		err = writeNPYValues(bw, ch, options, "<i8", DataTypeInt64, interpretInt64)
+	case DataTypeUint8:
+		err = writeNPYValues(bw, ch, options, "|u1", DataTypeUint8, interpretUint8)
+	case DataTypeUint16:
+		err = writeNPYValues(bw, ch, options, "<u2", DataTypeUint16, interpretUint16)
+	case DataTypeUint32:
+		err = writeNPYValues(bw, ch, options, "<u4", DataTypeUint32, interpretUint32)
+	case DataTypeUint64:
+		err = writeNPYValues(bw, ch, options, "<u8", DataTypeUint64, interpretUint64)
+	case DataTypeFloat32:
+		err = writeNPYValues(bw, ch, options, "<f4", DataTypeFloat32, interpretFloat32)
+	case DataTypeFloat64:
+		err = writeNPYValues(bw, ch, options, "<f8", DataTypeFloat64, interpretFloat64)
+	case DataTypeFloat128:
+		err = writeNPYValues(bw, ch, options, "<f8", DataTypeFloat128, interpretFloat128AsFloat64)
+	case DataTypeBool:
+		err = writeNPYValues(bw, ch, options, "|b1", DataTypeBool, interpretBool)
+	case DataTypeTimestamp:
+		err = writeNPYValues(bw, ch, options, "<M8[ns]", DataTypeTimestamp, interpretUnixNano)
+	case DataTypeComplex64:
+		err = writeNPYValues(bw, ch, options, "<c8", DataTypeComplex64, interpretComplex64)
+	case DataTypeComplex128:
+		err = writeNPYValues(bw, ch, options, "<c16", DataTypeComplex128, interpretComplex128)
+	case DataTypeVoid:
+		if ch.totalNumValues != 0 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedType, ch.DataType)
+		}
+		err = writeNPYHeader(bw, "<f8", 0)
+	default:
+		return fmt.Errorf("%w: cannot write %s channel as NPY", ErrUnsupportedType, ch.DataType)
+	}
+
+	if err != nil {
+		_ = bw.Flush()
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeNPYHeader writes the magic, version and header of a version 1.0 .npy
+// file holding a one dimensional array of numValues values of the given type.
+func writeNPYHeader(w io.Writer, descr string, numValues uint64) error {
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%d,), }", descr, numValues)
+
+	// The header is padded with spaces and ends with a newline, so that the
+	// data starts at a multiple of 64 bytes.
+	prefixSize := len(npyMagic) + 2 + 2
+	padding := 64 - (prefixSize+len(header)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	if len(header) > 0xFFFF {
+		return fmt.Errorf("NPY header is too long: %d bytes", len(header))
+	}
+
+	buf := make([]byte, 0, prefixSize+len(header))
+	buf = append(buf, npyMagic...)
+	buf = append(buf, 1, 0)
+	buf = binary.LittleEndian.AppendUint16(buf, uint16(len(header)))
+	buf = append(buf, header...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// writeNPYValues writes a .npy file of the values of the channel, encoding
+// each batch little endian.
+func writeNPYValues[T any](
+	w io.Writer,
+	ch *Channel,
+	options []ReadOption,
+	descr string,
+	dataType DataType,
+	interpret interpreter[T],
+) error {
+	if ch.f.opts.metadataOnly {
+		return ErrMetadataOnly
+	}
+
+	if err := writeNPYHeader(w, descr, ch.totalNumValues); err != nil {
+		return err
+	}
+
+	var buf []byte
+	numValues := uint64(0)
+	for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+		if err != nil {
+			return err
+		}
+
+		buf, err = binary.Append(buf[:0], binary.LittleEndian, batch)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		numValues += uint64(len(batch))
+	}
+
+	// The header gives the number of values up front, so the file is invalid
+	// if fewer were written.
+	if numValues != ch.totalNumValues {
+		return fmt.Errorf(
+			"%w: channel %s declares %d values but only %d could be read",
+			ErrLengthMismatch,
+			ch.path,
+			ch.totalNumValues,
+			numValues,
+		)
+	}
+
+	return nil
+}
+
+func interpretUnixNano(bytes []byte, order binary.ByteOrder) int64 {
+	return interpretTime(bytes, order).UnixNano()
+}
@@ -0,0 +1,111 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseNPY splits a version 1.0 .npy file into its header and data.
+func parseNPY(t *testing.T, data []byte) (string, []byte) {
+	t.Helper()
+
+	if len(data) < 10 || string(data[:6]) != npyMagic || data[6] != 1 || data[7] != 0 {
+		t.Fatalf("missing NPY magic or version: %q", data)
+	}
+
+	headerSize := int(binary.LittleEndian.Uint16(data[8:10]))
+	if len(data) < 10+headerSize {
+		t.Fatalf("NPY header of %d bytes overruns the file", headerSize)
+	}
+
+	if (10+headerSize)%64 != 0 {
+		t.Errorf("expected data to start at a multiple of 64 bytes, starts at %d", 10+headerSize)
+	}
+
+	header := string(data[10 : 10+headerSize])
+	if !strings.HasSuffix(header, "\n") {
+		t.Errorf("expected header to end with a newline: %q", header)
+	}
+
+	return strings.TrimRight(header, " \n"), data[10+headerSize:]
+}
+
+func TestWriteNPY(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 30, 0, 500_000_000, time.UTC)
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'int'", values: []int16{-1, 0, 300}},
+			{path: "/'group'/'uint8'", values: []uint8{1, 255}},
+			{path: "/'group'/'float'", values: []float64{0.5, math.Inf(-1)}},
+			{path: "/'group'/'bool'", values: []bool{true, false}},
+			{path: "/'group'/'time'", values: []Timestamp{NewTimestampFromTime(start)}},
+			{path: "/'group'/'complex'", values: []complex64{complex(1, -2.5)}},
+			{path: "/'group'/'empty'"},
+		},
+		numChunks: 2,
+		bigEndian: true,
+	})
+
+	tests := []struct {
+		channel string
+		descr   string
+		shape   string
+		values  any
+	}{
+		{"int", "<i2", "(6,)", []int16{-1, 0, 300, -1, 0, 300}},
+		{"uint8", "|u1", "(4,)", []uint8{1, 255, 1, 255}},
+		{"float", "<f8", "(4,)", []float64{0.5, math.Inf(-1), 0.5, math.Inf(-1)}},
+		{"bool", "|b1", "(4,)", []bool{true, false, true, false}},
+		{"time", "<M8[ns]", "(2,)", []int64{start.UnixNano(), start.UnixNano()}},
+		{"complex", "<c8", "(2,)", []complex64{complex(1, -2.5), complex(1, -2.5)}},
+		{"empty", "<f8", "(0,)", []float64{}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := testChannel(t, f, "group", tt.channel).WriteNPY(&buf, BatchSize(3)); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.channel, err)
+			continue
+		}
+
+		header, data := parseNPY(t, buf.Bytes())
+		expectedHeader := "{'descr': '" + tt.descr + "', 'fortran_order': False, 'shape': " + tt.shape + ", }"
+		if header != expectedHeader {
+			t.Errorf("%s: expected header %s, got %s", tt.channel, expectedHeader, header)
+		}
+
+		expectedData, err := binary.Append(nil, binary.LittleEndian, tt.values)
+		if err != nil {
+			t.Fatalf("%s: failed to encode expected values: %v", tt.channel, err)
+		}
+
+		if !bytes.Equal(data, expectedData) {
+			t.Errorf("%s: expected data % x, got % x", tt.channel, expectedData, data)
+		}
+	}
+}
+
+func TestWriteNPYUnsupported(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'string'", values: []string{"a"}},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := testChannel(t, f, "group", "string").WriteNPY(&buf); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %d bytes", buf.Len())
+	}
+}
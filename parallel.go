@@ -0,0 +1,127 @@
+package tdms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// ReadAllFloat64 reads every real numeric channel in the group, converting
+// their values to float64 as [Channel.ReadDataAsFloat64Coerced] does, and
+// returns them keyed by channel name. Channels of any other type, e.g. string
+// or timestamp channels, are skipped.
+//
+// Up to concurrency channels are read at once. If concurrency is less than 1,
+// runtime.GOMAXPROCS(0) is used. Channels are only read in parallel if the
+// underlying reader implements [io.ReaderAt], as files opened from a path do,
+// as otherwise every read has to share the same reader. If reading any channel
+// fails, the reads of the remaining channels are cancelled and the first error
+// is returned.
+func (g *Group) ReadAllFloat64(concurrency int, options ...ReadOption) (map[string][]float64, error) {
+	names := make([]string, 0, len(g.Channels))
+	for _, name := range slices.Sorted(maps.Keys(g.Channels)) {
+		if g.Channels[name].DataType.isRealNumeric() {
+			names = append(names, name)
+		}
+	}
+
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	readerAt, ok := g.f.data.(io.ReaderAt)
+	if !ok {
+		concurrency = 1
+	}
+	concurrency = min(concurrency, len(names))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		results  = make(map[string][]float64, len(names))
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	work := make(chan string)
+
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each worker reads through its own view of the file so that
+			// seeking in one doesn't move the others.
+			f := *g.f
+			if readerAt != nil {
+				f.data = io.NewSectionReader(readerAt, 0, f.dataSize)
+			}
+			f.data = &ctxReader{ctx: ctx, r: f.data}
+
+			for name := range work {
+				ch := g.Channels[name]
+				ch.f = &f
+
+				values, err := ch.ReadDataAsFloat64Coerced(options...)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to read channel %s: %w", ch.path, err)
+						cancel()
+					}
+				} else {
+					results[name] = values
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, name := range names {
+		select {
+		case work <- name:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
+
+// ctxReader is an [io.ReadSeeker] which fails once its context is cancelled,
+// so that long reads can be stopped part way through.
+type ctxReader struct {
+	ctx context.Context
+	r   io.ReadSeeker
+}
+
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Read(p)
+}
+
+func (cr *ctxReader) Seek(offset int64, whence int) (int64, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Seek(offset, whence)
+}
@@ -0,0 +1,99 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestGroupReadAllFloat64(t *testing.T) {
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []float64{1, 2}},
+				{path: "/'group'/'b'", values: []int16{3, 4}},
+				{path: "/'group'/'c'", values: []string{"x", "y"}},
+				{path: "/'group'/'d'", values: []uint8{5, 6}},
+			},
+			numChunks: 2,
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []float64{7}},
+			},
+		},
+	}
+
+	expected := map[string][]float64{
+		"a": {1, 2, 1, 2, 7},
+		"b": {3, 4, 3, 4},
+		"d": {5, 6, 5, 6},
+	}
+
+	check := func(t *testing.T, f *File, concurrency int) {
+		t.Helper()
+
+		group := f.Groups["group"]
+		results, err := group.ReadAllFloat64(concurrency)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) != len(expected) {
+			t.Errorf("expected %d channels, got %d", len(expected), len(results))
+		}
+
+		for name, values := range expected {
+			if !slices.Equal(results[name], values) {
+				t.Errorf("channel %s: expected %v, got %v", name, values, results[name])
+			}
+		}
+	}
+
+	data := buildTestFile(segments...)
+
+	t.Run("Parallel", func(t *testing.T) {
+		f := openTestFile(t, segments...)
+		check(t, f, 3)
+	})
+
+	t.Run("DefaultConcurrency", func(t *testing.T) {
+		f := openTestFile(t, segments...)
+		check(t, f, 0)
+	})
+
+	t.Run("FromPath", func(t *testing.T) {
+		f, err := Open(writeTestFile(t, "test.tdms", data))
+		if err != nil {
+			t.Fatalf("failed to open file: %v", err)
+		}
+		defer f.Close()
+
+		check(t, f, 4)
+	})
+
+	t.Run("NoReaderAt", func(t *testing.T) {
+		reader := struct{ io.ReadSeeker }{bytes.NewReader(data)}
+		f, err := New(reader, false, int64(len(data)))
+		if err != nil {
+			t.Fatalf("failed to parse test file: %v", err)
+		}
+
+		check(t, f, 4)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), MetadataOnly())
+		if err != nil {
+			t.Fatalf("failed to parse test file: %v", err)
+		}
+
+		group := f.Groups["group"]
+		if _, err := group.ReadAllFloat64(2); !errors.Is(err, ErrMetadataOnly) {
+			t.Errorf("expected ErrMetadataOnly, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package tdms
+
+import (
+	"context"
+	"iter"
+)
+
+// Result is a single value read from a channel, or the error which stopped the
+// read, as sent by [Chan].
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Chan bridges an iterator, e.g. from [Channel.ReadDataAsFloat64], to a Go
+// channel for use in select-based pipelines. Values are read by a new
+// goroutine and sent on the returned channel, which is closed once every value
+// has been sent, after sending a Result with an error if the read fails, or
+// when ctx is cancelled. The goroutine exits as soon as ctx is cancelled, so
+// cancel ctx if the channel isn't drained, or the goroutine will leak.
+func Chan[T any](ctx context.Context, seq iter.Seq2[T, error]) <-chan Result[T] {
+	results := make(chan Result[T])
+
+	go func() {
+		defer close(results)
+
+		for value, err := range seq {
+			select {
+			case results <- Result[T]{Value: value, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// CollectErr reads every value from an iterator, e.g. from
+// [Channel.ReadDataAsFloat64], into a slice, stopping at the first error.
+func CollectErr[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var values []T
+	for value, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// ReadDataFloat64Chan reads the float64 values of the channel in a new
+// goroutine and sends them on the returned Go channel. See [Chan] for how the
+// channel is closed and how cancelling ctx stops the read.
+func (ch *Channel) ReadDataFloat64Chan(ctx context.Context, options ...ReadOption) <-chan Result[float64] {
+	return Chan(ctx, ch.ReadDataAsFloat64(options...))
+}
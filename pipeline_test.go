@@ -0,0 +1,105 @@
+package tdms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadDataFloat64Chan(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{0.5, 1.5, 2.5}},
+		},
+		numChunks: 2,
+	})
+	ch := testChannel(t, f, "group", "a")
+
+	t.Run("all values", func(t *testing.T) {
+		var values []float64
+		for result := range ch.ReadDataFloat64Chan(context.Background(), BatchSize(2)) {
+			if result.Err != nil {
+				t.Fatalf("unexpected error: %v", result.Err)
+			}
+			values = append(values, result.Value)
+		}
+
+		if expected := []float64{0.5, 1.5, 2.5, 0.5, 1.5, 2.5}; !slices.Equal(values, expected) {
+			t.Errorf("expected %v, got %v", expected, values)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		results := ch.ReadDataFloat64Chan(ctx)
+
+		if result := <-results; result.Value != 0.5 {
+			t.Errorf("expected 0.5, got %v", result.Value)
+		}
+		cancel()
+
+		// The channel is closed once the goroutine sees the cancellation,
+		// possibly after sending one more value.
+		n := 0
+		for range results {
+			n++
+		}
+		if n > 1 {
+			t.Errorf("expected at most 1 value after cancelling, got %d", n)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		data := buildTestFile(testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []float64{0.5}},
+			},
+		})
+
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), MetadataOnly())
+		if err != nil {
+			t.Fatalf("failed to parse test file: %v", err)
+		}
+
+		var results []Result[float64]
+		for result := range testChannel(t, f, "group", "a").ReadDataFloat64Chan(context.Background()) {
+			results = append(results, result)
+		}
+
+		if len(results) != 1 || !errors.Is(results[0].Err, ErrMetadataOnly) {
+			t.Errorf("expected a single ErrMetadataOnly result, got %v", results)
+		}
+	})
+}
+
+func TestCollectErr(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int32{1, 2, 3}},
+		},
+	})
+
+	values, err := CollectErr(testChannel(t, f, "group", "a").ReadDataAsInt32(BatchSize(2)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []int32{1, 2, 3}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	failing := func(yield func(int, error) bool) {
+		if yield(1, nil) {
+			yield(0, ErrReadFailed)
+		}
+	}
+
+	if _, err := CollectErr(failing); !errors.Is(err, ErrReadFailed) {
+		t.Errorf("expected ErrReadFailed, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package tdms
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -19,12 +20,72 @@ type Property struct {
 	Value any
 }
 
+// RawTypeCode returns the type code of the property exactly as it is stored in
+// the file, which is the same as TypeCode. It is provided to match
+// [Channel.RawTypeCode].
+func (p Property) RawTypeCode() uint32 {
+	return uint32(p.TypeCode)
+}
+
 // String implements [fmt.Stringer] interface, returning the string
 // representation of the key and value.
 func (p Property) String() string {
 	return fmt.Sprintf("%s: %v", p.Name, p.Value)
 }
 
+// Format returns the property value in a canonical textual form, which is
+// deterministic and, other than for timestamps, lossless. Unlike String, only
+// the value is included, not the name.
+//
+// Floats are formatted with the fewest digits needed to represent them
+// exactly, including [Float128] values. Complex values are formatted as
+// "(real+imagi)" and timestamps are formatted as RFC 3339 in UTC with
+// nanosecond precision. Strings are returned as-is.
+func (p Property) Format() string {
+	switch v := p.Value.(type) {
+	case nil:
+		return ""
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case Float128:
+		bigFloat := v.AsBigFloat()
+		if bigFloat == nil {
+			return "NaN"
+		}
+		return bigFloat.Text('g', -1)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case Timestamp:
+		return v.AsTime().UTC().Format(time.RFC3339Nano)
+	case complex64:
+		return strconv.FormatComplex(complex128(v), 'g', -1, 64)
+	case complex128:
+		return strconv.FormatComplex(v, 'g', -1, 128)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 // AsInt8 returns the property value as an int8.
 // Returns ErrIncorrectType if the property is not of type DataTypeInt8.
 func (p Property) AsInt8() (int8, error) {
@@ -98,27 +159,30 @@ func (p Property) AsUint64() (uint64, error) {
 }
 
 // AsFloat32 returns the property value as a float32.
-// Returns ErrIncorrectType if the property is not of type DataTypeFloat32.
+// Returns ErrIncorrectType if the property is not of type DataTypeFloat32 or
+// DataTypeFloat32WithUnit.
 func (p Property) AsFloat32() (float32, error) {
-	if p.TypeCode != DataTypeFloat32 {
+	if p.TypeCode.baseType() != DataTypeFloat32 {
 		return 0, ErrIncorrectType
 	}
 	return p.Value.(float32), nil
 }
 
 // AsFloat64 returns the property value as a float64.
-// Returns ErrIncorrectType if the property is not of type DataTypeFloat64.
+// Returns ErrIncorrectType if the property is not of type DataTypeFloat64 or
+// DataTypeFloat64WithUnit.
 func (p Property) AsFloat64() (float64, error) {
-	if p.TypeCode != DataTypeFloat64 {
+	if p.TypeCode.baseType() != DataTypeFloat64 {
 		return 0, ErrIncorrectType
 	}
 	return p.Value.(float64), nil
 }
 
 // AsFloat128 returns the property value as a Float128.
-// Returns ErrIncorrectType if the property is not of type DataTypeFloat128.
+// Returns ErrIncorrectType if the property is not of type DataTypeFloat128 or
+// DataTypeFloat128WithUnit.
 func (p Property) AsFloat128() (Float128, error) {
-	if p.TypeCode != DataTypeFloat128 {
+	if p.TypeCode.baseType() != DataTypeFloat128 {
 		return Float128{}, ErrIncorrectType
 	}
 	return Float128(p.Value.(Float128)), nil
@@ -179,3 +243,147 @@ func (p Property) AsComplex128() (complex128, error) {
 	}
 	return p.Value.(complex128), nil
 }
+
+// PropertyResult is the result of looking up a property by name, as returned
+// by [Channel.GetTyped]. It distinguishes a property which is absent from one
+// which is present with a different type: the typed getters return false in
+// both cases, while Present and DataType tell them apart.
+type PropertyResult struct {
+	prop    Property
+	present bool
+}
+
+// Present returns whether the property exists.
+func (r PropertyResult) Present() bool {
+	return r.present
+}
+
+// DataType returns the data type of the property, or DataTypeVoid if the
+// property is absent.
+func (r PropertyResult) DataType() DataType {
+	if !r.present {
+		return DataTypeVoid
+	}
+	return r.prop.TypeCode
+}
+
+// Property returns the property itself, or false if it is absent.
+func (r PropertyResult) Property() (Property, bool) {
+	return r.prop, r.present
+}
+
+// propertyResultAs converts the property using as, returning false if it is
+// absent or has a different type.
+func propertyResultAs[T any](r PropertyResult, as func(Property) (T, error)) (T, bool) {
+	if !r.present {
+		return *new(T), false
+	}
+
+	value, err := as(r.prop)
+	if err != nil {
+		return *new(T), false
+	}
+	return value, true
+}
+
+// AsInt8 returns the property value as an int8, or false if the property is
+// absent or not of type DataTypeInt8.
+func (r PropertyResult) AsInt8() (int8, bool) {
+	return propertyResultAs(r, Property.AsInt8)
+}
+
+// AsInt16 returns the property value as an int16, or false if the property is
+// absent or not of type DataTypeInt16.
+func (r PropertyResult) AsInt16() (int16, bool) {
+	return propertyResultAs(r, Property.AsInt16)
+}
+
+// AsInt32 returns the property value as an int32, or false if the property is
+// absent or not of type DataTypeInt32.
+func (r PropertyResult) AsInt32() (int32, bool) {
+	return propertyResultAs(r, Property.AsInt32)
+}
+
+// AsInt64 returns the property value as an int64, or false if the property is
+// absent or not of type DataTypeInt64.
+func (r PropertyResult) AsInt64() (int64, bool) {
+	return propertyResultAs(r, Property.AsInt64)
+}
+
+// AsUint8 returns the property value as a uint8, or false if the property is
+// absent or not of type DataTypeUint8.
+func (r PropertyResult) AsUint8() (uint8, bool) {
+	return propertyResultAs(r, Property.AsUint8)
+}
+
+// AsUint16 returns the property value as a uint16, or false if the property is
+// absent or not of type DataTypeUint16.
+func (r PropertyResult) AsUint16() (uint16, bool) {
+	return propertyResultAs(r, Property.AsUint16)
+}
+
+// AsUint32 returns the property value as a uint32, or false if the property is
+// absent or not of type DataTypeUint32.
+func (r PropertyResult) AsUint32() (uint32, bool) {
+	return propertyResultAs(r, Property.AsUint32)
+}
+
+// AsUint64 returns the property value as a uint64, or false if the property is
+// absent or not of type DataTypeUint64.
+func (r PropertyResult) AsUint64() (uint64, bool) {
+	return propertyResultAs(r, Property.AsUint64)
+}
+
+// AsFloat32 returns the property value as a float32, or false if the property
+// is absent or not of type DataTypeFloat32.
+func (r PropertyResult) AsFloat32() (float32, bool) {
+	return propertyResultAs(r, Property.AsFloat32)
+}
+
+// AsFloat64 returns the property value as a float64, or false if the property
+// is absent or not of type DataTypeFloat64.
+func (r PropertyResult) AsFloat64() (float64, bool) {
+	return propertyResultAs(r, Property.AsFloat64)
+}
+
+// AsFloat128 returns the property value as a Float128, or false if the property
+// is absent or not of type DataTypeFloat128.
+func (r PropertyResult) AsFloat128() (Float128, bool) {
+	return propertyResultAs(r, Property.AsFloat128)
+}
+
+// AsString returns the property value as a string, or false if the property is
+// absent or not of type DataTypeString.
+func (r PropertyResult) AsString() (string, bool) {
+	return propertyResultAs(r, Property.AsString)
+}
+
+// AsBool returns the property value as a bool, or false if the property is
+// absent or not of type DataTypeBool.
+func (r PropertyResult) AsBool() (bool, bool) {
+	return propertyResultAs(r, Property.AsBool)
+}
+
+// AsTimestamp returns the property value as a Timestamp, or false if the
+// property is absent or not of type DataTypeTimestamp.
+func (r PropertyResult) AsTimestamp() (Timestamp, bool) {
+	return propertyResultAs(r, Property.AsTimestamp)
+}
+
+// AsTime returns the property value as a time.Time, or false if the property is
+// absent or not of type DataTypeTimestamp.
+func (r PropertyResult) AsTime() (time.Time, bool) {
+	return propertyResultAs(r, Property.AsTime)
+}
+
+// AsComplex64 returns the property value as a complex64, or false if the
+// property is absent or not of type DataTypeComplex64.
+func (r PropertyResult) AsComplex64() (complex64, bool) {
+	return propertyResultAs(r, Property.AsComplex64)
+}
+
+// AsComplex128 returns the property value as a complex128, or false if the
+// property is absent or not of type DataTypeComplex128.
+func (r PropertyResult) AsComplex128() (complex128, bool) {
+	return propertyResultAs(r, Property.AsComplex128)
+}
@@ -0,0 +1,173 @@
+package tdms
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComplexProperties(t *testing.T) {
+	for _, bigEndian := range []bool{false, true} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'", props: []Property{
+					{Name: "impedance", TypeCode: DataTypeComplex128, Value: complex(50.5, -3.25)},
+					{Name: "gain", TypeCode: DataTypeComplex64, Value: complex64(complex(1.5, 2))},
+					{Name: "after", TypeCode: DataTypeInt32, Value: int32(7)},
+				}},
+			},
+			bigEndian: bigEndian,
+		})
+
+		group := f.Groups["group"]
+
+		impedance, err := group.Properties["impedance"].AsComplex128()
+		if err != nil {
+			t.Fatalf("big endian %v: unexpected error: %v", bigEndian, err)
+		}
+		if impedance != complex(50.5, -3.25) {
+			t.Errorf("big endian %v: expected impedance (50.5-3.25i), got %v", bigEndian, impedance)
+		}
+
+		gain, err := group.Properties["gain"].AsComplex64()
+		if err != nil {
+			t.Fatalf("big endian %v: unexpected error: %v", bigEndian, err)
+		}
+		if gain != complex(1.5, 2) {
+			t.Errorf("big endian %v: expected gain (1.5+2i), got %v", bigEndian, gain)
+		}
+
+		// The property after the complex values is only read correctly if the
+		// complex values consumed exactly the right number of bytes.
+		if after, _ := group.Properties["after"].AsInt32(); after != 7 {
+			t.Errorf("big endian %v: expected after to be 7, got %v", bigEndian, after)
+		}
+
+		if _, err := group.Properties["impedance"].AsComplex64(); !errors.Is(err, ErrIncorrectType) {
+			t.Errorf("big endian %v: expected ErrIncorrectType, got %v", bigEndian, err)
+		}
+	}
+}
+
+func TestTimestampPropertyFieldOrder(t *testing.T) {
+	// These values were taken directly from the bytes of the files. In little
+	// endian files, the fractional part of the timestamp comes before the
+	// seconds, whereas in big endian files the seconds come first.
+	tests := []struct {
+		filename    string
+		groupName   string
+		channelName string
+		property    string
+		expected    Timestamp
+	}{
+		{
+			"testdata/raw_timestamps.tdms", "Untitled", "Untitled", "wf_start_time",
+			Timestamp{Timestamp: 3788905723, Remainder: 1265713805430620160},
+		},
+		{
+			"testdata/big_endian.tdms", "Measured Data", "Amplitude sweep", "NI_ExpStartTimeStamp",
+			Timestamp{Timestamp: 3624995089, Remainder: 7444837212136407040},
+		},
+	}
+
+	for _, tt := range tests {
+		f, err := Open(tt.filename)
+		if err != nil {
+			t.Fatalf("failed to open file: %v", err)
+		}
+		defer f.Close()
+
+		ch := testChannel(t, f, tt.groupName, tt.channelName)
+		timestamp, err := ch.Properties[tt.property].AsTimestamp()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.filename, err)
+		}
+
+		if timestamp != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.filename, tt.expected, timestamp)
+		}
+	}
+}
+
+func TestPropertyFormat(t *testing.T) {
+	timestamp := Timestamp{Timestamp: 3788905723, Remainder: 1 << 63}
+
+	tests := []struct {
+		prop     Property
+		expected string
+	}{
+		{Property{TypeCode: DataTypeVoid}, ""},
+		{Property{TypeCode: DataTypeInt8, Value: int8(-8)}, "-8"},
+		{Property{TypeCode: DataTypeInt64, Value: int64(-1 << 62)}, "-4611686018427387904"},
+		{Property{TypeCode: DataTypeUint64, Value: uint64(1<<64 - 1)}, "18446744073709551615"},
+		{Property{TypeCode: DataTypeFloat32, Value: float32(0.1)}, "0.1"},
+		{Property{TypeCode: DataTypeFloat64, Value: 0.1}, "0.1"},
+		{Property{TypeCode: DataTypeFloat64, Value: 1e300}, "1e+300"},
+		{Property{TypeCode: DataTypeFloat128, Value: testFloat128(0.1)}, "0.1000000000000000055511151231257827"},
+		{Property{TypeCode: DataTypeFloat128, Value: testFloat128(-2)}, "-2"},
+		{Property{TypeCode: DataTypeString, Value: "hello world"}, "hello world"},
+		{Property{TypeCode: DataTypeBool, Value: true}, "true"},
+		{Property{TypeCode: DataTypeTimestamp, Value: timestamp}, timestamp.AsTime().UTC().Format(time.RFC3339Nano)},
+		{Property{TypeCode: DataTypeComplex64, Value: complex64(complex(1.5, -0.1))}, "(1.5-0.1i)"},
+		{Property{TypeCode: DataTypeComplex128, Value: complex(1.5, 0.1)}, "(1.5+0.1i)"},
+	}
+
+	for _, tt := range tests {
+		if actual := tt.prop.Format(); actual != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.prop.TypeCode, tt.expected, actual)
+		}
+	}
+
+	if actual := (Property{TypeCode: DataTypeTimestamp, Value: timestamp}).Format(); !strings.HasSuffix(actual, ".5Z") {
+		t.Errorf("expected timestamp with half a second in UTC, got %q", actual)
+	}
+}
+
+func TestGetTyped(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'channel'",
+				values: []float64{1},
+				props: []Property{
+					{Name: "gain", TypeCode: DataTypeFloat64, Value: 2.5},
+					{Name: "unit_string", TypeCode: DataTypeString, Value: "V"},
+				},
+			},
+		},
+	})
+	ch := testChannel(t, f, "group", "channel")
+
+	gain := ch.GetTyped("gain")
+	if !gain.Present() || gain.DataType() != DataTypeFloat64 {
+		t.Errorf("expected gain to be present with type %s, got %t and %s", DataTypeFloat64, gain.Present(), gain.DataType())
+	}
+
+	if value, ok := gain.AsFloat64(); !ok || value != 2.5 {
+		t.Errorf("expected gain 2.5, got %v (%t)", value, ok)
+	}
+
+	// Present with a different type.
+	if value, ok := gain.AsInt32(); ok || value != 0 {
+		t.Errorf("expected float64 gain not to be an int32, got %v (%t)", value, ok)
+	}
+
+	if value, ok := ch.GetTyped("unit_string").AsString(); !ok || value != "V" {
+		t.Errorf("expected unit V, got %q (%t)", value, ok)
+	}
+
+	missing := ch.GetTyped("missing")
+	if missing.Present() || missing.DataType() != DataTypeVoid {
+		t.Errorf("expected missing property to be absent, got %t and %s", missing.Present(), missing.DataType())
+	}
+
+	if _, ok := missing.AsFloat64(); ok {
+		t.Errorf("expected no value for missing property")
+	}
+
+	if _, ok := missing.Property(); ok {
+		t.Errorf("expected no property for missing property")
+	}
+}
@@ -209,11 +209,46 @@ func interpretFloat128(bytes []byte, order binary.ByteOrder) Float128 {
 	// Probably not as fast as the bit shifting method from binary.LittleEndian,
 	// but hey. We store the value as little endian so it's standardised and we
 	// don't need to know the byte order when we convert it to another type.
+	// The bytes may belong to the caller, so only the copy is reversed.
+	value := Float128(bytes)
 	if order == binary.BigEndian {
-		slices.Reverse(bytes)
+		slices.Reverse(value[:])
 	}
 
-	return Float128(bytes)
+	return value
+}
+
+func interpretFloat128AsFloat64(bytes []byte, order binary.ByteOrder) float64 {
+	return interpretFloat128(bytes, order).AsFloat64()
+}
+
+// interpretAsFloat64 wraps the interpreter for a real numeric type so that the
+// values it interprets are converted to float64.
+func interpretAsFloat64[T int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64](
+	interpret interpreter[T],
+) interpreter[float64] {
+	return func(bytes []byte, order binary.ByteOrder) float64 {
+		return float64(interpret(bytes, order))
+	}
+}
+
+func interpretAsFloat32[T int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64](
+	interpret interpreter[T],
+) interpreter[float32] {
+	return func(bytes []byte, order binary.ByteOrder) float32 {
+		return float32(interpret(bytes, order))
+	}
+}
+
+// interpretComplexAs returns an interpreter which applies f to each complex
+// value, e.g. to take its magnitude.
+func interpretComplexAs[T complex64 | complex128](
+	interpret interpreter[T],
+	f func(complex128) float64,
+) interpreter[float64] {
+	return func(bytes []byte, order binary.ByteOrder) float64 {
+		return f(complex128(interpret(bytes, order)))
+	}
 }
 
 func interpretString(bytes []byte, order binary.ByteOrder) string {
@@ -228,17 +263,24 @@ func interpretBool(bytes []byte, order binary.ByteOrder) bool {
 }
 
 func interpretTimestamp(bytes []byte, order binary.ByteOrder) Timestamp {
+	// A timestamp is stored as if it were a single 128-bit fixed point number,
+	// so in little endian the fractional part comes first, whereas in big
+	// endian the seconds come first.
+	if order == binary.BigEndian {
+		return Timestamp{
+			Timestamp: int64(order.Uint64(bytes)),
+			Remainder: order.Uint64(bytes[8:]),
+		}
+	}
+
 	return Timestamp{
-		Timestamp: int64(order.Uint64(bytes)),
-		Remainder: order.Uint64(bytes[8:]),
+		Timestamp: int64(order.Uint64(bytes[8:])),
+		Remainder: order.Uint64(bytes),
 	}
 }
 
 func interpretTime(bytes []byte, order binary.ByteOrder) time.Time {
-	t := Timestamp{
-		Timestamp: int64(order.Uint64(bytes)),
-		Remainder: order.Uint64(bytes[8:]),
-	}
+	t := interpretTimestamp(bytes, order)
 	return t.AsTime()
 }
 
@@ -330,3 +372,19 @@ func parsePath(path string) (string, string, error) {
 
 	return groupName, channelName, nil
 }
+
+// formatPath is the inverse of parsePath, building the object path for the
+// given group and channel names. Leave the channel name empty for a group path
+// and both names empty for the root path.
+func formatPath(groupName, channelName string) string {
+	if groupName == "" {
+		return "/"
+	}
+
+	path := "/'" + strings.ReplaceAll(groupName, "'", "''") + "'"
+	if channelName != "" {
+		path += "/'" + strings.ReplaceAll(channelName, "'", "''") + "'"
+	}
+
+	return path
+}
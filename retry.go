@@ -0,0 +1,99 @@
+package tdms
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// RetryReads retries reading channel data up to retries times when the
+// underlying reader returns an error, waiting backoff before the first retry
+// and doubling the wait for each retry after that. This is useful when reading
+// from network-backed readers which occasionally fail.
+//
+// Before each retry, the reader is seeked back to where the failed read
+// started. Reaching the end of the file is not treated as a transient error
+// and is never retried.
+func RetryReads(retries int, backoff time.Duration) ReadOption {
+	return func(opts *readOptions) {
+		opts.retries = retries
+		opts.retryBackoff = backoff
+	}
+}
+
+// retryReader wraps a reader, retrying failed reads and seeks.
+type retryReader struct {
+	r       io.ReadSeeker
+	retries int
+	backoff time.Duration
+
+	// pos is the position of r after the last successful read or seek.
+	pos int64
+}
+
+func (rr *retryReader) Read(p []byte) (int, error) {
+	var n int
+	err := rr.retry(func() error {
+		var err error
+		n, err = rr.r.Read(p)
+		if n > 0 {
+			// Return what we have and let the caller read the rest.
+			rr.pos += int64(n)
+			return nil
+		}
+		return err
+	})
+
+	return n, err
+}
+
+func (rr *retryReader) Seek(offset int64, whence int) (int64, error) {
+	// Relative seeks can't be safely retried because we don't know whether the
+	// failed seek moved the reader, so make them absolute.
+	if whence == io.SeekCurrent {
+		offset += rr.pos
+		whence = io.SeekStart
+	}
+
+	var pos int64
+	err := rr.retry(func() error {
+		var err error
+		pos, err = rr.r.Seek(offset, whence)
+		return err
+	})
+	if err == nil {
+		rr.pos = pos
+	}
+
+	return pos, err
+}
+
+func (rr *retryReader) retry(op func() error) error {
+	backoff := rr.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= rr.retries || !isRetryable(err) {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+
+		// Return to where we were before the failed attempt, in case it
+		// partially succeeded.
+		if _, err := rr.r.Seek(rr.pos, io.SeekStart); err != nil && !isRetryable(err) {
+			return err
+		}
+	}
+}
+
+// isRetryable returns whether err could be caused by a transient failure in the
+// underlying reader.
+func isRetryable(err error) bool {
+	return !errors.Is(err, io.EOF) &&
+		!errors.Is(err, io.ErrUnexpectedEOF) &&
+		!errors.Is(err, os.ErrClosed) &&
+		!errors.Is(err, ErrInvalidFileFormat)
+}
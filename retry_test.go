@@ -0,0 +1,88 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+var errFlaky = errors.New("connection reset")
+
+// flakyReader fails every other read.
+type flakyReader struct {
+	*bytes.Reader
+	reads int
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	f.reads++
+	if f.reads%2 == 0 {
+		// Move the reader forward to check that we seek back before retrying.
+		_, _ = f.Reader.Seek(1, io.SeekCurrent)
+		return 0, errFlaky
+	}
+
+	return f.Reader.Read(p)
+}
+
+func TestRetryReads(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []int16{1, 2, 3, 4, 5, 6, 7}},
+		},
+		numChunks: 3,
+	})
+
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		t.Fatalf("failed to parse test file: %v", err)
+	}
+
+	ch := testChannel(t, f, "group", "channel")
+
+	// Only the data reads go through the flaky reader.
+	f.data = &flakyReader{Reader: bytes.NewReader(data)}
+
+	if _, err := ch.ReadDataInt16All(BatchSize(3)); !errors.Is(err, errFlaky) {
+		t.Fatalf("expected flaky error without retries, got %v", err)
+	}
+
+	values, err := ch.ReadDataInt16All(BatchSize(3), RetryReads(1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error with retries: %v", err)
+	}
+
+	expected := slices.Repeat([]int16{1, 2, 3, 4, 5, 6, 7}, 3)
+	if !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestRetryReaderDoesNotRetryEOF(t *testing.T) {
+	reader := &countingReader{}
+	rr := &retryReader{r: reader, retries: 5}
+
+	if _, err := rr.Read(make([]byte, 4)); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	if reader.reads != 1 {
+		t.Errorf("expected EOF not to be retried, got %d reads", reader.reads)
+	}
+}
+
+type countingReader struct {
+	reads int
+}
+
+func (c *countingReader) Read([]byte) (int, error) {
+	c.reads++
+	return 0, io.EOF
+}
+
+func (c *countingReader) Seek(int64, int) (int64, error) {
+	return 0, nil
+}
@@ -1,23 +1,421 @@
 package tdms
 
-// NI have a bunch of scaling functions, read from the property
-// "NI_Scale[i]_Scale_Type" where i is the scale index. This only applies if
-// "NI_Scaling_Status" is "scaled", otherwise the data is not scaled.
+import (
+	"fmt"
+	"math"
+)
+
+// Channels can specify scalings which convert the raw data values stored in
+// the file into meaningful values, e.g. converting a voltage into a
+// temperature. These are stored as a chain of scales in the channel properties,
+// where each scale takes either the raw data or the output of another scale as
+// its input:
+//
+//	NI_Number_Of_Scales = 2
+//	NI_Scale[0]_Scale_Type = "Linear"
+//	NI_Scale[0]_Linear_Slope = 2.0
+//	NI_Scale[0]_Linear_Y_Intercept = 1.0
+//	NI_Scale[0]_Linear_Input_Source = -1 (raw data)
+//	NI_Scale[1]_Scale_Type = "Polynomial"
+//	...
+//	NI_Scale[1]_Polynomial_Input_Source = 0
 //
-// DAQmxScaling works differently.
+// The output of the last scale in the chain is the scaled data.
 //
-// npTDMS supports the following scaling functions"
+// See: https://www.ni.com/docs/en-US/bundle/labview-api-ref/page/properties/tdms-file-properties.html
+
+const (
+	numberOfScalesProperty = "NI_Number_Of_Scales"
+	scalingStatusProperty  = "NI_Scaling_Status"
+
+	// scalingStatusScaled indicates that the data stored in the file has
+	// already been scaled, so the scaling mustn't be applied again.
+	scalingStatusScaled = "scaled"
+
+	// rawInputSource is the input source of a scale which takes the raw data
+	// as its input. It is written as either -1 or 0xFFFFFFFF depending on
+	// whether the property is signed.
+	rawInputSource = -1
+)
+
+// ScaleType is the kind of a [Scaling].
+type ScaleType string
+
+const (
+	// ScaleTypeLinear scales values by y = slope*x + intercept.
+	ScaleTypeLinear ScaleType = "Linear"
+
+	// ScaleTypePolynomial scales values by y = c0 + c1*x + c2*x^2 + ...
+	ScaleTypePolynomial ScaleType = "Polynomial"
+
+	// ScaleTypeAdvanced is a scale created through the DAQmx advanced API.
+	// These scales are not described by the file, so they are treated as
+	// leaving values unchanged.
+	ScaleTypeAdvanced ScaleType = "Advanced"
+
+	// ScaleTypeNone is the type of a scale with no Scale_Type property, which
+	// LabVIEW sometimes writes for unused scales. These scales take the raw
+	// data as input and leave values unchanged.
+	ScaleTypeNone ScaleType = ""
+)
+
+// Scaling is a single scale in the chain of scales of a channel.
+type Scaling struct {
+	// Type is the kind of this scale. Scales of types which aren't supported
+	// are kept with their type as written in the file, and treated as leaving
+	// values unchanged.
+	Type ScaleType
+
+	// InputSource is the index of the scale whose output is the input to this
+	// scale, or -1 if the input is the raw data.
+	InputSource int
+
+	// Slope is the slope of a linear scale.
+	Slope float64
+
+	// Intercept is the y intercept of a linear scale.
+	Intercept float64
+
+	// Coefficients are the coefficients of a polynomial scale, starting with
+	// the constant term.
+	Coefficients []float64
+}
+
+// IsSupported returns whether this scale can be applied to values. Advanced
+// and unrecognised scales aren't supported, and leave values unchanged. Scales
+// with no type are supported, as leaving values unchanged is exactly what they
+// do.
+func (s Scaling) IsSupported() bool {
+	return s.Type == ScaleTypeLinear || s.Type == ScaleTypePolynomial || s.Type == ScaleTypeNone
+}
+
+// Apply scales a single value. Unsupported scales return the value unchanged.
+func (s Scaling) Apply(value float64) float64 {
+	switch s.Type {
+	case ScaleTypeLinear:
+		return s.Slope*value + s.Intercept
+	case ScaleTypePolynomial:
+		// Horner's method.
+		result := 0.0
+		for i := len(s.Coefficients) - 1; i >= 0; i-- {
+			result = result*value + s.Coefficients[i]
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+type scalingOptions struct {
+	strict bool
+}
+
+// ScalingOption configures how scaling is applied by [Channel.ApplyScaling] and
+// [Channel.ReadDataRawAndScaled].
+type ScalingOption func(*scalingOptions)
+
+// StrictScaling makes [Channel.ApplyScaling] and [Channel.ReadDataRawAndScaled]
+// return ErrUnsupportedScaling if any scale in the chain can't be applied, e.g.
+// advanced API scales, instead of treating those scales as leaving values
+// unchanged.
+func StrictScaling() ScalingOption {
+	return func(opts *scalingOptions) {
+		opts.strict = true
+	}
+}
+
+// Scaling returns the chain of scales specified by the properties of this
+// channel, or an empty slice if the channel isn't scaled.
 //
-//   - polynomial
-//   - linear
-//   - RTD (convert signal from Resistance Temperature Detector (RTD) into degrees Celsius)
-//   - strain
-//   - table
-//   - thermistor
-//   - thermocouple
-//   - add
-//   - subtract
-//   - advanced API (taken as no-op)
+// Partial scaling properties are handled as LabVIEW does: a scale without a
+// Scale_Type property has type [ScaleTypeNone] and leaves values unchanged,
+// and a scale without an Input_Source property takes the raw data as its
+// input. Returns ErrMissingProperty if any other property required by one of
+// the scales is missing, e.g. the slope of a linear scale.
+func (ch *Channel) Scaling() ([]Scaling, error) {
+	numScales, ok, err := intProperty(ch.Properties, numberOfScalesProperty)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || numScales <= 0 {
+		return []Scaling{}, nil
+	}
+
+	// Every scale which does anything is described by properties of its own,
+	// so a count beyond the number of properties can only come from a corrupt
+	// file, and mustn't be used to size the scales.
+	if numScales > len(ch.Properties) {
+		return nil, fmt.Errorf(
+			"%w: channel %s has %d scales but only %d properties",
+			ErrInvalidFileFormat, ch.path, numScales, len(ch.Properties),
+		)
+	}
+
+	scales := make([]Scaling, numScales)
+	for i := range scales {
+		scale, err := readScaling(ch.Properties, i)
+		if err != nil {
+			return nil, err
+		}
+
+		if scale.InputSource != rawInputSource && (scale.InputSource < 0 || scale.InputSource >= numScales) {
+			return nil, fmt.Errorf("%w: scale %d has invalid input source %d", ErrInvalidFileFormat, i, scale.InputSource)
+		}
+
+		scales[i] = scale
+	}
+
+	return scales, nil
+}
+
+// ApplyScaling applies the scaling of this channel to the given raw values,
+// which are modified in place and returned. Channels without any scaling, or
+// whose NI_Scaling_Status property shows that the stored data is already
+// scaled, return the values unchanged.
 //
-// See: https://www.ni.com/docs/en-US/bundle/labwindows-cvi/page/cvi/libref/cvitdmslibraryfunctiontree.htm
-// (scroll down to "Advanced Data Scaling")
+// Scales which can't be applied, e.g. advanced API scales, leave values
+// unchanged unless the StrictScaling option is given, in which case
+// ErrUnsupportedScaling is returned.
+func (ch *Channel) ApplyScaling(values []float64, options ...ScalingOption) ([]float64, error) {
+	chain, err := ch.scalingChain(options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		values[i] = applyScalingChain(chain, value)
+	}
+
+	return values, nil
+}
+
+// ReadDataRawAndScaled reads all values from a channel of any real numeric
+// type, returning both the raw values, converted to float64 as by
+// [Channel.ReadDataAsFloat64Coerced], and the values with the scaling of the
+// channel applied as by [Channel.ApplyScaling] with the given options. The
+// channel is only read once, which is useful for showing raw and scaled values
+// side by side.
+func (ch *Channel) ReadDataRawAndScaled(options ...ScalingOption) ([]float64, []float64, error) {
+	chain, err := ch.scalingChain(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, err := ch.ReadDataAsFloat64Coerced()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scaled := make([]float64, len(raw))
+	for i, value := range raw {
+		scaled[i] = applyScalingChain(chain, value)
+	}
+
+	return raw, scaled, nil
+}
+
+// scalingChain returns the chain of scales leading from the raw data to the
+// final scale of the channel, in reverse order, or nil if the values of the
+// channel are used as they are.
+func (ch *Channel) scalingChain(options []ScalingOption) ([]Scaling, error) {
+	opts := scalingOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	if status, err := ch.Properties[scalingStatusProperty].AsString(); err == nil && status == scalingStatusScaled {
+		return nil, nil
+	}
+
+	scales, err := ch.Scaling()
+	if err != nil {
+		return nil, err
+	}
+	if len(scales) == 0 {
+		return nil, nil
+	}
+
+	// Work out the chain of scales leading to the final scale, starting from
+	// the raw data.
+	chain := make([]Scaling, 0, len(scales))
+	for i := len(scales) - 1; i != rawInputSource; i = scales[i].InputSource {
+		if len(chain) == len(scales) {
+			return nil, fmt.Errorf("%w: scales of channel %s form a cycle", ErrInvalidFileFormat, ch.path)
+		}
+
+		if opts.strict && !scales[i].IsSupported() {
+			return nil, fmt.Errorf("%w: scale %d of channel %s has type %s", ErrUnsupportedScaling, i, ch.path, scales[i].Type)
+		}
+
+		chain = append(chain, scales[i])
+	}
+
+	return chain, nil
+}
+
+// applyScalingChain applies a chain of scales, as returned by scalingChain, to
+// a single value.
+func applyScalingChain(chain []Scaling, value float64) float64 {
+	for j := len(chain) - 1; j >= 0; j-- {
+		value = chain[j].Apply(value)
+	}
+	return value
+}
+
+func readScaling(props map[string]Property, i int) (Scaling, error) {
+	prefix := fmt.Sprintf("NI_Scale[%d]_", i)
+
+	scale := Scaling{
+		Type:        ScaleTypeNone,
+		InputSource: rawInputSource,
+	}
+
+	scaleTypeProp, ok := props[prefix+"Scale_Type"]
+	if !ok {
+		return scale, nil
+	}
+
+	scaleType, err := scaleTypeProp.AsString()
+	if err != nil {
+		return Scaling{}, fmt.Errorf("%w: %sScale_Type", ErrMissingProperty, prefix)
+	}
+	scale.Type = ScaleType(scaleType)
+
+	switch scale.Type {
+	case ScaleTypeLinear:
+		prefix += "Linear_"
+		if scale.Slope, err = requiredFloatProperty(props, prefix+"Slope"); err != nil {
+			return Scaling{}, err
+		}
+		if scale.Intercept, err = requiredFloatProperty(props, prefix+"Y_Intercept"); err != nil {
+			return Scaling{}, err
+		}
+	case ScaleTypePolynomial:
+		prefix += "Polynomial_"
+		numCoefficients, ok, err := intProperty(props, prefix+"Coefficients_Size")
+		if err != nil {
+			return Scaling{}, err
+		}
+		if !ok {
+			return Scaling{}, fmt.Errorf("%w: %sCoefficients_Size", ErrMissingProperty, prefix)
+		}
+
+		// Each coefficient is a property of its own.
+		if numCoefficients > len(props) {
+			return Scaling{}, fmt.Errorf(
+				"%w: %sCoefficients_Size is %d but there are only %d properties",
+				ErrInvalidFileFormat, prefix, numCoefficients, len(props),
+			)
+		}
+
+		scale.Coefficients = make([]float64, max(numCoefficients, 0))
+		for j := range scale.Coefficients {
+			name := fmt.Sprintf("%sCoefficients[%d]", prefix, j)
+			if scale.Coefficients[j], err = requiredFloatProperty(props, name); err != nil {
+				return Scaling{}, err
+			}
+		}
+	case ScaleTypeAdvanced, "AdvancedAPI":
+		// These scales aren't described by any other properties, so they
+		// always take the raw data as input.
+		scale.Type = ScaleTypeAdvanced
+		return scale, nil
+	default:
+		return scale, nil
+	}
+
+	// A missing input source means the raw data, as for the default of -1.
+	inputSource, ok, err := intProperty(props, prefix+"Input_Source")
+	if err != nil {
+		return Scaling{}, err
+	}
+	if ok {
+		scale.InputSource = inputSource
+	}
+
+	return scale, nil
+}
+
+// intProperty returns the value of an integer property as an int, and whether
+// the property exists. Unsigned properties whose value is all ones are
+// returned as -1, as this is how unsigned properties represent -1.
+func intProperty(props map[string]Property, name string) (int, bool, error) {
+	prop, ok := props[name]
+	if !ok {
+		return 0, false, nil
+	}
+
+	switch v := prop.Value.(type) {
+	case int8:
+		return int(v), true, nil
+	case int16:
+		return int(v), true, nil
+	case int32:
+		return int(v), true, nil
+	case int64:
+		return int(v), true, nil
+	case uint8:
+		return int(v), true, nil
+	case uint16:
+		return int(v), true, nil
+	case uint32:
+		if v == math.MaxUint32 {
+			return -1, true, nil
+		}
+		return int(v), true, nil
+	case uint64:
+		if v == math.MaxUint64 {
+			return -1, true, nil
+		}
+		if v > math.MaxInt {
+			return 0, true, fmt.Errorf("%w: property %s value %d is out of range", ErrInvalidFileFormat, name, v)
+		}
+		return int(v), true, nil
+	case float64:
+		// Some writers store integral values as doubles. The upper bound is
+		// exclusive as math.MaxInt rounds up to a power of two as a float64.
+		if v == math.Trunc(v) {
+			if v < math.MinInt || v >= math.MaxInt {
+				return 0, true, fmt.Errorf("%w: property %s value %g is out of range", ErrInvalidFileFormat, name, v)
+			}
+			return int(v), true, nil
+		}
+	}
+
+	return 0, true, fmt.Errorf("%w: property %s has type %s, expected an integer", ErrIncorrectType, name, prop.TypeCode)
+}
+
+// requiredFloatProperty returns the value of a numeric property as a float64.
+func requiredFloatProperty(props map[string]Property, name string) (float64, error) {
+	prop, ok := props[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMissingProperty, name)
+	}
+
+	switch v := prop.Value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case Float128:
+		return v.AsFloat64(), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("%w: property %s has type %s, expected a number", ErrIncorrectType, name, prop.TypeCode)
+	}
+}
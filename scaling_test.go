@@ -0,0 +1,242 @@
+package tdms
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func scaledTestChannel(t *testing.T, props ...Property) *Channel {
+	t.Helper()
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: []float64{0, 1, 2}, props: props},
+		},
+	})
+
+	return testChannel(t, f, "group", "channel")
+}
+
+func TestApplyScaling(t *testing.T) {
+	ch := scaledTestChannel(t,
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeUint32, Value: uint32(2)},
+		Property{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+		Property{Name: "NI_Scale[0]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 2.0},
+		Property{Name: "NI_Scale[0]_Linear_Y_Intercept", TypeCode: DataTypeFloat64, Value: 1.0},
+		Property{Name: "NI_Scale[0]_Linear_Input_Source", TypeCode: DataTypeUint32, Value: uint32(0xFFFFFFFF)},
+		Property{Name: "NI_Scale[1]_Scale_Type", TypeCode: DataTypeString, Value: "Polynomial"},
+		Property{Name: "NI_Scale[1]_Polynomial_Coefficients_Size", TypeCode: DataTypeInt32, Value: int32(3)},
+		Property{Name: "NI_Scale[1]_Polynomial_Coefficients[0]", TypeCode: DataTypeFloat64, Value: 1.0},
+		Property{Name: "NI_Scale[1]_Polynomial_Coefficients[1]", TypeCode: DataTypeFloat64, Value: 0.0},
+		Property{Name: "NI_Scale[1]_Polynomial_Coefficients[2]", TypeCode: DataTypeFloat64, Value: 0.5},
+		Property{Name: "NI_Scale[1]_Polynomial_Input_Source", TypeCode: DataTypeInt32, Value: int32(0)},
+	)
+
+	values, err := ch.ReadDataFloat64All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scaled, err := ch.ApplyScaling(values, StrictScaling())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// x -> 2x + 1 -> 1 + 0.5(2x + 1)^2
+	if expected := []float64{1.5, 5.5, 13.5}; !slices.Equal(scaled, expected) {
+		t.Errorf("expected %v, got %v", expected, scaled)
+	}
+}
+
+func TestReadDataRawAndScaled(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'scaled'",
+				values: []int16{-2, 0, 4},
+				props: []Property{
+					{Name: "NI_Number_Of_Scales", TypeCode: DataTypeUint32, Value: uint32(1)},
+					{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+					{Name: "NI_Scale[0]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 0.5},
+					{Name: "NI_Scale[0]_Linear_Y_Intercept", TypeCode: DataTypeFloat64, Value: 10.0},
+				},
+			},
+			{path: "/'group'/'unscaled'", values: []float64{1, 2}},
+		},
+	})
+
+	raw, scaled, err := testChannel(t, f, "group", "scaled").ReadDataRawAndScaled()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := []float64{-2, 0, 4}; !slices.Equal(raw, expected) {
+		t.Errorf("raw: expected %v, got %v", expected, raw)
+	}
+
+	if expected := []float64{9, 10, 12}; !slices.Equal(scaled, expected) {
+		t.Errorf("scaled: expected %v, got %v", expected, scaled)
+	}
+
+	raw, scaled, err = testChannel(t, f, "group", "unscaled").ReadDataRawAndScaled()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(raw, []float64{1, 2}) || !slices.Equal(scaled, raw) {
+		t.Errorf("expected unscaled channel to have equal raw and scaled values, got %v and %v", raw, scaled)
+	}
+}
+
+func TestApplyScalingAdvanced(t *testing.T) {
+	ch := scaledTestChannel(t,
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(1)},
+		Property{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Advanced"},
+	)
+
+	scales, err := ch.Scaling()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scales) != 1 || scales[0].Type != ScaleTypeAdvanced || scales[0].IsSupported() {
+		t.Errorf("expected a single unsupported advanced scale, got %+v", scales)
+	}
+
+	scaled, err := ch.ApplyScaling([]float64{0, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{0, 1, 2}; !slices.Equal(scaled, expected) {
+		t.Errorf("expected advanced scale to leave values unchanged, got %v", scaled)
+	}
+
+	if _, err := ch.ApplyScaling([]float64{0, 1, 2}, StrictScaling()); !errors.Is(err, ErrUnsupportedScaling) {
+		t.Errorf("expected ErrUnsupportedScaling with strict scaling, got %v", err)
+	}
+
+	if _, _, err := ch.ReadDataRawAndScaled(StrictScaling()); !errors.Is(err, ErrUnsupportedScaling) {
+		t.Errorf("expected ErrUnsupportedScaling reading with strict scaling, got %v", err)
+	}
+}
+
+func TestApplyScalingAlreadyScaled(t *testing.T) {
+	ch := scaledTestChannel(t,
+		Property{Name: "NI_Scaling_Status", TypeCode: DataTypeString, Value: "scaled"},
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(1)},
+		Property{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+		Property{Name: "NI_Scale[0]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 2.0},
+		Property{Name: "NI_Scale[0]_Linear_Y_Intercept", TypeCode: DataTypeFloat64, Value: 1.0},
+		Property{Name: "NI_Scale[0]_Linear_Input_Source", TypeCode: DataTypeInt32, Value: int32(-1)},
+	)
+
+	scaled, err := ch.ApplyScaling([]float64{0, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{0, 1, 2}; !slices.Equal(scaled, expected) {
+		t.Errorf("expected already scaled values to be unchanged, got %v", scaled)
+	}
+}
+
+func TestScalingMissingProperty(t *testing.T) {
+	ch := scaledTestChannel(t,
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(1)},
+		Property{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+		Property{Name: "NI_Scale[0]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 2.0},
+	)
+
+	if _, err := ch.ApplyScaling([]float64{0}); !errors.Is(err, ErrMissingProperty) {
+		t.Errorf("expected ErrMissingProperty, got %v", err)
+	}
+}
+
+func TestScalingPartialProperties(t *testing.T) {
+	ch := scaledTestChannel(t,
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(3)},
+		// Scale 0 has no type at all.
+		Property{Name: "NI_Scale[1]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+		Property{Name: "NI_Scale[1]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 2.0},
+		Property{Name: "NI_Scale[1]_Linear_Y_Intercept", TypeCode: DataTypeFloat64, Value: 1.0},
+		// Scale 1 has no input source, so takes the raw data.
+		Property{Name: "NI_Scale[2]_Scale_Type", TypeCode: DataTypeString, Value: "Linear"},
+		Property{Name: "NI_Scale[2]_Linear_Slope", TypeCode: DataTypeFloat64, Value: 3.0},
+		Property{Name: "NI_Scale[2]_Linear_Y_Intercept", TypeCode: DataTypeFloat64, Value: 0.0},
+		Property{Name: "NI_Scale[2]_Linear_Input_Source", TypeCode: DataTypeInt32, Value: int32(1)},
+	)
+
+	scales, err := ch.Scaling()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(scales) != 3 {
+		t.Fatalf("expected 3 scales, got %+v", scales)
+	}
+	if scales[0].Type != ScaleTypeNone || scales[0].InputSource != -1 || !scales[0].IsSupported() {
+		t.Errorf("expected scale 0 to be a supported scale with no type taking raw data, got %+v", scales[0])
+	}
+	if scales[1].InputSource != -1 {
+		t.Errorf("expected scale 1 to take raw data, got input source %d", scales[1].InputSource)
+	}
+
+	scaled, err := ch.ApplyScaling([]float64{0, 1, 2}, StrictScaling())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// x -> 2x + 1 -> 3(2x + 1)
+	if expected := []float64{3, 9, 15}; !slices.Equal(scaled, expected) {
+		t.Errorf("expected %v, got %v", expected, scaled)
+	}
+
+	// A chain ending in a scale with no type leaves values unchanged.
+	untyped := scaledTestChannel(t,
+		Property{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(1)},
+	)
+
+	scaled, err = untyped.ApplyScaling([]float64{0, 1, 2}, StrictScaling())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{0, 1, 2}; !slices.Equal(scaled, expected) {
+		t.Errorf("expected values to be unchanged, got %v", scaled)
+	}
+}
+
+func TestScalingCorruptCounts(t *testing.T) {
+	tests := []struct {
+		name  string
+		props []Property
+	}{
+		{
+			"number of scales",
+			[]Property{
+				{Name: "NI_Number_Of_Scales", TypeCode: DataTypeUint64, Value: uint64(1 << 40)},
+			},
+		},
+		{
+			"number of scales as double",
+			[]Property{
+				{Name: "NI_Number_Of_Scales", TypeCode: DataTypeFloat64, Value: 1e300},
+			},
+		},
+		{
+			"number of coefficients",
+			[]Property{
+				{Name: "NI_Number_Of_Scales", TypeCode: DataTypeInt32, Value: int32(1)},
+				{Name: "NI_Scale[0]_Scale_Type", TypeCode: DataTypeString, Value: "Polynomial"},
+				{Name: "NI_Scale[0]_Polynomial_Coefficients_Size", TypeCode: DataTypeInt64, Value: int64(1 << 40)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		ch := scaledTestChannel(t, tt.props...)
+		if _, err := ch.Scaling(); !errors.Is(err, ErrInvalidFileFormat) {
+			t.Errorf("%s: expected ErrInvalidFileFormat, got %v", tt.name, err)
+		}
+	}
+}
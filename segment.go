@@ -8,7 +8,9 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
+	"slices"
 )
 
 const (
@@ -57,7 +59,11 @@ const segmentIncomplete uint64 = 0xff_ff_ff_ff_ff_ff_ff_ff
 
 const (
 	leadInSize uint64 = 28
-	scalerSize uint32 = 16
+
+	// Format changing scalers are five uint32s, while digital line scalers
+	// store the sample format bitmap as a single byte.
+	formatChangingScalerSize uint32 = 20
+	digitalLineScalerSize    uint32 = 17
 )
 
 var (
@@ -97,6 +103,22 @@ type metadata struct {
 	// of data (either interleaved or non-interleaved) one after the other.
 	numChunks uint64
 	chunkSize uint64
+
+	// If the final chunk is incomplete, this is the number of bytes of it that
+	// are present in the file. Otherwise, it is zero.
+	finalChunkSize uint64
+
+	// If the final chunk was written with fewer values of each object than the
+	// other chunks, rather than being cut off part way through, this is the
+	// number of values of each object in it. Otherwise, it is zero.
+	finalChunkValues uint64
+
+	// droppedFinalChunk is set if the final chunk was cut off part way through
+	// and left out because of the DropIncomplete policy.
+	droppedFinalChunk bool
+
+	// rawDataOffset is the absolute offset of the first chunk of raw data.
+	rawDataOffset int64
 }
 
 type daqmxScalerType int
@@ -107,6 +129,17 @@ const (
 	daqmxScalerTypeDigitalLine
 )
 
+func (t daqmxScalerType) String() string {
+	switch t {
+	case daqmxScalerTypeFormatChanging:
+		return "FormatChanging"
+	case daqmxScalerTypeDigitalLine:
+		return "DigitalLine"
+	default:
+		return "None"
+	}
+}
+
 type object struct {
 	path string
 
@@ -122,12 +155,21 @@ type objectIndex struct {
 	dataType   DataType
 	numValues  uint64
 
+	// rawDataType is the type code exactly as it appears in the file, which
+	// differs from dataType for the "with unit" data types.
+	rawDataType uint32
+
 	// For variable-size data types, e.g. strings, this is taken from the file
 	// itself. Otherwise, it is calculated from data type size and number of
 	// values. This refers to the total size of this channel in bytes for a
-	// single chunk.
+	// single chunk. For strings, this includes the table of 4-byte offsets
+	// which precedes the string data.
 	totalSize uint64
 
+	// stringOffsetsAdded is set when totalSize has been corrected to include
+	// the string offsets which the writer left out of it.
+	stringOffsetsAdded bool
+
 	// Only stored for DAQmx raw data.
 	scalers []daqmxScaler
 
@@ -162,19 +204,30 @@ type daqmxScaler struct {
 // next, like objects and indices.
 func (t *File) readSegmentLeadIn() (*leadIn, error) {
 	leadInBytes := make([]byte, leadInSize)
-	if _, err := t.f.Read(leadInBytes); err != nil {
+	if _, err := io.ReadFull(t.f, leadInBytes); err != nil {
 		return nil, errors.Join(ErrReadFailed, err)
 	}
 
 	magicBytes := leadInBytes[:4]
-	if t.isIndex {
-		if !bytes.Equal(magicBytes, tdmsIndexMagicBytes) {
-			return nil, errors.Join(ErrInvalidFileFormat, errors.New("invalid TDMS index magic bytes"))
-		}
-	} else if !bytes.Equal(magicBytes, tdmsMagicBytes) {
+	isIndexMagic := bytes.Equal(magicBytes, tdmsIndexMagicBytes)
+	if !isIndexMagic && !bytes.Equal(magicBytes, tdmsMagicBytes) {
 		return nil, errors.Join(ErrInvalidFileFormat, errors.New("invalid TDMS magic bytes"))
 	}
 
+	// The first segment determines whether this is an index file, unless the
+	// caller has told us explicitly. All later segments must agree.
+	if isIndexMagic != t.isIndex {
+		if !t.detectIndex {
+			if isIndexMagic {
+				return nil, fmt.Errorf("%w: found index file magic bytes in data file", ErrIndexMismatch)
+			}
+			return nil, fmt.Errorf("%w: found data file magic bytes in index file", ErrIndexMismatch)
+		}
+
+		t.isIndex = isIndexMagic
+	}
+	t.detectIndex = false
+
 	leadIn := leadIn{
 		containsMetadata:     false,
 		containsRawData:      false,
@@ -211,7 +264,7 @@ func (t *File) readSegmentLeadIn() (*leadIn, error) {
 
 	version := leadIn.byteOrder.Uint32(leadInBytes[8:])
 	if version != 4712 && version != 4713 {
-		return nil, ErrUnsupportedVersion
+		return nil, fmt.Errorf("%w: got version %d, expected 4712 or 4713", ErrUnsupportedVersion, version)
 	}
 
 	leadIn.nextSegmentOffset = leadIn.byteOrder.Uint64(leadInBytes[12:])
@@ -220,12 +273,51 @@ func (t *File) readSegmentLeadIn() (*leadIn, error) {
 	return &leadIn, nil
 }
 
+// checkMetadataSize checks that the metadata of the segment with the given
+// index, which started at metadataStart, fits within the number of bytes given
+// by the raw data offset in its lead in. Metadata which overruns the raw data
+// offset is an error with the Strict option, and is otherwise logged. A gap
+// between the metadata and the raw data is allowed, as writers may pad the
+// metadata, e.g. DAQmx reserves space for it.
+func (t *File) checkMetadataSize(segmentIdx int, leadIn *leadIn, metadataStart int64) error {
+	metadataEnd, err := t.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("failed to get position after segment %d metadata: %w", segmentIdx, err)
+	}
+
+	metadataSize := uint64(metadataEnd - metadataStart)
+	if metadataSize <= leadIn.rawDataOffset {
+		return nil
+	}
+
+	if t.opts.strict {
+		return fmt.Errorf(
+			"%w: segment %d metadata is %d bytes long, but the raw data offset is %d",
+			ErrInvalidFileFormat,
+			segmentIdx,
+			metadataSize,
+			leadIn.rawDataOffset,
+		)
+	}
+
+	t.opts.logger.Warn(
+		"segment metadata overruns the raw data offset, the file may be corrupt",
+		"segment", segmentIdx,
+		"metadata_size", metadataSize,
+		"raw_data_offset", leadIn.rawDataOffset,
+	)
+
+	return nil
+}
+
 func (t *File) readSegmentMetadata(segmentOffset int64, leadIn *leadIn, prevSegment *segment) (*metadata, error) {
 	numObjects, err := readUint32(t.f, leadIn.byteOrder)
 	if err != nil {
 		return nil, err
 	}
 
+	t.stats.ObjectsRead += int(numObjects)
+
 	m := metadata{
 		objects:     make(map[string]object, numObjects),
 		objectOrder: make([]string, 0, numObjects),
@@ -264,8 +356,12 @@ func (t *File) readSegmentMetadata(segmentOffset int64, leadIn *leadIn, prevSegm
 
 			// New properties get added to the map while existing properties get
 			// updated; properties not mentioned in the latest segment are
-			// unchanged.
-			maps.Copy(existingObj.properties, obj.properties)
+			// unchanged. The existing map may belong to the previous segment,
+			// so we mustn't modify it in place.
+			if len(obj.properties) > 0 {
+				existingObj.properties = maps.Clone(existingObj.properties)
+				maps.Copy(existingObj.properties, obj.properties)
+			}
 
 			m.objects[obj.path] = existingObj
 		} else {
@@ -275,80 +371,380 @@ func (t *File) readSegmentMetadata(segmentOffset int64, leadIn *leadIn, prevSegm
 			m.objects[obj.path] = *obj
 		}
 
-		// If this object already exists in the file's collection of properties
-		// (which may happen even if new object list is set or the previous
-		// segment doesn't have the object because it itself has the new object
-		// list flag set), we update the file's objects so that we have an up-to-date
-		// list of objects. We need to merge properties but replace raw
-		// data index.
-		if existingObj, ok := t.objects[obj.path]; ok {
-			// At the top-level, the raw data index has very little significance
-			// as it is very much segment-specific. The only useful piece of
-			// information is the data type, which is forbidden from changing
-			// from one segment to the next for a specific object. This sets the
-			// index equal to the last non-nil value, which you can use to
-			// extract data type and scalers. It's not clear if scalers can
-			// change from one segment to the next, which implies we have to
-			// handle this as an edge case; you should thus be using
-			// segment-specific objects for that information.
-			if obj.index != nil {
-				// It's OK to use the same pointer here because we only replace
-				// the index, not update it.
-				existingObj.index = obj.index
-			}
+		t.mergeObject(obj)
+	}
 
-			maps.Copy(existingObj.properties, obj.properties)
+	t.computeDataLayout(&m, segmentOffset, leadIn)
 
-			// Root level objects map has structs, not pointers, so we need to
-			// remember to update the map once we've updated the fields.
-			t.objects[obj.path] = existingObj
-		} else {
-			// File doesn't have this object yet – better add it.
-			rootObj := *obj
+	return &m, nil
+}
 
-			// We don't want to re-use the map, as above does only a shallow copy.
-			rootObj.properties = make(map[string]Property, len(obj.properties))
-			maps.Copy(rootObj.properties, obj.properties)
+// mergeObject updates the file's collection of objects with an object read from
+// the metadata of a segment.
+func (t *File) mergeObject(obj *object) {
+	// If this object already exists in the file's collection of properties
+	// (which may happen even if new object list is set or the previous
+	// segment doesn't have the object because it itself has the new object
+	// list flag set), we update the file's objects so that we have an up-to-date
+	// list of objects. We need to merge properties but replace raw
+	// data index.
+	if existingObj, ok := t.objects[obj.path]; ok {
+		// At the top-level, the raw data index has very little significance
+		// as it is very much segment-specific. The only useful piece of
+		// information is the data type, which is forbidden from changing
+		// from one segment to the next for a specific object. This sets the
+		// index equal to the last non-nil value, which you can use to
+		// extract data type and scalers. It's not clear if scalers can
+		// change from one segment to the next, which implies we have to
+		// handle this as an edge case; you should thus be using
+		// segment-specific objects for that information.
+		if obj.index != nil {
+			// It's OK to use the same pointer here because we only replace
+			// the index, not update it.
+			existingObj.index = obj.index
+		}
+
+		maps.Copy(existingObj.properties, obj.properties)
+
+		// Root level objects map has structs, not pointers, so we need to
+		// remember to update the map once we've updated the fields.
+		t.objects[obj.path] = existingObj
+	} else {
+		// File doesn't have this object yet – better add it.
+		rootObj := *obj
+
+		// We don't want to re-use the map, as above does only a shallow copy.
+		rootObj.properties = make(map[string]Property, len(obj.properties))
+		maps.Copy(rootObj.properties, obj.properties)
+
+		t.objects[obj.path] = rootObj
+	}
+}
+
+// carryOverMetadata returns the metadata for a segment which has no metadata
+// of its own, in which case the objects and raw data indices of the previous
+// segment apply unchanged. The positions of the data are
+// specific to each segment, so they are computed afresh.
+func (t *File) carryOverMetadata(segmentOffset int64, leadIn *leadIn, prevSegment *segment) *metadata {
+	m := metadata{
+		objects:     maps.Clone(prevSegment.metadata.objects),
+		objectOrder: slices.Clone(prevSegment.metadata.objectOrder),
+	}
+
+	t.computeDataLayout(&m, segmentOffset, leadIn)
 
-			t.objects[obj.path] = rootObj
+	return &m
+}
+
+// computeDataLayout works out the number and size of the chunks of raw data in
+// the segment, and the position of the data for each object within them.
+func (t *File) computeDataLayout(m *metadata, segmentOffset int64, leadIn *leadIn) {
+	// The raw data index may be shared with the file's objects and with
+	// earlier segments, e.g. when it matches the previous value, and those
+	// have their own sizes and offsets, so each segment needs its own copy
+	// before anything in it is changed. Whether the string sizes need
+	// correcting depends on the raw data of each segment, so any correction
+	// made for an earlier segment is undone.
+	for objectPath, obj := range m.objects {
+		if obj.index == nil {
+			continue
 		}
+
+		index := *obj.index
+		if index.stringOffsetsAdded {
+			index.totalSize -= 4 * index.numValues
+			index.stringOffsetsAdded = false
+		}
+
+		obj.index = &index
+		m.objects[objectPath] = obj
 	}
 
 	// Calculate the number of chunks based on the next segment offset and
 	// the total size of each chunk.
 	m.chunkSize = 0
+	countedDAQmx := false
 	for _, obj := range m.objects {
-		if obj.index != nil {
-			m.chunkSize += obj.index.totalSize
+		if obj.index == nil {
+			continue
 		}
+
+		// Every DAQmx object in the segment reads from the same raw buffers,
+		// so they only take up space in the chunk once.
+		if obj.index.scalerType != daqmxScalerTypeNone {
+			if countedDAQmx {
+				continue
+			}
+			countedDAQmx = true
+		}
+
+		m.chunkSize += obj.index.totalSize
 	}
 
+	m.rawDataOffset = segmentOffset + int64(leadInSize+leadIn.rawDataOffset)
+
 	totalRawDataSize := leadIn.nextSegmentOffset - leadIn.rawDataOffset
-	if leadIn.nextSegmentOffset == segmentIncomplete {
-		rawDataAbsolutePosition := uint64(segmentOffset) + leadInSize + leadIn.rawDataOffset
-		totalRawDataSize = uint64(t.size) - rawDataAbsolutePosition
+	cutOff := leadIn.nextSegmentOffset == segmentIncomplete
+	if leadIn.nextSegmentOffset != segmentIncomplete && m.includeStringOffsets(totalRawDataSize) {
+		t.opts.logger.Warn(
+			"string total sizes exclude the string offsets, adjusting them to fit the raw data",
+			"segment_offset", segmentOffset,
+			"chunk_size", m.chunkSize,
+		)
+	}
+	if t.hasRawData() {
+		// If LabVIEW crashed while writing this segment, or the file has been
+		// truncated since, the raw data only runs until the end of the file.
+		availableRawDataSize := uint64(max(t.dataSize-m.rawDataOffset, 0))
+		if leadIn.nextSegmentOffset == segmentIncomplete || totalRawDataSize > availableRawDataSize {
+			totalRawDataSize = availableRawDataSize
+			cutOff = true
+		}
+	} else if leadIn.nextSegmentOffset == segmentIncomplete {
+		// We can't know how much data was written without the data file.
+		totalRawDataSize = 0
+	}
+
+	// The final chunk may be short, either because the writer had fewer values
+	// left for it, or because it has been cut off part way through. In either
+	// case we still include it so that the values which were written can be
+	// read.
+	if m.chunkSize > 0 {
+		m.numChunks = totalRawDataSize / m.chunkSize
+		m.finalChunkSize = totalRawDataSize % m.chunkSize
+		if m.finalChunkSize > 0 && !cutOff {
+			m.finalChunkValues = m.shortChunkValues()
+		}
+
+		if m.finalChunkValues > 0 {
+			m.numChunks++
+			t.opts.logger.Debug(
+				"final chunk has fewer values than the others",
+				"segment_offset", segmentOffset,
+				"chunk_size", m.chunkSize,
+				"final_chunk_values", m.finalChunkValues,
+			)
+		} else if m.finalChunkSize > 0 && t.opts.incomplete == DropIncomplete {
+			t.opts.logger.Warn(
+				"raw data ends part way through a chunk, the chunk will be dropped",
+				"segment_offset", segmentOffset,
+				"chunk_size", m.chunkSize,
+				"final_chunk_size", m.finalChunkSize,
+			)
+			m.finalChunkSize = 0
+			m.droppedFinalChunk = true
+		} else if m.finalChunkSize > 0 {
+			m.numChunks++
+			t.opts.logger.Warn(
+				"raw data ends part way through a chunk, only the complete values will be read",
+				"segment_offset", segmentOffset,
+				"chunk_size", m.chunkSize,
+				"final_chunk_size", m.finalChunkSize,
+			)
+		}
+	} else if totalRawDataSize > 0 && leadIn.containsRawData {
+		t.opts.logger.Warn(
+			"segment has raw data but no objects with data, the raw data will be ignored",
+			"segment_offset", segmentOffset,
+			"raw_data_size", totalRawDataSize,
+		)
 	}
 
-	m.numChunks = totalRawDataSize / m.chunkSize
+	// When the data is interleaved, each chunk is made up of rows containing
+	// a single value for each object in turn.
+	rowSize := uint64(0)
+	if leadIn.isInterleaved {
+		for _, obj := range m.objects {
+			if obj.index != nil && obj.index.totalSize > 0 {
+				rowSize += uint64(obj.index.dataType.Size())
+			}
+		}
+	}
 
 	// Calculate the offset from the start of the segment to the first data
 	// point for the object, as well as the "stride" between successive data
 	// points when the data is interleaved. The stride isn't useful when the
 	// data is not interleaved, but it's cheap to calculate.
-	dataOffset := segmentOffset + int64(leadInSize+leadIn.rawDataOffset)
+	dataOffset := m.rawDataOffset
+	daqmxOffset := int64(-1)
 	for _, objectPath := range m.objectOrder {
 		obj := m.objects[objectPath]
 		if obj.index == nil || obj.index.totalSize == 0 {
 			continue
 		}
 
+		// DAQmx objects all start at the raw buffers, which are placed where
+		// the first DAQmx object appears. The position of the values for each
+		// object within the buffers is given by its scalers instead.
+		if obj.index.scalerType != daqmxScalerTypeNone {
+			if daqmxOffset < 0 {
+				daqmxOffset = dataOffset
+				dataOffset += int64(obj.index.totalSize)
+			}
+
+			obj.index.offset = daqmxOffset
+			obj.index.stride = 0
+			continue
+		}
+
 		obj.index.offset = dataOffset
-		dataOffset += int64(obj.index.totalSize)
 
-		obj.index.stride = int64(m.chunkSize - obj.index.totalSize)
+		if leadIn.isInterleaved {
+			dataSize := uint64(obj.index.dataType.Size())
+			dataOffset += int64(dataSize)
+			obj.index.stride = int64(rowSize - dataSize)
+		} else {
+			dataOffset += int64(obj.index.totalSize)
+			obj.index.stride = int64(m.chunkSize - obj.index.totalSize)
+		}
 	}
+}
 
-	return &m, nil
+// includeStringOffsets corrects the total size of each string object in the
+// segment if it has been written without the 4-byte offset of each string,
+// which some writers leave out. This is only done if the raw data doesn't
+// divide into whole chunks as given, but does with the offsets included.
+// Returns whether the sizes were corrected.
+func (m *metadata) includeStringOffsets(totalRawDataSize uint64) bool {
+	offsetsSize := uint64(0)
+	for _, obj := range m.objects {
+		if obj.index != nil && obj.index.dataType == DataTypeString {
+			offsetsSize += 4 * obj.index.numValues
+		}
+	}
+
+	if offsetsSize == 0 || m.chunkSize == 0 ||
+		totalRawDataSize%m.chunkSize == 0 ||
+		totalRawDataSize%(m.chunkSize+offsetsSize) != 0 {
+		return false
+	}
+
+	for _, obj := range m.objects {
+		if obj.index != nil && obj.index.dataType == DataTypeString {
+			obj.index.totalSize += 4 * obj.index.numValues
+			obj.index.stringOffsetsAdded = true
+		}
+	}
+	m.chunkSize += offsetsSize
+
+	return true
+}
+
+// chunkOffset returns the absolute offset of the data for the object with the
+// given index in the chunk with the given index.
+func (m *metadata) chunkOffset(index *objectIndex, chunkIdx uint64, isInterleaved bool) int64 {
+	offset := index.offset + int64(chunkIdx*m.chunkSize)
+
+	if chunkIdx == m.numChunks-1 && m.finalChunkValues > 0 && !isInterleaved {
+		// The objects in a short final chunk are still one after the other,
+		// but with fewer values each. As every object has the same number of
+		// values, the offset within the chunk shrinks in proportion.
+		offsetInChunk := uint64(index.offset-m.rawDataOffset) / index.numValues * m.finalChunkValues
+		offset = m.rawDataOffset + int64(chunkIdx*m.chunkSize+offsetInChunk)
+	}
+
+	return offset
+}
+
+// shortChunkValues returns the number of values of each object in a final
+// chunk of finalChunkSize bytes, if it holds whole rows of one value of each
+// object, as written when the writer has fewer values left than fill a chunk.
+// This is only possible if every object has a fixed-size data type and the
+// same number of values per chunk. Otherwise, it returns zero, and the final
+// chunk is treated as having been cut off.
+func (m *metadata) shortChunkValues() uint64 {
+	rowSize := uint64(0)
+	numValues := uint64(0)
+	for _, obj := range m.objects {
+		if obj.index == nil || obj.index.totalSize == 0 {
+			continue
+		}
+
+		dataSize := uint64(obj.index.dataType.Size())
+		if dataSize == 0 || obj.index.scalerType != daqmxScalerTypeNone {
+			return 0
+		}
+
+		if numValues != 0 && obj.index.numValues != numValues {
+			return 0
+		}
+		numValues = obj.index.numValues
+		rowSize += dataSize
+	}
+
+	if rowSize == 0 || m.finalChunkSize%rowSize != 0 {
+		return 0
+	}
+
+	return m.finalChunkSize / rowSize
+}
+
+// lostValues returns the number of values of the object with the given index
+// which weren't completely written in a final chunk that was cut off, whether
+// or not the complete values of that chunk were dropped.
+func (m *metadata) lostValues(index *objectIndex, isInterleaved bool) uint64 {
+	if m.droppedFinalChunk {
+		return index.numValues
+	}
+
+	if !m.isIncomplete() {
+		return 0
+	}
+
+	numValues, _ := m.chunkValues(index, m.numChunks-1, isInterleaved)
+	return index.numValues - numValues
+}
+
+// isIncomplete returns whether the raw data of the segment was cut off part
+// way through its final chunk.
+func (m *metadata) isIncomplete() bool {
+	return m.finalChunkSize > 0 && m.finalChunkValues == 0
+}
+
+// chunkValues returns the number of values and size in bytes of the data for
+// this object in the chunk with the given index. This is the same for every
+// chunk except a final chunk which has been cut short, where only the values
+// that were completely written are counted.
+func (m *metadata) chunkValues(index *objectIndex, chunkIdx uint64, isInterleaved bool) (uint64, uint64) {
+	if chunkIdx < m.numChunks-1 || m.finalChunkSize == 0 {
+		return index.numValues, index.totalSize
+	}
+
+	if m.finalChunkValues > 0 {
+		return m.finalChunkValues, m.finalChunkValues * uint64(index.dataType.Size())
+	}
+
+	offsetInChunk := uint64(index.offset - m.rawDataOffset)
+	if m.finalChunkSize <= offsetInChunk {
+		return 0, 0
+	}
+
+	dataSize := uint64(index.dataType.Size())
+	availableSize := m.finalChunkSize - offsetInChunk
+
+	if dataSize == 0 {
+		// Without reading the offsets at the start of the data, we can't tell
+		// how many variable-size values were written, so only take the values
+		// if they are all present. The same goes for DAQmx data, where the
+		// values are split across the raw buffers.
+		if availableSize >= index.totalSize {
+			return index.numValues, index.totalSize
+		}
+		return 0, 0
+	}
+
+	numValues := availableSize / dataSize
+	if isInterleaved {
+		// Each value is followed by the values for all the other objects.
+		numValues = 0
+		if availableSize >= dataSize {
+			numValues = (availableSize-dataSize)/(dataSize+uint64(index.stride)) + 1
+		}
+	}
+
+	numValues = min(numValues, index.numValues)
+
+	return numValues, numValues * dataSize
 }
 
 func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error) {
@@ -404,7 +800,8 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 			return nil, errors.Join(ErrReadFailed, err)
 		}
 
-		obj.index.dataType = DataType(leadIn.byteOrder.Uint32(rawDataIndexBytes))
+		obj.index.rawDataType = leadIn.byteOrder.Uint32(rawDataIndexBytes)
+		obj.index.dataType = DataType(obj.index.rawDataType).baseType()
 
 		// It is explicitly prohibited to have an interleaved segment with
 		// variable-width data types.
@@ -445,6 +842,11 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 
 			obj.index.scalers = make([]daqmxScaler, numScalers)
 
+			scalerSize := formatChangingScalerSize
+			if obj.index.scalerType == daqmxScalerTypeDigitalLine {
+				scalerSize = digitalLineScalerSize
+			}
+
 			scalersBytes := make([]byte, scalerSize*numScalers)
 			if _, err := t.f.Read(scalersBytes); err != nil {
 				return nil, errors.Join(ErrReadFailed, err)
@@ -454,11 +856,16 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 				scalerBytes := scalersBytes[i*scalerSize : (i+1)*scalerSize]
 
 				scaler := &obj.index.scalers[i]
-				scaler.dataType = DataType(leadIn.byteOrder.Uint32(scalerBytes))
+				scaler.dataType = daqmxDataType(leadIn.byteOrder.Uint32(scalerBytes))
 				scaler.rawBufferIndex = leadIn.byteOrder.Uint32(scalerBytes[4:8])
 				scaler.rawByteOffsetWithinStride = leadIn.byteOrder.Uint32(scalerBytes[8:12])
-				scaler.sampleFormatBitmap = leadIn.byteOrder.Uint32(scalerBytes[12:16])
-				scaler.scaleID = leadIn.byteOrder.Uint32(scalerBytes[16:20])
+				if obj.index.scalerType == daqmxScalerTypeDigitalLine {
+					scaler.sampleFormatBitmap = uint32(scalerBytes[12])
+					scaler.scaleID = leadIn.byteOrder.Uint32(scalerBytes[13:17])
+				} else {
+					scaler.sampleFormatBitmap = leadIn.byteOrder.Uint32(scalerBytes[12:16])
+					scaler.scaleID = leadIn.byteOrder.Uint32(scalerBytes[16:20])
+				}
 			}
 
 			numWidths, err := readUint32(t.f, leadIn.byteOrder)
@@ -473,10 +880,17 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 				return nil, errors.Join(ErrReadFailed, err)
 			}
 
+			// Each value is a row of bytes in every raw buffer, so all the
+			// buffers together hold the values for every DAQmx object in the
+			// segment.
+			rowSize := uint64(0)
 			for i := range numWidths {
 				widthBytes := widthsBytes[i*4:]
 				obj.index.widths[i] = leadIn.byteOrder.Uint32(widthBytes)
+				rowSize += uint64(obj.index.widths[i])
 			}
+
+			obj.index.totalSize = obj.index.numValues * rowSize
 		}
 	}
 
@@ -485,6 +899,8 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 		return nil, fmt.Errorf("failed to read number of properties: %w", err)
 	}
 
+	t.stats.PropertiesRead += int(numProps)
+
 	obj.properties = make(map[string]Property, numProps)
 	for range numProps {
 		propName, err := readString(t.f, leadIn.byteOrder)
@@ -504,6 +920,18 @@ func (t *File) readObject(leadIn *leadIn, prevSegment *segment) (*object, error)
 			return nil, fmt.Errorf("failed to read property value: %w", err)
 		}
 
+		if decoder := t.opts.stringDecoder; decoder != nil {
+			if propName, err = decodeString(decoder, propName); err != nil {
+				return nil, fmt.Errorf("failed to decode property name: %w", err)
+			}
+
+			if str, ok := value.(string); ok {
+				if value, err = decodeString(decoder, str); err != nil {
+					return nil, fmt.Errorf("failed to decode property %s: %w", propName, err)
+				}
+			}
+		}
+
 		prop := Property{
 			Name:     propName,
 			TypeCode: propDataType,
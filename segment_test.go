@@ -0,0 +1,629 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIncompleteFinalChunk(t *testing.T) {
+	objects := []testObject{
+		{path: "/'group'"},
+		{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+		{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5}},
+		{path: "/'group'/'c'", values: []string{"x", "y"}},
+	}
+
+	// Each chunk is 16 bytes for a, 32 bytes for b and 10 bytes for c. Cutting
+	// 30 bytes off the end leaves a in full and one and a half values of b in
+	// the final chunk.
+	tests := []struct {
+		name       string
+		incomplete bool
+	}{
+		{"incomplete segment", true},
+		{"truncated segment", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := openTestFile(t, testSegment{
+				objects:    objects,
+				numChunks:  2,
+				incomplete: tt.incomplete,
+				truncate:   30,
+			})
+
+			a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+			if err != nil {
+				t.Fatalf("unexpected error reading a: %v", err)
+			}
+
+			if expected := []int32{1, 2, 3, 4, 1, 2, 3, 4}; !slices.Equal(a, expected) {
+				t.Errorf("a: expected %v, got %v", expected, a)
+			}
+
+			bChannel := testChannel(t, f, "group", "b")
+			if bChannel.NumValues() != 5 {
+				t.Errorf("b: expected 5 values, got %d", bChannel.NumValues())
+			}
+
+			b, err := bChannel.ReadDataFloat64All()
+			if err != nil {
+				t.Fatalf("unexpected error reading b: %v", err)
+			}
+
+			if expected := []float64{0.5, 1.5, 2.5, 3.5, 0.5}; !slices.Equal(b, expected) {
+				t.Errorf("b: expected %v, got %v", expected, b)
+			}
+
+			c, err := testChannel(t, f, "group", "c").ReadDataStringAll()
+			if err != nil {
+				t.Fatalf("unexpected error reading c: %v", err)
+			}
+
+			if expected := []string{"x", "y"}; !slices.Equal(c, expected) {
+				t.Errorf("c: expected %v, got %v", expected, c)
+			}
+		})
+	}
+}
+
+func TestShortFinalChunk(t *testing.T) {
+	for _, interleaved := range []bool{false, true} {
+		t.Run(fmt.Sprintf("interleaved=%v", interleaved), func(t *testing.T) {
+			segments := []testSegment{
+				{
+					objects: []testObject{
+						{path: "/'group'"},
+						{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+						{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5}},
+					},
+					numChunks:        2,
+					shortChunkValues: 3,
+					interleaved:      interleaved,
+				},
+				{
+					objects: []testObject{
+						{path: "/'group'/'a'", values: []int32{5}},
+						{path: "/'group'/'b'", values: []float64{4.5}},
+					},
+				},
+			}
+			data := buildTestFile(segments...)
+
+			// A short final chunk in a complete segment isn't incomplete.
+			f, err := New(bytes.NewReader(data), false, int64(len(data)), IncompletePolicy(ErrorOnIncomplete))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []int32{1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 5}; !slices.Equal(a, expected) {
+				t.Errorf("expected %v, got %v", expected, a)
+			}
+
+			b, err := testChannel(t, f, "group", "b").ReadDataFloat64All()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []float64{0.5, 1.5, 2.5, 3.5, 0.5, 1.5, 2.5, 3.5, 0.5, 1.5, 2.5, 4.5}; !slices.Equal(b, expected) {
+				t.Errorf("expected %v, got %v", expected, b)
+			}
+
+			info := f.Segments()[0]
+			if info.NumChunks != 3 || info.IsIncomplete {
+				t.Errorf("expected 3 complete chunks, got %d (incomplete %v)", info.NumChunks, info.IsIncomplete)
+			}
+
+			if boundary := f.CompleteDataBoundary(); boundary != int64(len(data)) {
+				t.Errorf("expected complete data boundary %d, got %d", len(data), boundary)
+			}
+		})
+	}
+
+	t.Run("different value counts", func(t *testing.T) {
+		// Without the same number of values for every object, a short chunk
+		// can't be told apart from one that was cut off, so it's read as one.
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+				{path: "/'group'/'b'", values: []int32{5, 6}},
+			},
+			shortChunkValues: 3,
+		})
+
+		if !f.Segments()[0].IsIncomplete {
+			t.Errorf("expected segment to be incomplete")
+		}
+	})
+}
+
+func TestIncompletePolicy(t *testing.T) {
+	objects := []testObject{
+		{path: "/'group'"},
+		{path: "/'group'/'a'", values: []int32{1, 2, 3, 4}},
+		{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5}},
+	}
+
+	tests := []struct {
+		name       string
+		incomplete bool
+		truncate   int
+	}{
+		{"incomplete segment", true, 30},
+		{"truncated segment", false, 30},
+		{"incomplete segment with whole chunks", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildTestFile(testSegment{
+				objects:    objects,
+				numChunks:  2,
+				incomplete: tt.incomplete,
+				truncate:   tt.truncate,
+			})
+
+			_, err := New(bytes.NewReader(data), false, int64(len(data)), IncompletePolicy(ErrorOnIncomplete))
+			if !errors.Is(err, ErrIncompleteFile) {
+				t.Errorf("expected ErrIncompleteFile, got %v", err)
+			}
+
+			f, err := New(bytes.NewReader(data), false, int64(len(data)), IncompletePolicy(DropIncomplete))
+			if err != nil {
+				t.Fatalf("failed to parse test file: %v", err)
+			}
+
+			numValues := uint64(4)
+			if tt.truncate == 0 {
+				numValues = 8
+			}
+
+			for _, name := range []string{"a", "b"} {
+				ch := testChannel(t, f, "group", name)
+				if ch.NumValues() != numValues {
+					t.Errorf("%s: expected %d values, got %d", name, numValues, ch.NumValues())
+				}
+			}
+
+			a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+			if err != nil {
+				t.Fatalf("unexpected error reading a: %v", err)
+			}
+
+			if !slices.Equal(a[:4], []int32{1, 2, 3, 4}) {
+				t.Errorf("a: expected to start with [1 2 3 4], got %v", a)
+			}
+		})
+	}
+
+	t.Run("complete file", func(t *testing.T) {
+		data := buildTestFile(testSegment{objects: objects, numChunks: 2})
+
+		f, err := New(bytes.NewReader(data), false, int64(len(data)), IncompletePolicy(ErrorOnIncomplete))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if n := testChannel(t, f, "group", "a").NumValues(); n != 8 {
+			t.Errorf("expected 8 values, got %d", n)
+		}
+	})
+}
+
+func TestUnsupportedVersion(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int32{1}},
+		},
+	})
+	binary.LittleEndian.PutUint32(data[8:], 1234)
+
+	_, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "got version 1234") {
+		t.Errorf("expected error to include the version, got %v", err)
+	}
+}
+
+func TestPaddedRawData(t *testing.T) {
+	// The raw data offset in the lead in is larger than the size of the
+	// metadata, leaving a gap before the raw data which must be skipped.
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+				{path: "/'group'/'b'", values: []string{"x", "yz"}},
+			},
+			numChunks: 2,
+			padding:   13,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}},
+			},
+			padding: 7,
+		},
+	)
+
+	for _, batchSize := range []int{1, 1024} {
+		a, err := testChannel(t, f, "group", "a").ReadDataInt32All(BatchSize(batchSize))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []int32{1, 2, 1, 2, 3}; !slices.Equal(a, expected) {
+			t.Errorf("batch size %d: expected a %v, got %v", batchSize, expected, a)
+		}
+	}
+
+	b, err := testChannel(t, f, "group", "b").ReadDataStringAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"x", "yz", "x", "yz"}; !slices.Equal(b, expected) {
+		t.Errorf("expected b %v, got %v", expected, b)
+	}
+
+	offset, _ := testChannel(t, f, "group", "a").FirstDataOffset()
+	if expected := f.segments[0].metadata.rawDataOffset; offset != expected {
+		t.Errorf("expected first value at raw data offset %d, got %d", expected, offset)
+	}
+}
+
+func TestDAQmxScalerOffsets(t *testing.T) {
+	// Each channel in this file has a single format changing scaler, two bytes
+	// further into the stride than the scaler of the channel before. These are
+	// only found if each scaler is read as the 20 bytes it takes in the file.
+	f, err := Open("testdata/raw.tdms")
+	if err != nil {
+		t.Fatalf("failed to open DAQmx test file: %v", err)
+	}
+	defer f.Close()
+
+	offsets := map[string]uint32{
+		"First  Channel": 0,
+		"Second Chan":    2,
+		"Third Chan":     4,
+		"Fourth Chan":    6,
+		"Fifth Chan":     8,
+		"Sixth Chan":     10,
+		"Seventh Cha":    12,
+	}
+
+	for name, offset := range offsets {
+		obj, ok := f.segments[0].metadata.objects["/'Layer Data'/'"+name+"'"]
+		if !ok || obj.index == nil || len(obj.index.scalers) != 1 {
+			t.Fatalf("expected channel %s to have a single scaler", name)
+		}
+
+		scaler := obj.index.scalers[0]
+		if scaler.rawByteOffsetWithinStride != offset {
+			t.Errorf("%s: expected scaler at offset %d, got %+v", name, offset, scaler)
+		}
+	}
+}
+
+func TestDataOnlySegment(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+				{path: "/'group'/'b'", values: []string{"x", "yz"}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3, 4}},
+				{path: "/'group'/'b'", values: []string{"uv", "w"}},
+			},
+			noMetadata: true,
+			numChunks:  2,
+		},
+	)
+
+	a := testChannel(t, f, "group", "a")
+	if n := a.NumValues(); n != 6 {
+		t.Errorf("expected 6 values, got %d", n)
+	}
+
+	ints, err := a.ReadDataInt32All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int32{1, 2, 3, 4, 3, 4}; !slices.Equal(ints, expected) {
+		t.Errorf("expected %v, got %v", expected, ints)
+	}
+
+	strs, err := testChannel(t, f, "group", "b").ReadDataStringAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"x", "yz", "uv", "w", "uv", "w"}; !slices.Equal(strs, expected) {
+		t.Errorf("expected %v, got %v", expected, strs)
+	}
+}
+
+func TestReusedRawDataIndex(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'channel'", values: []int32{1, 2}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'channel'", values: []int32{3, 4}, reuseIndex: true},
+			},
+			appendObjects: true,
+		},
+	)
+
+	// Each segment must keep the position of its own data, even though the
+	// raw data index is the same.
+	values, err := testChannel(t, f, "group", "channel").ReadDataInt32All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int32{1, 2, 3, 4}; !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestStringOffsetsOnlyInOneSegment(t *testing.T) {
+	// Every segment shares the raw data index of the first, but only the
+	// strings of the second fit its raw data with the offsets included.
+	// Correcting the size for the second segment mustn't change it for the
+	// others.
+	reused := func(value string) testSegment {
+		return testSegment{
+			objects: []testObject{
+				{path: "/'group'/'strings'", values: []string{value}, reuseIndex: true},
+			},
+			appendObjects: true,
+		}
+	}
+	dataOnly := func(value string) testSegment {
+		return testSegment{
+			objects: []testObject{
+				{path: "/'group'/'strings'", values: []string{value}},
+			},
+			noMetadata: true,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		second, third testSegment
+	}{
+		{"reused index", reused("bcdef"), reused("g")},
+		{"data only", dataOnly("bcdef"), dataOnly("g")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := openTestFile(t,
+				testSegment{
+					objects: []testObject{
+						{path: "/'group'"},
+						{path: "/'group'/'strings'", values: []string{"a"}},
+					},
+				},
+				tt.second,
+				tt.third,
+			)
+
+			values, err := testChannel(t, f, "group", "strings").ReadDataStringAll()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []string{"a", "bcdef", "g"}; !slices.Equal(values, expected) {
+				t.Errorf("expected %q, got %q", expected, values)
+			}
+		})
+	}
+}
+
+func TestDAQmxScalerSizes(t *testing.T) {
+	// Format changing scalers take 20 bytes each, while digital line scalers
+	// take 17 as their sample format bitmap is a single byte. Reading any
+	// other size misplaces every scaler after the first.
+	analog := []daqmxScaler{
+		{dataType: DataTypeInt16, rawBufferIndex: 0, rawByteOffsetWithinStride: 0, sampleFormatBitmap: 0, scaleID: 1},
+		{dataType: DataTypeInt32, rawBufferIndex: 1, rawByteOffsetWithinStride: 2, sampleFormatBitmap: 0, scaleID: 2},
+	}
+	digital := []daqmxScaler{
+		{dataType: DataTypeUint8, rawBufferIndex: 0, rawByteOffsetWithinStride: 0, sampleFormatBitmap: 3, scaleID: 4},
+		{dataType: DataTypeUint8, rawBufferIndex: 0, rawByteOffsetWithinStride: 1, sampleFormatBitmap: 5, scaleID: 6},
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'analog'", daqmx: &testDAQmx{numValues: 1, widths: []uint32{8}, scalers: analog}},
+			{path: "/'group'/'digital'", daqmx: &testDAQmx{numValues: 1, widths: []uint32{8}, scalers: digital, digital: true}},
+			{path: "/'group'/'after'", props: []Property{{Name: "gain", TypeCode: DataTypeInt32, Value: int32(2)}}},
+		},
+		daqmxData: make([]byte, 8),
+	})
+
+	objects := f.segments[0].metadata.objects
+	if scalers := objects["/'group'/'analog'"].index.scalers; !slices.Equal(scalers, analog) {
+		t.Errorf("expected format changing scalers %+v, got %+v", analog, scalers)
+	}
+	if scalers := objects["/'group'/'digital'"].index.scalers; !slices.Equal(scalers, digital) {
+		t.Errorf("expected digital line scalers %+v, got %+v", digital, scalers)
+	}
+
+	// The metadata after the scalers must still line up.
+	if gain, ok := testChannel(t, f, "group", "after").Property("gain"); !ok || gain.Value != int32(2) {
+		t.Errorf("expected property after the scalers to be read, got %+v", gain)
+	}
+}
+
+func TestDataOnlyFirstSegment(t *testing.T) {
+	data := buildTestFile(testSegment{
+		objects: []testObject{
+			{path: "/'group'/'channel'", values: []int32{1, 2}},
+		},
+		noMetadata: true,
+	})
+
+	if _, err := New(bytes.NewReader(data), false, int64(len(data))); !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("expected ErrInvalidFileFormat, got %v", err)
+	}
+}
+
+func TestStreamingDataOnlySegments(t *testing.T) {
+	// Metadata is written once, after which each write only appends raw data,
+	// as is typical of high rate logging.
+	segments := []testSegment{
+		{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+				{path: "/'group'/'b'", values: []float64{0.5, 1.5}},
+			},
+			interleaved: true,
+		},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3, 4}},
+				{path: "/'group'/'b'", values: []float64{2.5, 3.5}},
+			},
+			noMetadata:  true,
+			interleaved: true,
+		},
+		// A segment with neither metadata nor raw data.
+		{noMetadata: true},
+		{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{5, 6}},
+				{path: "/'group'/'b'", values: []float64{4.5, 5.5}},
+			},
+			noMetadata:  true,
+			interleaved: true,
+			numChunks:   2,
+		},
+	}
+
+	expectedA := []int32{1, 2, 3, 4, 5, 6, 5, 6}
+	expectedB := []float64{0.5, 1.5, 2.5, 3.5, 4.5, 5.5, 4.5, 5.5}
+
+	check := func(t *testing.T, f *File, expectedA []int32, expectedB []float64) {
+		t.Helper()
+
+		a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+		if err != nil {
+			t.Fatalf("unexpected error reading channel a: %v", err)
+		}
+		if !slices.Equal(a, expectedA) {
+			t.Errorf("channel a: expected %v, got %v", expectedA, a)
+		}
+
+		b, err := testChannel(t, f, "group", "b").ReadDataFloat64All()
+		if err != nil {
+			t.Fatalf("unexpected error reading channel b: %v", err)
+		}
+		if !slices.Equal(b, expectedB) {
+			t.Errorf("channel b: expected %v, got %v", expectedB, b)
+		}
+	}
+
+	t.Run("data file", func(t *testing.T) {
+		f := openTestFile(t, segments...)
+
+		if len(f.segments) != len(segments) {
+			t.Errorf("expected %d segments, got %d", len(segments), len(f.segments))
+		}
+
+		check(t, f, expectedA, expectedB)
+	})
+
+	t.Run("index file", func(t *testing.T) {
+		dataFilename := writeTestFile(t, "data.tdms", buildTestFile(segments...))
+		indexFilename := writeTestFile(t, "data.tdms_index", buildTestIndexFile(segments...))
+
+		f, err := OpenWithIndex(dataFilename, indexFilename)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		check(t, f, expectedA, expectedB)
+	})
+
+	t.Run("incomplete final segment", func(t *testing.T) {
+		incomplete := slices.Clone(segments)
+		incomplete[len(incomplete)-1].incomplete = true
+		incomplete[len(incomplete)-1].truncate = 10
+
+		f := openTestFile(t, incomplete...)
+		if !f.IsIncomplete {
+			t.Errorf("expected file to be incomplete")
+		}
+
+		// The last row of the second chunk is missing b and half of a.
+		check(t, f, expectedA[:7], expectedB[:7])
+	})
+}
+
+func TestStrictMetadataSize(t *testing.T) {
+	segment := testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int32{1, 2}},
+		},
+	}
+
+	padded := segment
+	padded.padding = 4
+
+	// Overrunning metadata can't be built directly, so reduce the raw data
+	// offset in the lead in after building the segment.
+	overrun := segment.bytes()
+	rawDataOffset := binary.LittleEndian.Uint64(overrun[20:28])
+	binary.LittleEndian.PutUint64(overrun[20:28], rawDataOffset-4)
+
+	_, err := New(bytes.NewReader(overrun), false, int64(len(overrun)), Strict())
+	if !errors.Is(err, ErrInvalidFileFormat) {
+		t.Errorf("expected ErrInvalidFileFormat with strict, got %v", err)
+	}
+	if expected := fmt.Sprintf("is %d bytes long, but the raw data offset is %d", rawDataOffset, rawDataOffset-4); err != nil && !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error to give the discrepancy, got %v", err)
+	}
+
+	if _, err := New(bytes.NewReader(overrun), false, int64(len(overrun))); err != nil {
+		t.Errorf("unexpected error without strict: %v", err)
+	}
+
+	for _, data := range [][]byte{segment.bytes(), padded.bytes()} {
+		if _, err := New(bytes.NewReader(data), false, int64(len(data)), Strict()); err != nil {
+			t.Errorf("unexpected error with strict: %v", err)
+		}
+	}
+
+	for _, name := range []string{"standard.tdms", "big_endian.tdms", "raw.tdms", "raw_timestamps.tdms", "digital_input.tdms"} {
+		f, err := OpenWith("testdata/"+name, Strict())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		f.Close()
+	}
+}
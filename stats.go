@@ -0,0 +1,92 @@
+package tdms
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// float128Prec is the number of bits of precision of a [Float128], including
+// the implicit leading bit.
+const float128Prec = 113
+
+// BigStats are summary statistics of a [DataTypeFloat128] channel, computed
+// without losing any of the precision of the values. See
+// [Channel.StatsBigFloat].
+type BigStats struct {
+	// Count is the number of values, excluding NaN.
+	Count uint64
+
+	// NaNs is the number of NaN values, which are excluded from the other
+	// statistics as they can't be represented by big.Float.
+	NaNs uint64
+
+	// Min, Max and Mean are nil if there are no values other than NaN. Mean
+	// is also nil if there are both positive and negative infinite values.
+	Min  *big.Float
+	Max  *big.Float
+	Mean *big.Float
+}
+
+// StatsBigFloat computes the minimum, maximum and mean of a Float128 channel
+// at the full 113-bit precision of the values, reading the data in batches so
+// that memory usage is bounded by the batch size. Converting the values to
+// float64 first would lose most of the precision that makes Float128 useful.
+//
+// Returns ErrIncorrectType if the channel isn't a Float128 channel.
+func (ch *Channel) StatsBigFloat(options ...ReadOption) (BigStats, error) {
+	if ch.DataType != DataTypeFloat128 {
+		return BigStats{}, fmt.Errorf("%w: cannot compute big.Float statistics of %s channel", ErrIncorrectType, ch.DataType)
+	}
+
+	stats := BigStats{}
+
+	// The sum is kept at a higher precision than the values so that rounding
+	// errors don't build up over many values, and the mean is only rounded to
+	// the precision of the values at the end.
+	sum := new(big.Float).SetPrec(2 * float128Prec)
+	var posInf, negInf bool
+
+	for batch, err := range ch.ReadDataAsFloat128Batch(options...) {
+		if err != nil {
+			return BigStats{}, err
+		}
+
+		for _, value := range batch {
+			f := value.AsBigFloat()
+			if f == nil {
+				stats.NaNs++
+				continue
+			}
+
+			stats.Count++
+			if stats.Min == nil || f.Cmp(stats.Min) < 0 {
+				stats.Min = f
+			}
+			if stats.Max == nil || f.Cmp(stats.Max) > 0 {
+				stats.Max = f
+			}
+
+			// Adding infinities of opposite signs panics, so they are
+			// tracked separately.
+			switch {
+			case f.IsInf() && f.Signbit():
+				negInf = true
+			case f.IsInf():
+				posInf = true
+			default:
+				sum.Add(sum, f)
+			}
+		}
+	}
+
+	switch {
+	case stats.Count == 0 || (posInf && negInf):
+	case posInf || negInf:
+		stats.Mean = new(big.Float).SetPrec(float128Prec).SetInf(negInf)
+	default:
+		count := new(big.Float).SetUint64(stats.Count)
+		stats.Mean = new(big.Float).SetPrec(float128Prec).Quo(sum, count)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+package tdms
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestStatsBigFloat(t *testing.T) {
+	// These differ by less than float64 can represent, so would all be 1 if
+	// converted to float64 first.
+	one := testFloat128(1)
+	onePlus := one
+	onePlus[0] |= 2 // 1 + 2^-111
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []Float128{onePlus, one, testFloat128(math.NaN())}},
+			{path: "/'group'/'inf'", values: []Float128{testFloat128(1), testFloat128(math.Inf(-1))}},
+			{path: "/'group'/'empty'", values: []Float128{}},
+			{path: "/'group'/'float'", values: []float64{1}},
+		},
+	})
+
+	stats, err := testChannel(t, f, "group", "a").StatsBigFloat(BatchSize(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Count != 2 || stats.NaNs != 1 {
+		t.Errorf("expected 2 values and 1 NaN, got %d and %d", stats.Count, stats.NaNs)
+	}
+
+	expectedMean := new(big.Float).SetPrec(113).SetMantExp(big.NewFloat(1), -112)
+	expectedMean.Add(expectedMean, big.NewFloat(1))
+
+	if stats.Min.Cmp(big.NewFloat(1)) != 0 {
+		t.Errorf("expected min 1, got %s", stats.Min.Text('g', 40))
+	}
+	if stats.Max.Cmp(onePlus.AsBigFloat()) != 0 {
+		t.Errorf("expected max %s, got %s", onePlus.AsBigFloat().Text('g', 40), stats.Max.Text('g', 40))
+	}
+	if stats.Mean.Cmp(expectedMean) != 0 {
+		t.Errorf("expected mean %s, got %s", expectedMean.Text('g', 40), stats.Mean.Text('g', 40))
+	}
+
+	stats, err = testChannel(t, f, "group", "inf").StatsBigFloat()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !stats.Min.IsInf() || !stats.Mean.IsInf() || stats.Max.Cmp(big.NewFloat(1)) != 0 {
+		t.Errorf("expected min and mean -Inf and max 1, got %v", stats)
+	}
+
+	stats, err = testChannel(t, f, "group", "empty").StatsBigFloat()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stats.Count != 0 || stats.Min != nil || stats.Max != nil || stats.Mean != nil {
+		t.Errorf("expected empty stats, got %v", stats)
+	}
+
+	if _, err := testChannel(t, f, "group", "float").StatsBigFloat(); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
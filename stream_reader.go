@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"io"
 	"iter"
+	"unsafe"
 )
 
 type interpreter[T any] func([]byte, binary.ByteOrder) T
@@ -32,8 +33,13 @@ func StreamReader[T any](
 	dataType DataType,
 	interpret interpreter[T],
 ) iter.Seq2[T, error] {
+	return unbatch(BatchStreamReader(ch, options, dataType, interpret))
+}
+
+// unbatch returns an iterator yielding the individual values of each batch.
+func unbatch[T any](batches iter.Seq2[[]T, error]) iter.Seq2[T, error] {
 	return func(yield func(T, error) bool) {
-		for batch, err := range BatchStreamReader(ch, options, dataType, interpret) {
+		for batch, err := range batches {
 			if err != nil {
 				yield(*new(T), err)
 				return
@@ -48,6 +54,94 @@ func StreamReader[T any](
 	}
 }
 
+// filterStreamReader returns an iterator yielding only the values from the
+// channel for which keep returns true. Like [StreamReader], values are read in
+// batches internally so memory usage is bounded by the batch size regardless of
+// how many values match.
+func filterStreamReader[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	keep func(T) bool,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+			if err != nil {
+				yield(*new(T), err)
+				return
+			}
+
+			for _, datum := range batch {
+				if keep(datum) && !yield(datum, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// mapStreamReader returns an iterator yielding f applied to each value from the
+// channel. Like [StreamReader], values are read in batches internally so memory
+// usage is bounded by the batch size. If reading fails, the error is yielded
+// with the zero value and the iterator stops.
+func mapStreamReader[T, U any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	f func(T) U,
+) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+			if err != nil {
+				yield(*new(U), err)
+				return
+			}
+
+			for _, datum := range batch {
+				if !yield(f(datum), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Reduce folds every value of the channel into an accumulator, starting with
+// init and calling f with the accumulator and each value in turn. Values are
+// read in batches, so memory usage is bounded by the batch size no matter how
+// long the channel is. Use the [BatchSize] option to control the batch size.
+//
+// If reading fails, the error is returned along with the accumulator as it was
+// after the last value which was read.
+func Reduce[T, A any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	init A,
+	f func(A, T) A,
+) (A, error) {
+	return reduce(BatchStreamReader(ch, options, dataType, interpret), init, f)
+}
+
+func reduce[T, A any](batches iter.Seq2[[]T, error], init A, f func(A, T) A) (A, error) {
+	acc := init
+
+	for batch, err := range batches {
+		if err != nil {
+			return acc, err
+		}
+
+		for _, datum := range batch {
+			acc = f(acc, datum)
+		}
+	}
+
+	return acc, nil
+}
+
 // BatchStreamReader returns an iterator that yields batches of values from the
 // channel. Each batch is a slice of values read from the underlying file. Use
 // the [BatchSize] option to control how many values are read in each batch.
@@ -61,13 +155,46 @@ func BatchStreamReader[T any](
 	options []ReadOption,
 	dataType DataType,
 	interpret interpreter[T],
+) iter.Seq2[[]T, error] {
+	return batchStreamReader(ch, options, dataType, interpret, false)
+}
+
+// nativeBatchStreamReader is the same as [BatchStreamReader], except that
+// interpret must be the standard interpreter for dataType whenever T is the Go
+// type of dataType. This allows the raw data to be read directly into the batch
+// without calling interpret when the byte order of the data matches the host,
+// which is several times faster for numeric data.
+func nativeBatchStreamReader[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+) iter.Seq2[[]T, error] {
+	return batchStreamReader(ch, options, dataType, interpret, true)
+}
+
+func batchStreamReader[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	native bool,
 ) iter.Seq2[[]T, error] {
 	return func(yield func([]T, error) bool) {
+		if ch.f.opts.metadataOnly {
+			yield(nil, ErrMetadataOnly)
+			return
+		}
+
 		opts := readOptions{}
 		for _, opt := range options {
 			opt(&opts)
 		}
 
+		if opts.batchSize == 0 {
+			opts.batchSize = ch.f.defaultBatchSize
+		}
+
 		if opts.batchSize == 0 {
 			opts.batchSize = 2056
 			if dataType == DataTypeString {
@@ -85,22 +212,66 @@ func BatchStreamReader[T any](
 		buf := make([]byte, batchSize*dataSize)
 		bufLen := uint64(len(buf))
 		batch := make([]T, batchSize)
-		r := ch.f.f
 
-		for _, chunk := range ch.dataChunks {
+		// If T has exactly the same memory layout as the raw data, we can read
+		// the data straight into the batch instead of interpreting each value.
+		var batchBytes []byte
+		native = native && nativeDataType[T]() == dataType
+		if native {
+			batchBytes = unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(batch))), len(batch)*dataSize)
+		}
+
+		// For interleaved data, we read whole rows and pick out the values
+		// for this channel.
+		var rowsBuf []byte
+
+		r := ch.f.data
+		if opts.retries > 0 {
+			r = &retryReader{r: r, retries: opts.retries, backoff: opts.retryBackoff}
+		}
+
+		for _, chunk := range ch.dataChunks() {
+			if chunk.daqmx != nil {
+				yield(nil, fmt.Errorf(
+					"%w: channel %s holds DAQmx raw data, which can only be read with ReadDAQmxRawData",
+					ErrUnsupportedType,
+					ch.path,
+				))
+				return
+			}
+
+			// You aren't allowed to have interleaved variable-length data
+			// channels. This is checked when the metadata is read, but a
+			// data-only segment can still mark a string channel's data as
+			// interleaved, and reading it as such would return garbage.
+			if chunk.isInterleaved && dataSize == 0 {
+				yield(nil, fmt.Errorf(
+					"%w: channel %s has interleaved data but interleaved data cannot contain variable-length data types",
+					ErrInvalidFileFormat,
+					ch.path,
+				))
+				return
+			}
+
 			if _, err := r.Seek(chunk.offset, io.SeekStart); err != nil {
 				yield(nil, err)
 				return
 			}
 
+			order := chunk.order
+			if opts.byteOrder != nil {
+				order = opts.byteOrder
+			}
+
 			bytesRead := uint64(0)
+			direct := native && order == hostByteOrder
 
 			// Special case for strings, where the indices into the strings are
 			// stored at the beginning of the chunk.
 			strOffsets := []uint32{0}
 			if dataType == DataTypeString {
 				strOffsetsBytes := make([]byte, chunk.numValues*4)
-				if n, err := r.Read(strOffsetsBytes); err != nil {
+				if n, err := io.ReadFull(r, strOffsetsBytes); err != nil {
 					yield(nil, err)
 					return
 				} else {
@@ -108,7 +279,12 @@ func BatchStreamReader[T any](
 				}
 
 				for i := range chunk.numValues {
-					strOffsets = append(strOffsets, chunk.order.Uint32(strOffsetsBytes[i*4:]))
+					strOffsets = append(strOffsets, order.Uint32(strOffsetsBytes[i*4:]))
+				}
+
+				if err := validateStringOffsets(chunk, strOffsets); err != nil {
+					yield(nil, fmt.Errorf("%w: channel %s", err, ch.path))
+					return
 				}
 			}
 
@@ -127,17 +303,14 @@ func BatchStreamReader[T any](
 				// is 0. Now that we know how long each value is, we can make
 				// buf big enough to hold the values for this batch.
 				if dataType == DataTypeString {
-					numValuesLeft := 0
-					for i := valuesProcessed; i < int(chunk.numValues); i++ {
-						numValuesLeft++
+					if valuesProcessed >= int(chunk.numValues) {
+						// Any bytes after the final string don't belong to
+						// any value.
+						break
 					}
 
-					requiredNumValues := min(batchSize, numValuesLeft)
-
-					requiredBufLen := uint32(0)
-					for i := valuesProcessed; i < valuesProcessed+requiredNumValues; i++ {
-						requiredBufLen += strOffsets[i+1] - strOffsets[i]
-					}
+					requiredNumValues := min(batchSize, int(chunk.numValues)-valuesProcessed)
+					requiredBufLen := strOffsets[valuesProcessed+requiredNumValues] - strOffsets[valuesProcessed]
 
 					bufLen = uint64(requiredBufLen)
 					if cap(buf) < int(requiredBufLen) {
@@ -154,39 +327,45 @@ func BatchStreamReader[T any](
 					buf = buf[:bufLen]
 				}
 
+				dst := buf
+				if direct {
+					dst = batchBytes[:len(buf)]
+				}
+
 				n := 0
 				var err error
 				if !chunk.isInterleaved {
-					n, err = io.ReadFull(r, buf)
+					n, err = io.ReadFull(r, dst)
 				} else {
-					// You aren't allowed to have interleaved variable-length
-					// data channels.
-					if dataSize == 0 {
-						yield(
-							nil,
-							fmt.Errorf(
-								"%w: interleaved data chunks cannot contains variable-length data types",
-								ErrInvalidFileFormat,
-							),
-						)
-						return
-					}
-
-					for i := 0; i < len(buf); i += dataSize {
-						if i > 0 {
-							if _, err := r.Seek(chunk.stride, io.SeekCurrent); err != nil {
-								yield(nil, err)
-								return
-							}
-						}
-
-						if readLen, err := r.Read(buf[int(i)*dataSize : int(i+1)*dataSize]); err != nil {
+					// After the first batch, we're positioned just after the
+					// last value we read, so we need to skip the rest of its row.
+					if bytesRead > 0 {
+						if _, err := r.Seek(chunk.stride, io.SeekCurrent); err != nil {
 							yield(nil, err)
 							return
-						} else {
-							n += readLen
 						}
 					}
+
+					rowSize := dataSize + int(chunk.stride)
+					rowsLen := (len(dst)/dataSize-1)*rowSize + dataSize
+					if cap(rowsBuf) < rowsLen {
+						rowsBuf = make([]byte, rowsLen)
+					}
+
+					var rowsRead int
+					rowsRead, err = io.ReadFull(r, rowsBuf[:rowsLen])
+
+					// Only whole values are taken if the data ends part way
+					// through.
+					numValues := 0
+					if rowsRead >= dataSize {
+						numValues = (rowsRead-dataSize)/rowSize + 1
+					}
+
+					for i := range numValues {
+						copy(dst[i*dataSize:(i+1)*dataSize], rowsBuf[i*rowSize:])
+					}
+					n = numValues * dataSize
 				}
 
 				bytesRead += uint64(n)
@@ -196,12 +375,9 @@ func BatchStreamReader[T any](
 				// exactly line up with the end of the chunk, we will get EOF
 				// when we try to read the next batch where there's no data
 				// left.
-				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
-					// We've reached the end of the chunk.
-					break
-				}
+				reachedEnd := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
 
-				if err != nil {
+				if err != nil && !reachedEnd {
 					yield(nil, err)
 					return
 				}
@@ -215,7 +391,22 @@ func BatchStreamReader[T any](
 				// doesn't work for variable-size types.
 				numValuesRead := min(batchSize, int(chunk.numValues)-valuesProcessed)
 
+				if reachedEnd {
+					// The data ended part way through the batch, so we can only
+					// use the whole values that were read before the end.
+					// Partially read strings can't be recovered.
+					if dataSize == 0 {
+						break
+					}
+					numValuesRead = min(numValuesRead, n/dataSize)
+				}
+
 				for i := range numValuesRead {
+					if direct {
+						// The values have already been read into the batch.
+						break
+					}
+
 					startIdx := int(i) * dataSize
 					endIdx := int(i+1) * dataSize
 
@@ -223,12 +414,25 @@ func BatchStreamReader[T any](
 						// strOffsets should always have one more data point in
 						// it than number of strings – we added the 0 at the
 						// beginning and the last value is the end of the final
-						// string.
-						startIdx = int(strOffsets[i])
-						endIdx = int(strOffsets[i+1])
+						// string. The offsets are from the start of the chunk's
+						// string data, whereas buf starts at the first string
+						// in this batch.
+						batchStart := strOffsets[valuesProcessed]
+						startIdx = int(strOffsets[valuesProcessed+i] - batchStart)
+						endIdx = int(strOffsets[valuesProcessed+i+1] - batchStart)
+					}
+
+					value := buf[startIdx:endIdx]
+					if dataType == DataTypeString && opts.stringDecoder != nil {
+						decoded, err := opts.stringDecoder.Bytes(value)
+						if err != nil {
+							yield(nil, fmt.Errorf("failed to decode string: %w", err))
+							return
+						}
+						value = decoded
 					}
 
-					batch[i] = interpret(buf[startIdx:endIdx], chunk.order)
+					batch[i] = interpret(value, order)
 				}
 
 				valuesProcessed += numValuesRead
@@ -237,25 +441,395 @@ func BatchStreamReader[T any](
 				// size of each individual string from the offsetes at
 				// the start of the chunk.
 
-				if !yield(batch[:numValuesRead], nil) {
+				if numValuesRead > 0 && !yield(batch[:numValuesRead], nil) {
 					return
 				}
+
+				if reachedEnd {
+					break
+				}
 			}
 		}
 	}
 }
 
+// validateStringOffsets checks that the offsets read from the start of a chunk
+// of string data are in order and that the strings they point to fit within
+// the chunk, as the total size of a string object includes both the 4-byte
+// offset of each string and the strings themselves.
+func validateStringOffsets(chunk dataChunk, strOffsets []uint32) error {
+	for i := 1; i < len(strOffsets); i++ {
+		if strOffsets[i] < strOffsets[i-1] {
+			return fmt.Errorf(
+				"%w: string offset %d is before previous offset %d",
+				ErrInvalidFileFormat,
+				strOffsets[i],
+				strOffsets[i-1],
+			)
+		}
+	}
+
+	offsetsSize := 4 * chunk.numValues
+	stringsSize := uint64(strOffsets[len(strOffsets)-1])
+	if offsetsSize+stringsSize > chunk.size {
+		return fmt.Errorf(
+			"%w: %d string offsets and %d bytes of strings don't fit in a chunk of %d bytes",
+			ErrInvalidFileFormat,
+			chunk.numValues,
+			stringsSize,
+			chunk.size,
+		)
+	}
+
+	return nil
+}
+
+// hostByteOrder is the byte order of the machine we're running on.
+var hostByteOrder binary.ByteOrder = func() binary.ByteOrder {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// nativeDataType returns the data type whose raw data has exactly the same
+// memory layout as T on a host with the same byte order as the data, or
+// DataTypeVoid if there is none.
+func nativeDataType[T any]() DataType {
+	switch any(*new(T)).(type) {
+	case int8:
+		return DataTypeInt8
+	case int16:
+		return DataTypeInt16
+	case int32:
+		return DataTypeInt32
+	case int64:
+		return DataTypeInt64
+	case uint8:
+		return DataTypeUint8
+	case uint16:
+		return DataTypeUint16
+	case uint32:
+		return DataTypeUint32
+	case uint64:
+		return DataTypeUint64
+	case float32:
+		return DataTypeFloat32
+	case float64:
+		return DataTypeFloat64
+	case complex64:
+		return DataTypeComplex64
+	case complex128:
+		return DataTypeComplex128
+	default:
+		// Bools can't be copied directly as any non-zero byte is true, and
+		// the fields of timestamps are in a different order.
+		return DataTypeVoid
+	}
+}
+
 // readAllData reads all data from a channel and put it into a single slice.
 //
+// If the raw data in the file ends before all the values declared in the
+// metadata have been read, the values which were read are returned along with
+// an error wrapping ErrLengthMismatch, so that the data loss isn't silent.
+//
 // By re-using BatchStreamReader here, we can avoid having to allocate 2*N bytes
 // – one for the raw bytes and other for the interpreted values. The raw bytes
 // are still batched while we allocate the values slice up-front. It's also
 // cleaner in terms of the code as we avoid re-implementing the underlying read
 // functionality.
 func readAllData[T any](ch *Channel, options []ReadOption, dataType DataType, interpret interpreter[T]) ([]T, error) {
+	opts := readOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
 	values := make([]T, 0, ch.totalNumValues)
+	if opts.alignment > 0 {
+		aligned, err := alignedSlice[T](ch.totalNumValues, opts.alignment)
+		if err != nil {
+			return nil, err
+		}
+		values = aligned[:0]
+	}
+
+	for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, batch...)
+	}
+
+	if uint64(len(values)) != ch.totalNumValues {
+		return values, fmt.Errorf(
+			"%w: channel %s declares %d values but only %d could be read",
+			ErrLengthMismatch,
+			ch.path,
+			ch.totalNumValues,
+			len(values),
+		)
+	}
+
+	return values, nil
+}
+
+// alignedSlice returns a slice of n values whose first element is aligned to
+// the given number of bytes, by allocating some extra values and slicing from
+// the first aligned one.
+func alignedSlice[T any](n uint64, alignment int) ([]T, error) {
+	if alignment&(alignment-1) != 0 {
+		return nil, fmt.Errorf("alignment must be a power of two, got %d", alignment)
+	}
+
+	size := unsafe.Sizeof(*new(T))
+	if size == 0 {
+		return make([]T, n), nil
+	}
+
+	// Stepping through the values cycles through every address which can be
+	// aligned within this many values.
+	extra := uint64(alignment) / gcd(uint64(size), uint64(alignment))
+
+	buf := make([]T, n+extra)
+	for i := range extra {
+		if uintptr(unsafe.Pointer(&buf[i]))%uintptr(alignment) == 0 {
+			return buf[i : i+n : i+n], nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to align %d byte values to %d bytes", size, alignment)
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// readChunkData reads all the values of the chunk of the channel with the
+// given index into a single slice.
+func readChunkData[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	chunkIndex int,
+) ([]T, error) {
+	if ch.f.opts.metadataOnly {
+		return nil, ErrMetadataOnly
+	}
+
+	chunks := ch.dataChunks()
+	if chunkIndex < 0 || chunkIndex >= len(chunks) {
+		return nil, fmt.Errorf("%w: channel %s has no chunk %d", ErrNotFound, ch.path, chunkIndex)
+	}
+
+	chunkChannel := *ch
+	chunkChannel.lazyChunks = &lazyDataChunks{chunks: chunks[chunkIndex : chunkIndex+1]}
+	chunkChannel.totalNumValues = chunks[chunkIndex].numValues
+
+	return readAllData(&chunkChannel, options, dataType, interpret)
+}
+
+// readReImData reads all data from a complex channel, splitting the values
+// into their real and imaginary parts.
+func readReImData[T complex64 | complex128](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+) ([]float64, []float64, error) {
+	realValues := make([]float64, 0, ch.totalNumValues)
+	imagValues := make([]float64, 0, ch.totalNumValues)
+
+	for batch, err := range nativeBatchStreamReader(ch, options, dataType, interpret) {
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, value := range batch {
+			c := complex128(value)
+			realValues = append(realValues, real(c))
+			imagValues = append(imagValues, imag(c))
+		}
+	}
+
+	return realValues, imagValues, nil
+}
+
+// ReadHead reads the first n values from the channel into a single slice,
+// interpreting them in the same way as [BatchStreamReader]. If the channel has
+// fewer than n values, all values are returned.
+//
+// This is the generic form of [Channel.ReadDataFloat64Head], for use with any
+// data type.
+func ReadHead[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	n uint64,
+) ([]T, error) {
+	return readRangeData(ch, options, dataType, interpret, 0, n, false)
+}
+
+// ReadTail reads the last n values from the channel into a single slice,
+// interpreting them in the same way as [BatchStreamReader]. If the channel has
+// fewer than n values, all values are returned.
+//
+// This is the generic form of [Channel.ReadDataFloat64Tail], for use with any
+// data type. Chunks before the last n values are skipped entirely.
+func ReadTail[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	n uint64,
+) ([]T, error) {
+	start := uint64(0)
+	if n < ch.totalNumValues {
+		start = ch.totalNumValues - n
+	}
+
+	return readRangeData(ch, options, dataType, interpret, start, n, false)
+}
+
+// readRangeData reads count values from a channel starting at the value with
+// index start and puts them into a single slice. If the range extends beyond
+// the end of the channel, only the values that exist are returned. If native is
+// true, interpret must satisfy the same constraint as for
+// [nativeBatchStreamReader].
+//
+// Chunks lying entirely before the range are skipped without reading them, and
+// for fixed-size data types the first and last chunks of the range are trimmed
+// so that only the values in the range are read from the file.
+func readRangeData[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	start uint64,
+	count uint64,
+	native bool,
+) ([]T, error) {
+	if start >= ch.totalNumValues {
+		return []T{}, nil
+	}
+
+	values := make([]T, min(count, ch.totalNumValues-start))
+	n, err := readRangeInto(ch, options, dataType, interpret, start, values, native)
+	if err != nil {
+		return nil, err
+	}
+
+	return values[:n], nil
+}
+
+// readRangeInto is the same as [readRangeData], except that it reads up to
+// len(dst) values into dst and returns the number of values read. If reading
+// fails part way through, the values copied so far are counted in the result
+// along with the error.
+func readRangeInto[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	start uint64,
+	dst []T,
+	native bool,
+) (int, error) {
+	if start >= ch.totalNumValues || len(dst) == 0 {
+		return 0, nil
+	}
+
+	count := min(uint64(len(dst)), ch.totalNumValues-start)
+	dst = dst[:count]
+
+	chunks, skip := sliceDataChunks(ch.dataChunks(), dataType.Size(), start, count)
+
+	rangeChannel := *ch
+	rangeChannel.lazyChunks = &lazyDataChunks{chunks: chunks}
+	rangeChannel.totalNumValues = 0
+	for _, chunk := range chunks {
+		rangeChannel.totalNumValues += chunk.numValues
+	}
+
+	n := 0
+	for batch, err := range batchStreamReader(&rangeChannel, options, dataType, interpret, native) {
+		if err != nil {
+			return n, err
+		}
+
+		if skip > 0 {
+			skipped := min(skip, uint64(len(batch)))
+			batch = batch[skipped:]
+			skip -= skipped
+		}
+
+		n += copy(dst[n:], batch)
+		if n == len(dst) {
+			break
+		}
+	}
 
-	for batch, err := range BatchStreamReader(ch, options, dataType, interpret) {
+	return n, nil
+}
+
+// readStrideData reads every nth value from a channel with a fixed-size data
+// type into a single slice.
+//
+// Each chunk is turned into an interleaved chunk starting at its first
+// selected value, with a stride that steps over the values which aren't
+// selected, so that the skipped values are never interpreted.
+func readStrideData[T any](
+	ch *Channel,
+	options []ReadOption,
+	dataType DataType,
+	interpret interpreter[T],
+	n uint64,
+) ([]T, error) {
+	dataSize := dataType.Size()
+	if dataSize == 0 {
+		return nil, fmt.Errorf("%w: cannot read every nth value of %s channel", ErrUnsupportedType, dataType)
+	}
+
+	chunks := make([]dataChunk, 0, len(ch.dataChunks()))
+	numValues := uint64(0)
+
+	chunkStart := uint64(0)
+	for _, chunk := range ch.dataChunks() {
+		// The first selected value is the first multiple of n within the
+		// chunk, counting across the whole channel.
+		firstValue := (n - chunkStart%n) % n
+		chunkStart += chunk.numValues
+		if firstValue >= chunk.numValues {
+			continue
+		}
+
+		distance := int64(dataSize)
+		if chunk.isInterleaved {
+			distance += chunk.stride
+		}
+
+		chunk.offset += int64(firstValue) * distance
+		chunk.numValues = (chunk.numValues-firstValue-1)/n + 1
+		chunk.size = chunk.numValues * uint64(dataSize)
+		chunk.isInterleaved = true
+		chunk.stride = int64(n)*distance - int64(dataSize)
+
+		chunks = append(chunks, chunk)
+		numValues += chunk.numValues
+	}
+
+	strideChannel := *ch
+	strideChannel.lazyChunks = &lazyDataChunks{chunks: chunks}
+	strideChannel.totalNumValues = numValues
+
+	values := make([]T, 0, numValues)
+	for batch, err := range nativeBatchStreamReader(&strideChannel, options, dataType, interpret) {
 		if err != nil {
 			return nil, err
 		}
@@ -265,3 +839,58 @@ func readAllData[T any](ch *Channel, options []ReadOption, dataType DataType, in
 
 	return values, nil
 }
+
+// sliceDataChunks returns the subset of chunks containing the count values
+// starting at index start.
+//
+// Fixed-size chunks are trimmed so that they contain only values within the
+// range. Variable-size chunks (i.e. strings) can't be trimmed because the
+// offsets of the values are stored at the start of the chunk, so these are
+// returned whole and skip is the number of values at the start of the first
+// chunk which precede the range.
+func sliceDataChunks(chunks []dataChunk, dataSize int, start, count uint64) ([]dataChunk, uint64) {
+	sliced := make([]dataChunk, 0)
+	skip := uint64(0)
+	end := start + count
+
+	chunkStart := uint64(0)
+	for _, chunk := range chunks {
+		chunkEnd := chunkStart + chunk.numValues
+		if chunkEnd <= start || chunk.numValues == 0 {
+			chunkStart = chunkEnd
+			continue
+		}
+
+		if chunkStart >= end {
+			break
+		}
+
+		firstValue := uint64(0)
+		if start > chunkStart {
+			firstValue = start - chunkStart
+		}
+		lastValue := min(chunk.numValues, end-chunkStart)
+
+		if dataSize == 0 {
+			if len(sliced) == 0 {
+				skip = firstValue
+			}
+		} else {
+			// Interleaved values are separated by the stride, while
+			// non-interleaved values are contiguous.
+			distance := int64(dataSize)
+			if chunk.isInterleaved {
+				distance += chunk.stride
+			}
+
+			chunk.offset += int64(firstValue) * distance
+			chunk.numValues = lastValue - firstValue
+			chunk.size = chunk.numValues * uint64(dataSize)
+		}
+
+		sliced = append(sliced, chunk)
+		chunkStart = chunkEnd
+	}
+
+	return sliced, skip
+}
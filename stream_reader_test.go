@@ -0,0 +1,431 @@
+package tdms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadInterleaved(t *testing.T) {
+	for _, bigEndian := range []bool{false, true} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []int32{1, 2, 3, 4, 5}},
+				{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5, 4.5}},
+				{path: "/'group'/'c'", values: []int16{-1, -2, -3, -4, -5}},
+			},
+			interleaved: true,
+			bigEndian:   bigEndian,
+			numChunks:   2,
+			truncate:    14 + 8,
+		})
+
+		for _, batchSize := range []int{1, 2, 3, 1024} {
+			a, err := testChannel(t, f, "group", "a").ReadDataInt32All(BatchSize(batchSize))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []int32{1, 2, 3, 4, 5, 1, 2, 3, 4}; !slices.Equal(a, expected) {
+				t.Errorf("big endian %v, batch size %d: expected a %v, got %v", bigEndian, batchSize, expected, a)
+			}
+
+			b, err := testChannel(t, f, "group", "b").ReadDataFloat64All(BatchSize(batchSize))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []float64{0.5, 1.5, 2.5, 3.5, 4.5, 0.5, 1.5, 2.5}; !slices.Equal(b, expected) {
+				t.Errorf("big endian %v, batch size %d: expected b %v, got %v", bigEndian, batchSize, expected, b)
+			}
+
+			c, err := testChannel(t, f, "group", "c").ReadDataInt16All(BatchSize(batchSize))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := []int16{-1, -2, -3, -4, -5, -1, -2, -3}; !slices.Equal(c, expected) {
+				t.Errorf("big endian %v, batch size %d: expected c %v, got %v", bigEndian, batchSize, expected, c)
+			}
+		}
+	}
+}
+
+func TestReadInterleavedRanges(t *testing.T) {
+	// Reading part of an interleaved chunk relies on each value being a whole
+	// row after the previous one, rather than a whole chunk.
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []int16{1, 2, 3, 4}},
+			{path: "/'group'/'b'", values: []float64{0.5, 1.5, 2.5, 3.5}},
+		},
+		interleaved: true,
+		numChunks:   2,
+	})
+
+	b := testChannel(t, f, "group", "b")
+
+	head, err := b.ReadDataFloat64Head(3)
+	if err != nil {
+		t.Fatalf("unexpected error reading head: %v", err)
+	}
+	if expected := []float64{0.5, 1.5, 2.5}; !slices.Equal(head, expected) {
+		t.Errorf("expected head %v, got %v", expected, head)
+	}
+
+	tail, err := b.ReadDataFloat64Tail(5)
+	if err != nil {
+		t.Fatalf("unexpected error reading tail: %v", err)
+	}
+	if expected := []float64{3.5, 0.5, 1.5, 2.5, 3.5}; !slices.Equal(tail, expected) {
+		t.Errorf("expected tail %v, got %v", expected, tail)
+	}
+
+	every, err := b.ReadDataFloat64Stride(3)
+	if err != nil {
+		t.Fatalf("unexpected error reading every third value: %v", err)
+	}
+	if expected := []float64{0.5, 3.5, 2.5}; !slices.Equal(every, expected) {
+		t.Errorf("expected every third value %v, got %v", expected, every)
+	}
+}
+
+// A data-only segment can mark a string channel's data as interleaved without
+// the metadata being read again, which must be an error rather than garbage.
+func TestReadInterleavedStrings(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'strings'", values: []string{"a", "b"}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'strings'", values: []string{"c", "d"}},
+			},
+			noMetadata:  true,
+			interleaved: true,
+		},
+	)
+
+	_, err := testChannel(t, f, "group", "strings").ReadDataStringAll()
+	if !errors.Is(err, ErrInvalidFileFormat) {
+		t.Fatalf("expected ErrInvalidFileFormat, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/'group'/'strings'") {
+		t.Errorf("expected error to contain channel path, got %v", err)
+	}
+}
+
+func TestReadStringBatches(t *testing.T) {
+	// The string offsets at the start of each chunk are from the start of the
+	// chunk's strings, so they need adjusting for every batch after the first.
+	values := []string{"a", "", "bcd", "efgh", "ij"}
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'strings'", values: values},
+		},
+		numChunks: 2,
+	})
+
+	expected := slices.Concat(values, values)
+	for _, batchSize := range []int{1, 2, 3, 1024} {
+		actual, err := testChannel(t, f, "group", "strings").ReadDataStringAll(BatchSize(batchSize))
+		if err != nil {
+			t.Fatalf("batch size %d: unexpected error: %v", batchSize, err)
+		}
+
+		if !slices.Equal(actual, expected) {
+			t.Errorf("batch size %d: expected %q, got %q", batchSize, expected, actual)
+		}
+	}
+}
+
+func TestReadStringChunks(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+	}{
+		{"single string", []string{"hello"}},
+		{"many strings", []string{"a", "", "bcd", "efgh", "ij", "k", "lmnop"}},
+	}
+
+	for _, tt := range tests {
+		for _, omitOffsets := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s omit offsets %t", tt.name, omitOffsets), func(t *testing.T) {
+				f := openTestFile(t, testSegment{
+					objects: []testObject{
+						{path: "/'group'"},
+						{path: "/'group'/'a'", values: tt.values, omitStringOffsets: omitOffsets},
+						{path: "/'group'/'b'", values: []int32{1, 2}},
+					},
+					numChunks: 2,
+				})
+
+				expected := slices.Concat(tt.values, tt.values)
+
+				// A batch size smaller than a chunk checks that the offsets are
+				// taken relative to the start of each batch.
+				for _, batchSize := range []int{0, 1, 2, 3} {
+					ch := testChannel(t, f, "group", "a")
+					a, err := ch.ReadDataStringAll(BatchSize(batchSize))
+					if err != nil {
+						t.Fatalf("batch size %d: unexpected error reading a: %v", batchSize, err)
+					}
+
+					if !slices.Equal(a, expected) {
+						t.Errorf("batch size %d: expected %q, got %q", batchSize, expected, a)
+					}
+				}
+
+				b, err := testChannel(t, f, "group", "b").ReadDataInt32All()
+				if err != nil {
+					t.Fatalf("unexpected error reading b: %v", err)
+				}
+
+				if expected := []int32{1, 2, 1, 2}; !slices.Equal(b, expected) {
+					t.Errorf("b: expected %v, got %v", expected, b)
+				}
+			})
+		}
+	}
+
+	t.Run("offsets out of order", func(t *testing.T) {
+		data := buildTestFile(testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'a'", values: []string{"ab", "c"}},
+			},
+		})
+
+		// Swap the offsets of the two strings.
+		offsets := []byte{2, 0, 0, 0, 3, 0, 0, 0}
+		i := bytes.LastIndex(data, offsets)
+		if i < 0 {
+			t.Fatal("string offsets not found in test file")
+		}
+		copy(data[i:], []byte{3, 0, 0, 0, 2, 0, 0, 0})
+
+		f, err := New(bytes.NewReader(data), false, int64(len(data)))
+		if err != nil {
+			t.Fatalf("failed to parse test file: %v", err)
+		}
+
+		_, err = testChannel(t, f, "group", "a").ReadDataStringAll()
+		if !errors.Is(err, ErrInvalidFileFormat) {
+			t.Errorf("expected ErrInvalidFileFormat, got %v", err)
+		}
+	})
+}
+
+func TestReduce(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'floats'", values: []float64{1, 2, 3}},
+				{path: "/'group'/'strings'", values: []string{"a", "bc"}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'floats'", values: []float64{4}},
+				{path: "/'group'/'strings'", values: []string{"def"}},
+			},
+		},
+	)
+
+	floats := testChannel(t, f, "group", "floats")
+	for _, batchSize := range []int{1, 2, 1024} {
+		sumOfSquares, err := floats.ReduceFloat64(0, func(acc, value float64) float64 {
+			return acc + value*value
+		}, BatchSize(batchSize))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sumOfSquares != 30 {
+			t.Errorf("batch size %d: expected 30, got %v", batchSize, sumOfSquares)
+		}
+	}
+
+	totalLength, err := Reduce(testChannel(t, f, "group", "strings"), nil, DataTypeString, interpretString, 0,
+		func(acc int, value string) int {
+			return acc + len(value)
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if totalLength != 6 {
+		t.Errorf("expected total length 6, got %d", totalLength)
+	}
+}
+
+// The data types which can be read directly into the batch must give exactly
+// the same values as interpreting each value in turn.
+func TestNativeReadMatchesInterpreted(t *testing.T) {
+	for _, bigEndian := range []bool{false, true} {
+		f := openTestFile(t, testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'int64'", values: []int64{-1, 1 << 40, 3}},
+				{path: "/'group'/'uint16'", values: []uint16{1, 0xFFFF, 3}},
+				{path: "/'group'/'float32'", values: []float32{0.1, -2, 3e30}},
+				{path: "/'group'/'complex64'", values: []complex64{1 + 2i, -3i, 4}},
+				{path: "/'group'/'complex128'", values: []complex128{1 + 2i, -3i, 4}},
+			},
+			bigEndian: bigEndian,
+		})
+
+		assertNativeMatches(t, testChannel(t, f, "group", "int64"), DataTypeInt64, interpretInt64)
+		assertNativeMatches(t, testChannel(t, f, "group", "uint16"), DataTypeUint16, interpretUint16)
+		assertNativeMatches(t, testChannel(t, f, "group", "float32"), DataTypeFloat32, interpretFloat32)
+		assertNativeMatches(t, testChannel(t, f, "group", "complex64"), DataTypeComplex64, interpretComplex64)
+		assertNativeMatches(t, testChannel(t, f, "group", "complex128"), DataTypeComplex128, interpretComplex128)
+	}
+}
+
+func assertNativeMatches[T comparable](t *testing.T, ch *Channel, dataType DataType, interpret interpreter[T]) {
+	t.Helper()
+
+	var native, interpreted []T
+	for batch, err := range nativeBatchStreamReader(ch, nil, dataType, interpret) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		native = append(native, batch...)
+	}
+	for batch, err := range BatchStreamReader(ch, nil, dataType, interpret) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		interpreted = append(interpreted, batch...)
+	}
+
+	if !slices.Equal(native, interpreted) {
+		t.Errorf("%s: native read %v doesn't match interpreted read %v", ch.path, native, interpreted)
+	}
+}
+
+// benchmarkFile generates a file with numSegments segments, each containing
+// the given objects.
+func benchmarkFile(b *testing.B, numSegments int, segment testSegment) *File {
+	b.Helper()
+
+	segments := make([]testSegment, numSegments)
+	for i := range segments {
+		segments[i] = segment
+	}
+
+	data := buildTestFile(segments...)
+	f, err := New(bytes.NewReader(data), false, int64(len(data)))
+	if err != nil {
+		b.Fatalf("failed to parse benchmark file: %v", err)
+	}
+
+	b.SetBytes(int64(len(data)))
+
+	return f
+}
+
+func BenchmarkReadFloat64All(b *testing.B) {
+	values := make([]float64, 100_000)
+	for i := range values {
+		values[i] = float64(i)
+	}
+
+	for _, bigEndian := range []bool{false, true} {
+		b.Run(fmt.Sprintf("big endian %v", bigEndian), func(b *testing.B) {
+			f := benchmarkFile(b, 10, testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'channel'", values: values},
+				},
+				bigEndian: bigEndian,
+			})
+			ch := testChannel(b, f, "group", "channel")
+
+			for b.Loop() {
+				if _, err := ch.ReadDataFloat64All(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Complex data in the host byte order is read straight into the values, while
+// data in the other byte order is decoded one value at a time, so this shows
+// the speedup of the native path on a large spectrum.
+func BenchmarkReadComplex128All(b *testing.B) {
+	values := make([]complex128, 2_000_000)
+	for i := range values {
+		values[i] = complex(float64(i), -float64(i))
+	}
+
+	for _, bigEndian := range []bool{false, true} {
+		b.Run(fmt.Sprintf("big endian %v", bigEndian), func(b *testing.B) {
+			f := benchmarkFile(b, 1, testSegment{
+				objects: []testObject{
+					{path: "/'group'"},
+					{path: "/'group'/'channel'", values: values},
+				},
+				bigEndian: bigEndian,
+			})
+			ch := testChannel(b, f, "group", "channel")
+
+			for b.Loop() {
+				if _, err := ch.ReadDataComplex128All(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReadInt32Interleaved(b *testing.B) {
+	objects := []testObject{{path: "/'group'"}}
+	for i := range 4 {
+		values := make([]int32, 25_000)
+		for j := range values {
+			values[j] = int32(i * j)
+		}
+		objects = append(objects, testObject{path: fmt.Sprintf("/'group'/'channel %d'", i), values: values})
+	}
+
+	f := benchmarkFile(b, 10, testSegment{objects: objects, interleaved: true})
+
+	for b.Loop() {
+		for i := range 4 {
+			ch := testChannel(b, f, "group", fmt.Sprintf("channel %d", i))
+			if _, err := ch.ReadDataInt32All(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkReadStringChannel(b *testing.B) {
+	values := make([]string, 10_000)
+	for i := range values {
+		values[i] = fmt.Sprintf("value number %d", i)
+	}
+
+	f := benchmarkFile(b, 10, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'channel'", values: values},
+		},
+	})
+	ch := testChannel(b, f, "group", "channel")
+
+	for b.Loop() {
+		if _, err := ch.ReadDataStringAll(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1075,40 +1075,11 @@ func testChannelStatistics(t *testing.T, ch *tdms.Channel, expected ChannelInfo)
 
 // mapDataType maps string data type names to tdms.DataType
 func mapDataType(s string) tdms.DataType {
-	switch s {
-	case "int8":
-		return tdms.DataTypeInt8
-	case "int16":
-		return tdms.DataTypeInt16
-	case "int32":
-		return tdms.DataTypeInt32
-	case "int64":
-		return tdms.DataTypeInt64
-	case "uint8":
-		return tdms.DataTypeUint8
-	case "uint16":
-		return tdms.DataTypeUint16
-	case "uint32":
-		return tdms.DataTypeUint32
-	case "uint64":
-		return tdms.DataTypeUint64
-	case "float32":
-		return tdms.DataTypeFloat32
-	case "float64":
-		return tdms.DataTypeFloat64
-	case "string":
-		return tdms.DataTypeString
-	case "boolean":
-		return tdms.DataTypeBool
-	case "timestamp":
-		return tdms.DataTypeTimestamp
-	case "complex64":
-		return tdms.DataTypeComplex64
-	case "complex128":
-		return tdms.DataTypeComplex128
-	default:
+	dt, err := tdms.ParseDataType(s)
+	if err != nil {
 		return tdms.DataTypeVoid
 	}
+	return dt
 }
 
 // comparePropertyValue compares a property value from the file with expected value from JSON
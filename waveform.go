@@ -0,0 +1,235 @@
+package tdms
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Waveform channels written by LabVIEW describe the timing of their samples
+// using a set of standard "wf_" properties.
+const (
+	waveformStartTimeProperty   = "wf_start_time"
+	waveformStartOffsetProperty = "wf_start_offset"
+	waveformIncrementProperty   = "wf_increment"
+	waveformSamplesProperty     = "wf_samples"
+)
+
+// Waveform holds the timing information of a waveform channel, taken from its
+// standard waveform properties.
+type Waveform struct {
+	// StartTime is the time of the first sample, from the wf_start_time
+	// property. This is the zero Timestamp if the property isn't present,
+	// in which case the waveform only has relative timing.
+	StartTime Timestamp
+
+	// StartOffset is the offset in seconds of the first sample from
+	// StartTime, from the wf_start_offset property. This is zero if the
+	// property isn't present.
+	StartOffset float64
+
+	// Increment is the time in seconds between successive samples, from the
+	// wf_increment property.
+	Increment float64
+
+	// Samples is the number of samples in the waveform, from the wf_samples
+	// property. If the property isn't present, this is the number of values
+	// in the channel.
+	Samples uint64
+}
+
+// Waveform returns the timing information of this channel, and whether the
+// channel is a waveform. A channel is treated as a waveform if it has a
+// numeric wf_increment property, as this is the only property needed to
+// work out the relative time of each sample.
+func (ch *Channel) Waveform() (Waveform, bool) {
+	increment, err := requiredFloatProperty(ch.Properties, waveformIncrementProperty)
+	if err != nil {
+		return Waveform{}, false
+	}
+
+	waveform := Waveform{
+		Increment: increment,
+		Samples:   ch.NumValues(),
+	}
+
+	if startTime, err := ch.Properties[waveformStartTimeProperty].AsTimestamp(); err == nil {
+		waveform.StartTime = startTime
+	}
+
+	if startOffset, err := requiredFloatProperty(ch.Properties, waveformStartOffsetProperty); err == nil {
+		waveform.StartOffset = startOffset
+	}
+
+	if samples, ok, err := intProperty(ch.Properties, waveformSamplesProperty); ok && err == nil && samples >= 0 {
+		waveform.Samples = uint64(samples)
+	}
+
+	return waveform, true
+}
+
+// SampleRate returns the number of samples per second, i.e. 1/Increment, and
+// whether the increment gives a valid rate. It is false if the increment is
+// zero, negative or not finite. The duration of the waveform in seconds is
+// Samples/SampleRate, or equivalently Samples*Increment.
+func (w Waveform) SampleRate() (float64, bool) {
+	if w.Increment <= 0 || math.IsInf(w.Increment, 0) || math.IsNaN(w.Increment) {
+		return 0, false
+	}
+
+	return 1 / w.Increment, true
+}
+
+// SampleRate returns the sample rate of this channel in Hz, from its
+// wf_increment property, and whether the channel has a valid sample rate. It
+// is false if the channel isn't a waveform or its increment isn't positive.
+func (ch *Channel) SampleRate() (float64, bool) {
+	waveform, ok := ch.Waveform()
+	if !ok {
+		return 0, false
+	}
+
+	return waveform.SampleRate()
+}
+
+// Duration returns the length of time covered by this channel's data, i.e.
+// the number of values multiplied by its wf_increment property, and whether the
+// channel has valid waveform timing.
+func (ch *Channel) Duration() (time.Duration, bool) {
+	waveform, ok := ch.Waveform()
+	if !ok {
+		return 0, false
+	}
+
+	if _, ok := waveform.SampleRate(); !ok {
+		return 0, false
+	}
+
+	seconds := float64(ch.NumValues()) * waveform.Increment
+	return time.Duration(math.Round(seconds * 1e9)), true
+}
+
+// Duration returns the longest [Channel.Duration] of all the channels in the
+// file, and whether any channel has valid waveform timing.
+func (t *File) Duration() (time.Duration, bool) {
+	longest := time.Duration(0)
+	found := false
+
+	for _, group := range t.Groups {
+		for _, ch := range group.Channels {
+			if duration, ok := ch.Duration(); ok {
+				longest = max(longest, duration)
+				found = true
+			}
+		}
+	}
+
+	return longest, found
+}
+
+// StartTime returns the earliest acquisition start time recorded in the file,
+// and whether one was found, e.g. for sorting files chronologically. The start
+// time is taken from the file's DateTime or datetime properties, and from the
+// wf_start_time property of each channel. Only timestamp properties are used,
+// and zero timestamps are ignored, as they're written for waveforms with only
+// relative timing.
+func (t *File) StartTime() (time.Time, bool) {
+	var earliest time.Time
+	found := false
+
+	consider := func(prop Property, ok bool) {
+		if !ok {
+			return
+		}
+
+		timestamp, err := prop.AsTimestamp()
+		if err != nil || timestamp == (Timestamp{}) {
+			return
+		}
+
+		if startTime := timestamp.AsTime(); !found || startTime.Before(earliest) {
+			earliest = startTime
+			found = true
+		}
+	}
+
+	consider(t.Property("DateTime"))
+	consider(t.Property("datetime"))
+
+	for _, group := range t.Groups {
+		for _, ch := range group.Channels {
+			consider(ch.Property(waveformStartTimeProperty))
+		}
+	}
+
+	return earliest, found
+}
+
+// SampleTime returns the absolute time of the sample with the given index,
+// i.e. StartTime plus StartOffset plus index*Increment, rounded to the nearest
+// nanosecond.
+func (w Waveform) SampleTime(index uint64) time.Time {
+	seconds := w.StartOffset + float64(index)*w.Increment
+	return w.StartTime.AsTime().Add(time.Duration(math.Round(seconds * 1e9)))
+}
+
+// ReadDataFloat64Between reads the float64 values of a waveform channel whose
+// sample times fall within the window from start up to but not including end,
+// along with the time of each of those samples. Only the chunks containing the
+// window are read, so this is cheap even for very long recordings.
+//
+// Returns ErrMissingProperty if the channel doesn't have the wf_increment and
+// wf_start_time properties, or ErrInvalidFileFormat if wf_increment isn't
+// positive.
+func (ch *Channel) ReadDataFloat64Between(start, end time.Time, options ...ReadOption) ([]float64, []time.Time, error) {
+	waveform, ok := ch.Waveform()
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMissingProperty, waveformIncrementProperty)
+	}
+
+	if _, ok := ch.Properties[waveformStartTimeProperty]; !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMissingProperty, waveformStartTimeProperty)
+	}
+
+	if _, ok := waveform.SampleRate(); !ok {
+		return nil, nil, fmt.Errorf("%w: %s must be positive, got %v", ErrInvalidFileFormat, waveformIncrementProperty, waveform.Increment)
+	}
+
+	first := waveform.firstSampleFrom(start, ch.totalNumValues)
+	last := waveform.firstSampleFrom(end, ch.totalNumValues)
+	if last <= first {
+		return []float64{}, []time.Time{}, nil
+	}
+
+	values, err := readRangeData(ch, options, DataTypeFloat64, interpretFloat64, first, last-first, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	times := make([]time.Time, len(values))
+	for i := range times {
+		times[i] = waveform.SampleTime(first + uint64(i))
+	}
+
+	return values, times, nil
+}
+
+// firstSampleFrom returns the index of the first of numSamples samples whose
+// time is not before t, or numSamples if there is none.
+func (w Waveform) firstSampleFrom(t time.Time, numSamples uint64) uint64 {
+	firstSampleTime := w.StartTime.AsTime().Add(time.Duration(math.Round(w.StartOffset * 1e9)))
+
+	// The estimate may be out by one due to floating point rounding, so it is
+	// corrected using the exact time of the samples either side.
+	estimate := math.Ceil(t.Sub(firstSampleTime).Seconds() / w.Increment)
+	index := uint64(min(max(estimate, 0), float64(numSamples)))
+
+	for index > 0 && !w.SampleTime(index-1).Before(t) {
+		index--
+	}
+	for index < numSamples && w.SampleTime(index).Before(t) {
+		index++
+	}
+
+	return index
+}
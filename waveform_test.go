@@ -0,0 +1,264 @@
+package tdms
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWaveform(t *testing.T) {
+	f, err := Open("testdata/raw_timestamps.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	waveform, ok := testChannel(t, f, "Untitled", "Untitled").Waveform()
+	if !ok {
+		t.Fatalf("expected channel to be a waveform")
+	}
+
+	expected := Waveform{
+		StartTime:   Timestamp{Timestamp: 3788905723, Remainder: 1265713805430620160},
+		StartOffset: 0,
+		Increment:   0.001,
+		Samples:     128,
+	}
+	if waveform != expected {
+		t.Errorf("expected %+v, got %+v", expected, waveform)
+	}
+}
+
+func TestWaveformWithoutStartTime(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{
+				path:   "/'group'/'relative'",
+				values: []float64{1, 2, 3},
+				props: []Property{
+					{Name: "wf_increment", TypeCode: DataTypeFloat64, Value: 0.5},
+					{Name: "wf_start_offset", TypeCode: DataTypeFloat64, Value: 2.0},
+				},
+			},
+			{path: "/'group'/'plain'", values: []float64{1, 2, 3}},
+		},
+	})
+
+	waveform, ok := testChannel(t, f, "group", "relative").Waveform()
+	if !ok {
+		t.Fatalf("expected channel to be a waveform")
+	}
+
+	// Without wf_samples, the number of samples comes from the data.
+	expected := Waveform{StartOffset: 2, Increment: 0.5, Samples: 3}
+	if waveform != expected {
+		t.Errorf("expected %+v, got %+v", expected, waveform)
+	}
+
+	if _, ok := testChannel(t, f, "group", "plain").Waveform(); ok {
+		t.Errorf("expected channel without waveform properties not to be a waveform")
+	}
+}
+
+func TestSampleRate(t *testing.T) {
+	f, err := Open("testdata/raw_timestamps.tdms")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	rate, ok := testChannel(t, f, "Untitled", "Untitled").SampleRate()
+	if !ok || math.Abs(rate-1000) > 1e-9 {
+		t.Errorf("expected sample rate of 1000 Hz, got %v (ok %v)", rate, ok)
+	}
+
+	for _, increment := range []float64{0, -1, math.Inf(1), math.NaN()} {
+		if rate, ok := (Waveform{Increment: increment}).SampleRate(); ok {
+			t.Errorf("increment %v: expected no sample rate, got %v", increment, rate)
+		}
+	}
+
+	f = openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'plain'", values: []float64{1, 2, 3}},
+		},
+	})
+	if _, ok := testChannel(t, f, "group", "plain").SampleRate(); ok {
+		t.Errorf("expected channel without waveform properties not to have a sample rate")
+	}
+}
+
+func TestReadDataFloat64Between(t *testing.T) {
+	waveformProps := []Property{
+		{Name: "wf_start_time", TypeCode: DataTypeTimestamp, Value: Timestamp{Timestamp: tdmsEpochOffset + 1000}},
+		{Name: "wf_increment", TypeCode: DataTypeFloat64, Value: 0.5},
+	}
+
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'"},
+				{path: "/'group'/'waveform'", values: []float64{0, 1, 2, 3, 4}, props: waveformProps},
+				{path: "/'group'/'relative'", values: []float64{0}, props: waveformProps[1:]},
+				{path: "/'group'/'plain'", values: []float64{0}},
+			},
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'waveform'", values: []float64{5, 6, 7, 8, 9}},
+			},
+		},
+	)
+
+	at := func(seconds float64) time.Time {
+		return time.Unix(1000, 0).Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		expected   []float64
+	}{
+		{"exact bounds", at(1), at(3), []float64{2, 3, 4, 5}},
+		{"between samples", at(0.9), at(3.1), []float64{2, 3, 4, 5, 6}},
+		{"before start", at(-10), at(1), []float64{0, 1}},
+		{"after end", at(4.5), at(100), []float64{9}},
+		{"whole channel", at(-1), at(5), []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"outside channel", at(5), at(10), []float64{}},
+		{"empty window", at(3), at(1), []float64{}},
+	}
+
+	ch := testChannel(t, f, "group", "waveform")
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, times, err := ch.ReadDataFloat64Between(test.start, test.end)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(values, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, values)
+			}
+
+			if len(times) != len(values) {
+				t.Fatalf("expected %d times, got %d", len(values), len(times))
+			}
+
+			for i, value := range values {
+				if expected := at(value * 0.5); !times[i].Equal(expected) {
+					t.Errorf("value %d: expected time %v, got %v", i, expected, times[i])
+				}
+			}
+		})
+	}
+
+	for _, name := range []string{"relative", "plain"} {
+		if _, _, err := testChannel(t, f, "group", name).ReadDataFloat64Between(at(0), at(1)); !errors.Is(err, ErrMissingProperty) {
+			t.Errorf("%s: expected ErrMissingProperty, got %v", name, err)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'fast'", values: []float64{1, 2, 3, 4}, props: []Property{
+				{Name: "wf_increment", TypeCode: DataTypeFloat64, Value: 0.25},
+			}},
+			{path: "/'group'/'slow'", values: []float64{1, 2, 3, 4}, props: []Property{
+				{Name: "wf_increment", TypeCode: DataTypeFloat64, Value: 1.5},
+			}},
+			{path: "/'group'/'invalid'", values: []float64{1, 2, 3, 4}, props: []Property{
+				{Name: "wf_increment", TypeCode: DataTypeFloat64, Value: 0.0},
+			}},
+			{path: "/'group'/'plain'", values: []float64{1, 2, 3, 4}},
+		},
+	})
+
+	tests := []struct {
+		channel  string
+		expected time.Duration
+		ok       bool
+	}{
+		{"fast", time.Second, true},
+		{"slow", 6 * time.Second, true},
+		{"invalid", 0, false},
+		{"plain", 0, false},
+	}
+
+	for _, test := range tests {
+		duration, ok := testChannel(t, f, "group", test.channel).Duration()
+		if duration != test.expected || ok != test.ok {
+			t.Errorf("%s: expected %v (%v), got %v (%v)", test.channel, test.expected, test.ok, duration, ok)
+		}
+	}
+
+	if duration, ok := f.Duration(); duration != 6*time.Second || !ok {
+		t.Errorf("expected file duration 6s, got %v (%v)", duration, ok)
+	}
+
+	plain := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'plain'", values: []float64{1, 2, 3, 4}},
+		},
+	})
+
+	if duration, ok := plain.Duration(); ok {
+		t.Errorf("expected no file duration, got %v", duration)
+	}
+}
+
+func TestStartTime(t *testing.T) {
+	early := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	timestamp := func(name string, value time.Time) Property {
+		return Property{Name: name, TypeCode: DataTypeTimestamp, Value: NewTimestampFromTime(value)}
+	}
+
+	f := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{timestamp("DateTime", late)}},
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1}, props: []Property{timestamp("wf_start_time", early)}},
+			{path: "/'group'/'relative'", values: []float64{1}, props: []Property{
+				{Name: "wf_start_time", TypeCode: DataTypeTimestamp, Value: Timestamp{}},
+			}},
+		},
+	})
+
+	if startTime, ok := f.StartTime(); !ok || !startTime.Equal(early) {
+		t.Errorf("expected start time %v, got %v (%v)", early, startTime, ok)
+	}
+
+	fileOnly := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/", props: []Property{
+				timestamp("datetime", late),
+				{Name: "DateTime", TypeCode: DataTypeString, Value: "yesterday"},
+			}},
+		},
+	})
+
+	if startTime, ok := fileOnly.StartTime(); !ok || !startTime.Equal(late) {
+		t.Errorf("expected start time %v, got %v (%v)", late, startTime, ok)
+	}
+
+	none := openTestFile(t, testSegment{
+		objects: []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'a'", values: []float64{1}},
+		},
+	})
+
+	if _, ok := none.StartTime(); ok {
+		t.Errorf("expected no start time")
+	}
+}
@@ -0,0 +1,371 @@
+package tdms
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+)
+
+// contiguousObject is an object to be written by [File.WriteContiguous], along
+// with the layout of its raw data.
+type contiguousObject struct {
+	path       string
+	properties map[string]Property
+
+	// channel is nil for the root object and groups.
+	channel *Channel
+
+	// dataSize is the total size in bytes of the raw data of the channel.
+	dataSize uint64
+
+	// strOffsets are the offsets of the end of each string, for string
+	// channels.
+	strOffsets []uint32
+}
+
+// WriteContiguous writes the objects and data of this file to w as a new TDMS
+// file with a single segment, where the data of each channel is stored as one
+// contiguous block, one channel after another. This layout is guaranteed no
+// matter how the data is laid out in this file, e.g. interleaved or spread
+// across many segments, which matters for consumers that memory map the data
+// or read one channel fully before the next.
+//
+// Objects are written in the order in which they first appear in this file,
+// with the properties in [File.Properties] and the Properties field of each
+// group and channel, so any changes made to those maps are written. Empty
+// channels, which have a data type but no values, are written with their data
+// type so that they read back the same. The new file is always little endian.
+//
+// The data of each channel is streamed from this file rather than held in
+// memory, although string channels are read twice as the size of the strings
+// must be known before any data is written.
+//
+// Returns ErrUnsupportedType if any channel has a data type which can't be read,
+// or ErrMetadataOnly if the file was opened with the MetadataOnly option.
+func (t *File) WriteContiguous(w io.Writer) error {
+	if t.opts.metadataOnly {
+		return ErrMetadataOnly
+	}
+
+	order := binary.LittleEndian
+
+	objects, err := t.contiguousObjects()
+	if err != nil {
+		return err
+	}
+
+	metadata := &bytes.Buffer{}
+	if err := writeUint32(metadata, order, uint32(len(objects))); err != nil {
+		return err
+	}
+
+	rawDataSize := uint64(0)
+	for _, obj := range objects {
+		if err := writeContiguousObject(metadata, order, obj); err != nil {
+			return fmt.Errorf("failed to write metadata for object %s: %w", obj.path, err)
+		}
+		rawDataSize += obj.dataSize
+	}
+
+	toc := tocContainsMetadata | tocContainsNewObjectList
+	if rawDataSize > 0 {
+		toc |= tocContainsRawData
+	}
+
+	bw := bufio.NewWriter(w)
+
+	leadIn := make([]byte, leadInSize)
+	copy(leadIn, tdmsMagicBytes)
+	order.PutUint32(leadIn[4:], toc)
+	order.PutUint32(leadIn[8:], 4713)
+	order.PutUint64(leadIn[12:], uint64(metadata.Len())+rawDataSize)
+	order.PutUint64(leadIn[20:], uint64(metadata.Len()))
+
+	if _, err := bw.Write(leadIn); err != nil {
+		return err
+	}
+	if _, err := bw.Write(metadata.Bytes()); err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		if obj.dataSize == 0 {
+			continue
+		}
+
+		if err := writeContiguousData(bw, order, obj); err != nil {
+			return fmt.Errorf("failed to write data for channel %s: %w", obj.path, err)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// contiguousObjects returns the objects to write, working out the size of the
+// raw data of each channel.
+func (t *File) contiguousObjects() ([]contiguousObject, error) {
+	paths := t.ObjectPaths()
+	objects := make([]contiguousObject, 0, len(paths))
+
+	for _, path := range paths {
+		groupName, channelName, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		obj := contiguousObject{path: path}
+
+		switch {
+		case groupName == "":
+			obj.properties = t.Properties
+		case channelName == "":
+			obj.properties = t.Groups[groupName].Properties
+		default:
+			ch, err := t.Channel(groupName, channelName)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.properties = ch.Properties
+			obj.channel = ch
+
+			if err := obj.computeDataSize(); err != nil {
+				return nil, fmt.Errorf("failed to read channel %s: %w", path, err)
+			}
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+func (obj *contiguousObject) computeDataSize() error {
+	ch := obj.channel
+
+	switch {
+	case ch.DataType == DataTypeVoid || ch.NumValues() == 0:
+		return nil
+	case ch.DataType == DataTypeString:
+		obj.strOffsets = make([]uint32, 0, ch.NumValues())
+		for value, err := range ch.ReadDataAsString() {
+			if err != nil {
+				return err
+			}
+
+			obj.dataSize += uint64(len(value))
+			if obj.dataSize > math.MaxUint32 {
+				return fmt.Errorf("%w: string data is too large for a single chunk", ErrInvalidFileFormat)
+			}
+
+			obj.strOffsets = append(obj.strOffsets, uint32(obj.dataSize))
+		}
+
+		if uint64(len(obj.strOffsets)) != ch.NumValues() {
+			return fmt.Errorf("%w: read %d values but expected %d", ErrLengthMismatch, len(obj.strOffsets), ch.NumValues())
+		}
+
+		obj.dataSize += 4 * uint64(len(obj.strOffsets))
+	case ch.DataType.Size() == 0:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, ch.DataType)
+	default:
+		obj.dataSize = ch.NumValues() * uint64(ch.DataType.Size())
+	}
+
+	return nil
+}
+
+func writeContiguousObject(w io.Writer, order binary.ByteOrder, obj contiguousObject) error {
+	if err := writeString(w, order, obj.path); err != nil {
+		return err
+	}
+
+	// Channels which have a data type but no values still get a raw data
+	// index so that their data type isn't lost.
+	ch := obj.channel
+	if ch == nil || ch.DataType == DataTypeVoid {
+		if err := writeUint32(w, order, rawIndexHeaderNoRawData); err != nil {
+			return err
+		}
+	} else {
+		indexLen := uint32(20)
+		if ch.DataType == DataTypeString {
+			indexLen = 28
+		}
+
+		for _, value := range []uint32{indexLen, ch.RawTypeCode(), 1} {
+			if err := writeUint32(w, order, value); err != nil {
+				return err
+			}
+		}
+
+		if err := writeUint64(w, order, ch.NumValues()); err != nil {
+			return err
+		}
+
+		if ch.DataType == DataTypeString {
+			if err := writeUint64(w, order, obj.dataSize); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeProperties(w, order, obj.properties)
+}
+
+// writeProperties writes the number of properties of an object followed by
+// each property, in name order.
+func writeProperties(w io.Writer, order binary.ByteOrder, properties map[string]Property) error {
+	if err := writeUint32(w, order, uint32(len(properties))); err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		prop := properties[name]
+
+		if err := writeString(w, order, name); err != nil {
+			return err
+		}
+		if err := writeUint32(w, order, prop.RawTypeCode()); err != nil {
+			return err
+		}
+		if err := writeValue(w, prop.TypeCode, prop.Value, order); err != nil {
+			return fmt.Errorf("failed to write property %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeContiguousData(w io.Writer, order binary.ByteOrder, obj contiguousObject) error {
+	ch := obj.channel
+
+	var numValues uint64
+	var err error
+
+	switch ch.DataType {
+	case DataTypeString:
+		numValues, err = writeContiguousStrings(w, order, obj)
+	case DataTypeInt8:
+		numValues, err = writeContiguousValues(w, ch, DataTypeInt8, interpretInt8, appendBinary[int8](order))
+	case DataTypeInt16:
+		numValues, err = writeContiguousValues(w, ch, DataTypeInt16, interpretInt16, appendBinary[int16](order))
+	case DataTypeInt32:
+		numValues, err = writeContiguousValues(w, ch, DataTypeInt32, interpretInt32, appendBinary[int32](order))
+	case DataTypeInt64:
+		numValues, err = writeContiguousValues(w, ch, DataTypeInt64, interpretInt64, appendBinary[int64](order))
+	case DataTypeUint8:
+		numValues, err = writeContiguousValues(w, ch, DataTypeUint8, interpretUint8, appendBinary[uint8](order))
+	case DataTypeUint16:
+		numValues, err = writeContiguousValues(w, ch, DataTypeUint16, interpretUint16, appendBinary[uint16](order))
+	case DataTypeUint32:
+		numValues, err = writeContiguousValues(w, ch, DataTypeUint32, interpretUint32, appendBinary[uint32](order))
+	case DataTypeUint64:
+		numValues, err = writeContiguousValues(w, ch, DataTypeUint64, interpretUint64, appendBinary[uint64](order))
+	case DataTypeFloat32:
+		numValues, err = writeContiguousValues(w, ch, DataTypeFloat32, interpretFloat32, appendBinary[float32](order))
+	case DataTypeFloat64:
+		numValues, err = writeContiguousValues(w, ch, DataTypeFloat64, interpretFloat64, appendBinary[float64](order))
+	case DataTypeComplex64:
+		numValues, err = writeContiguousValues(w, ch, DataTypeComplex64, interpretComplex64, appendBinary[complex64](order))
+	case DataTypeComplex128:
+		numValues, err = writeContiguousValues(w, ch, DataTypeComplex128, interpretComplex128, appendBinary[complex128](order))
+	case DataTypeBool:
+		numValues, err = writeContiguousValues(w, ch, DataTypeBool, interpretBool, appendBinary[bool](order))
+	case DataTypeFloat128:
+		numValues, err = writeContiguousValues(w, ch, DataTypeFloat128, interpretFloat128, func(buf []byte, batch []Float128) ([]byte, error) {
+			for _, value := range batch {
+				buf = appendFloat128(buf, value, order)
+			}
+			return buf, nil
+		})
+	case DataTypeTimestamp:
+		numValues, err = writeContiguousValues(w, ch, DataTypeTimestamp, interpretTimestamp, func(buf []byte, batch []Timestamp) ([]byte, error) {
+			for _, value := range batch {
+				buf = appendTimestamp(buf, value, order)
+			}
+			return buf, nil
+		})
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, ch.DataType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The raw data index has already been written, so the data must match it.
+	if numValues != ch.NumValues() {
+		return fmt.Errorf("%w: read %d values but expected %d", ErrLengthMismatch, numValues, ch.NumValues())
+	}
+
+	return nil
+}
+
+// writeContiguousStrings writes the offsets of the strings of a string
+// channel followed by the strings themselves, returning the number written.
+func writeContiguousStrings(w io.Writer, order binary.ByteOrder, obj contiguousObject) (uint64, error) {
+	for _, offset := range obj.strOffsets {
+		if err := writeUint32(w, order, offset); err != nil {
+			return 0, err
+		}
+	}
+
+	numValues := uint64(0)
+	for value, err := range obj.channel.ReadDataAsString() {
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := io.WriteString(w, value); err != nil {
+			return 0, err
+		}
+		numValues++
+	}
+
+	return numValues, nil
+}
+
+// writeContiguousValues writes the values of a channel with a fixed-size data
+// type, encoding each batch read from the channel at once with appendBatch.
+// Returns the number of values written.
+func writeContiguousValues[T any](
+	w io.Writer,
+	ch *Channel,
+	dataType DataType,
+	interpret interpreter[T],
+	appendBatch func([]byte, []T) ([]byte, error),
+) (uint64, error) {
+	var buf []byte
+	numValues := uint64(0)
+	for batch, err := range nativeBatchStreamReader(ch, nil, dataType, interpret) {
+		if err != nil {
+			return 0, err
+		}
+
+		buf, err = appendBatch(buf[:0], batch)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := w.Write(buf); err != nil {
+			return 0, err
+		}
+		numValues += uint64(len(batch))
+	}
+
+	return numValues, nil
+}
+
+// appendBinary returns a function which appends a batch of values of a type
+// supported by [binary.Append] in the given byte order.
+func appendBinary[T any](order binary.ByteOrder) func([]byte, []T) ([]byte, error) {
+	return func(buf []byte, batch []T) ([]byte, error) {
+		return binary.Append(buf, order, batch)
+	}
+}
@@ -0,0 +1,184 @@
+package tdms
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteContiguous(t *testing.T) {
+	f := openTestFile(t,
+		testSegment{
+			objects: []testObject{
+				{path: "/", props: []Property{{Name: "name", TypeCode: DataTypeString, Value: "test"}}},
+				{path: "/'group'", props: []Property{{Name: "gain", TypeCode: DataTypeFloat128, Value: testFloat128(1.5)}}},
+				{path: "/'group'/'a'", values: []int32{1, 2}},
+				{path: "/'group'/'b'", values: []float64{0.5, 1.5}, props: []Property{
+					{Name: "start", TypeCode: DataTypeTimestamp, Value: Timestamp{Timestamp: 1, Remainder: 2}},
+				}},
+			},
+			interleaved: true,
+			numChunks:   2,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{3}},
+				{path: "/'group'/'b'", values: []float64{2.5}},
+				{path: "/'group'/'c'", values: []string{"x", "yz"}},
+				{path: "/'group'/'d'", values: []Timestamp{{Timestamp: 3, Remainder: 4}}},
+				{path: "/'group'/'e'", values: []bool{true, false}},
+				{path: "/'group'/'f'", values: []Float128{testFloat128(0.25)}},
+				{path: "/'group'/'g'", values: []complex64{1 + 2i}},
+				{path: "/'group'/'empty'"},
+			},
+			bigEndian: true,
+		},
+		testSegment{
+			objects: []testObject{
+				{path: "/'group'/'a'", values: []int32{4}},
+				{path: "/'group'/'b'", values: []float64{3.5}},
+				{path: "/'group'/'c'", values: []string{"uvw", ""}},
+				{path: "/'group'/'d'", values: []Timestamp{{Timestamp: 5, Remainder: 6}}},
+				{path: "/'group'/'e'", values: []bool{false, true}},
+				{path: "/'group'/'f'", values: []Float128{testFloat128(-8)}},
+				{path: "/'group'/'g'", values: []complex64{-3i}},
+			},
+			noMetadata: true,
+			bigEndian:  true,
+		},
+	)
+
+	buf := &bytes.Buffer{}
+	if err := f.WriteContiguous(buf); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	written, err := New(bytes.NewReader(buf.Bytes()), false, int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read written file: %v", err)
+	}
+
+	differences, err := Compare(f, written, CompareData(0))
+	if err != nil {
+		t.Fatalf("failed to compare files: %v", err)
+	}
+	if len(differences) > 0 {
+		t.Errorf("expected written file to match, got differences %v", differences)
+	}
+
+	if len(written.segments) != 1 {
+		t.Fatalf("expected a single segment, got %d", len(written.segments))
+	}
+
+	// Each channel's data must follow straight on from the previous channel.
+	expectedOffset := written.segments[0].metadata.rawDataOffset
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		ch := testChannel(t, written, "group", name)
+
+		chunks := ch.dataChunks()
+		if len(chunks) != 1 {
+			t.Fatalf("channel %s: expected a single chunk, got %d", name, len(chunks))
+		}
+
+		chunk := chunks[0]
+		if chunk.isInterleaved || chunk.offset != expectedOffset || chunk.numValues != ch.NumValues() {
+			t.Errorf("channel %s: expected contiguous chunk at offset %d, got %+v", name, expectedOffset, chunk)
+		}
+
+		expectedOffset += int64(chunk.size)
+	}
+}
+
+func TestWriteContiguousTestData(t *testing.T) {
+	for _, filename := range []string{"testdata/standard.tdms", "testdata/big_endian.tdms"} {
+		t.Run(filename, func(t *testing.T) {
+			f, err := Open(filename)
+			if err != nil {
+				t.Fatalf("failed to open file: %v", err)
+			}
+			defer f.Close()
+
+			buf := &bytes.Buffer{}
+			if err := f.WriteContiguous(buf); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+
+			written, err := New(bytes.NewReader(buf.Bytes()), false, int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("failed to read written file: %v", err)
+			}
+
+			differences, err := Compare(f, written, CompareData(0))
+			if err != nil {
+				t.Fatalf("failed to compare files: %v", err)
+			}
+			if len(differences) > 0 {
+				t.Errorf("expected written file to match, got differences %v", differences)
+			}
+		})
+	}
+}
+
+func TestWriteContiguousEmptyChannels(t *testing.T) {
+	unit := []Property{{Name: "unit_string", TypeCode: DataTypeString, Value: "V"}}
+
+	for _, withData := range []bool{false, true} {
+		objects := []testObject{
+			{path: "/'group'"},
+			{path: "/'group'/'int32'", values: []int32{}, props: unit},
+			{path: "/'group'/'string'", values: []string{}, props: unit},
+			{path: "/'group'/'float64'", values: []float64{}, dataType: DataTypeFloat64WithUnit, props: unit},
+			{path: "/'group'/'void'", props: unit},
+		}
+		if withData {
+			objects = append(objects, testObject{path: "/'group'/'data'", values: []int32{1, 2}})
+		}
+
+		f := openTestFile(t, testSegment{objects: objects})
+
+		buf := &bytes.Buffer{}
+		if err := f.WriteContiguous(buf); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		written, err := New(bytes.NewReader(buf.Bytes()), false, int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("failed to read written file: %v", err)
+		}
+
+		differences, err := Compare(f, written, CompareData(0))
+		if err != nil {
+			t.Fatalf("failed to compare files: %v", err)
+		}
+		if len(differences) > 0 {
+			t.Errorf("expected written file to match, got differences %v", differences)
+		}
+
+		expected := map[string]DataType{
+			"int32":   DataTypeInt32,
+			"string":  DataTypeString,
+			"float64": DataTypeFloat64,
+			"void":    DataTypeVoid,
+		}
+
+		for name, dataType := range expected {
+			ch := testChannel(t, written, "group", name)
+
+			if ch.DataType != dataType || ch.NumValues() != 0 {
+				t.Errorf("channel %s: expected empty %s channel, got %d values of %s", name, dataType, ch.NumValues(), ch.DataType)
+			}
+
+			if unit, ok := ch.Unit(); !ok || unit != "V" {
+				t.Errorf("channel %s: expected unit V, got %q", name, unit)
+			}
+
+			values, err := ch.ReadDataAnyAll()
+			if err != nil || len(values) != 0 {
+				t.Errorf("channel %s: expected no values, got %v (%v)", name, values, err)
+			}
+		}
+
+		if code := testChannel(t, written, "group", "float64").RawTypeCode(); code != uint32(DataTypeFloat64WithUnit) {
+			t.Errorf("expected raw type code %#x, got %#x", DataTypeFloat64WithUnit, code)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tdms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// writeValue writes a single value of the given data type in the same format
+// that readValue reads it. The Go type of the value must be the one that
+// readValue returns for the data type.
+func writeValue(w io.Writer, dataType DataType, value any, order binary.ByteOrder) error {
+	dataType = dataType.baseType()
+
+	actualDataType, err := DataTypeOf(value)
+	if err != nil {
+		return err
+	}
+	if actualDataType != dataType {
+		return fmt.Errorf("%w: cannot write %T as %s", ErrIncorrectType, value, dataType)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128:
+		return binary.Write(w, order, v)
+	case Float128:
+		_, err := w.Write(appendFloat128(nil, v, order))
+		return err
+	case string:
+		return writeString(w, order, v)
+	case bool:
+		b := byte(0)
+		if v {
+			b = 1
+		}
+		_, err := w.Write([]byte{b})
+		return err
+	case Timestamp:
+		_, err := w.Write(appendTimestamp(nil, v, order))
+		return err
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedType, value)
+	}
+}
+
+func writeUint32(w io.Writer, order binary.ByteOrder, value uint32) error {
+	return binary.Write(w, order, value)
+}
+
+func writeUint64(w io.Writer, order binary.ByteOrder, value uint64) error {
+	return binary.Write(w, order, value)
+}
+
+func writeString(w io.Writer, order binary.ByteOrder, value string) error {
+	if err := writeUint32(w, order, uint32(len(value))); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, value)
+	return err
+}
+
+// appendFloat128 appends a [Float128] in the given byte order.
+func appendFloat128(dst []byte, f Float128, order binary.ByteOrder) []byte {
+	// Float128 is always little endian in memory.
+	if order == binary.BigEndian {
+		slices.Reverse(f[:])
+	}
+
+	return append(dst, f[:]...)
+}
+
+// appendTimestamp appends a timestamp in the given byte order, as the inverse
+// of interpretTimestamp.
+func appendTimestamp(dst []byte, t Timestamp, order binary.ByteOrder) []byte {
+	var bytes [16]byte
+	if order == binary.BigEndian {
+		order.PutUint64(bytes[:], uint64(t.Timestamp))
+		order.PutUint64(bytes[8:], t.Remainder)
+	} else {
+		order.PutUint64(bytes[:], t.Remainder)
+		order.PutUint64(bytes[8:], uint64(t.Timestamp))
+	}
+
+	return append(dst, bytes[:]...)
+}
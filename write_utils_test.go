@@ -0,0 +1,70 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestWriteValue(t *testing.T) {
+	tests := []struct {
+		dataType DataType
+		value    any
+	}{
+		{DataTypeVoid, nil},
+		{DataTypeInt8, int8(-8)},
+		{DataTypeInt16, int16(-1600)},
+		{DataTypeInt32, int32(-320000)},
+		{DataTypeInt64, int64(-1 << 40)},
+		{DataTypeUint8, uint8(8)},
+		{DataTypeUint16, uint16(1600)},
+		{DataTypeUint32, uint32(320000)},
+		{DataTypeUint64, uint64(1<<64 - 1)},
+		{DataTypeFloat32, float32(-1.5)},
+		{DataTypeFloat64, 2.25},
+		{DataTypeFloat128, testFloat128(-3.125)},
+		{DataTypeFloat32WithUnit, float32(4.5)},
+		{DataTypeFloat64WithUnit, 5.75},
+		{DataTypeFloat128WithUnit, testFloat128(6.5)},
+		{DataTypeString, "héllo"},
+		{DataTypeString, ""},
+		{DataTypeBool, true},
+		{DataTypeBool, false},
+		{DataTypeTimestamp, Timestamp{Timestamp: 3788905723, Remainder: 1265713805430620160}},
+		{DataTypeComplex64, complex64(1 - 2i)},
+		{DataTypeComplex128, complex(3.5, -4.5)},
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for _, test := range tests {
+			buf := &bytes.Buffer{}
+			if err := writeValue(buf, test.dataType, test.value, order); err != nil {
+				t.Errorf("%s %s: failed to write %v: %v", order, test.dataType, test.value, err)
+				continue
+			}
+
+			if size := test.dataType.Size(); size > 0 && buf.Len() != size {
+				t.Errorf("%s %s: expected %d bytes, got %d", order, test.dataType, size, buf.Len())
+			}
+
+			actual, err := readValue(test.dataType, buf, order)
+			if err != nil {
+				t.Errorf("%s %s: failed to read back %v: %v", order, test.dataType, test.value, err)
+				continue
+			}
+
+			if actual != test.value {
+				t.Errorf("%s %s: expected %v, got %v", order, test.dataType, test.value, actual)
+			}
+
+			if buf.Len() != 0 {
+				t.Errorf("%s %s: %d bytes left over", order, test.dataType, buf.Len())
+			}
+		}
+	}
+
+	if err := writeValue(&bytes.Buffer{}, DataTypeInt32, int64(1), binary.LittleEndian); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
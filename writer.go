@@ -0,0 +1,420 @@
+package tdms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Writer writes a TDMS file incrementally, e.g. while an acquisition is
+// running. Channels are declared with [Writer.AddChannel], values given to
+// [Writer.Write] are held in memory, and each call to [Writer.Flush] writes
+// them to the file as a new segment. The file can be opened by a reader
+// between flushes, e.g. with [File.Follow].
+//
+// The file is always little endian. A Writer must not be used by more than one
+// goroutine at a time.
+type Writer struct {
+	w io.WriteSeeker
+
+	// offset is where the next segment is written, at the end of the last
+	// segment which was flushed.
+	offset int64
+
+	// channels are the channels which have been added, in the order in which
+	// they were added.
+	channels []*writerChannel
+	byPath   map[string]*writerChannel
+
+	// written holds the paths of the root object and groups which have been
+	// written to the file.
+	written map[string]bool
+}
+
+// writerChannel is a channel added to a [Writer], along with the values which
+// haven't been flushed yet.
+type writerChannel struct {
+	path       string
+	groupName  string
+	dataType   DataType
+	properties map[string]Property
+
+	// written is set once the channel's object, with its properties, has been
+	// written to the file.
+	written bool
+
+	numValues uint64
+	data      []byte
+
+	// strOffsets are the offsets of the end of each string in data, for
+	// string channels.
+	strOffsets []uint32
+}
+
+// NewWriter returns a [Writer] which writes segments to w, starting at its
+// current position, so a Writer can also append to an existing TDMS file.
+func NewWriter(w io.WriteSeeker) (*Writer, error) {
+	offset, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:       w,
+		offset:  offset,
+		byPath:  make(map[string]*writerChannel),
+		written: make(map[string]bool),
+	}, nil
+}
+
+// AddChannel declares a channel with the given data type and properties, which
+// may be nil, in the group with the given name. The channel is written by the
+// next call to [Writer.Flush], even if no values have been written to it, so
+// that an empty channel keeps its data type and properties.
+//
+// Returns ErrUnsupportedType if values of the data type can't be written, or
+// ErrIncorrectType if the value of a property doesn't match its type code.
+func (w *Writer) AddChannel(groupName, channelName string, dataType DataType, properties map[string]Property) error {
+	if groupName == "" || channelName == "" {
+		return fmt.Errorf("group and channel names must not be empty, got %q and %q", groupName, channelName)
+	}
+
+	path := formatPath(groupName, channelName)
+	if _, ok := w.byPath[path]; ok {
+		return fmt.Errorf("channel %s has already been added", path)
+	}
+
+	if dataType != DataTypeVoid && !slices.Contains(SupportedDataTypes(), dataType.baseType()) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, dataType)
+	}
+
+	for name, prop := range properties {
+		valueType, err := DataTypeOf(prop.Value)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		if valueType != prop.TypeCode.baseType() {
+			return fmt.Errorf("%w: cannot write %T as %s for property %s", ErrIncorrectType, prop.Value, prop.TypeCode, name)
+		}
+	}
+
+	ch := &writerChannel{
+		path:       path,
+		groupName:  groupName,
+		dataType:   dataType,
+		properties: maps.Clone(properties),
+	}
+
+	w.channels = append(w.channels, ch)
+	w.byPath[path] = ch
+
+	return nil
+}
+
+// Write adds values to the channel with the given name in the group with the
+// given name, to be written by the next call to [Writer.Flush]. The values
+// must be a slice of the Go type for the channel's data type, as listed in the
+// package documentation, e.g. []float64 for a DataTypeFloat64 channel.
+//
+// Returns ErrNotFound if the channel hasn't been added, or ErrIncorrectType if
+// the values are of the wrong type.
+func (w *Writer) Write(groupName, channelName string, values any) error {
+	ch, ok := w.byPath[formatPath(groupName, channelName)]
+	if !ok {
+		return fmt.Errorf("%w: channel %s has not been added", ErrNotFound, formatPath(groupName, channelName))
+	}
+
+	order := binary.LittleEndian
+
+	switch v := values.(type) {
+	case []string:
+		return ch.writeStrings(v)
+	case []int8:
+		return writeChannelValues(ch, DataTypeInt8, v, appendBinary[int8](order))
+	case []int16:
+		return writeChannelValues(ch, DataTypeInt16, v, appendBinary[int16](order))
+	case []int32:
+		return writeChannelValues(ch, DataTypeInt32, v, appendBinary[int32](order))
+	case []int64:
+		return writeChannelValues(ch, DataTypeInt64, v, appendBinary[int64](order))
+	case []uint8:
+		return writeChannelValues(ch, DataTypeUint8, v, appendBinary[uint8](order))
+	case []uint16:
+		return writeChannelValues(ch, DataTypeUint16, v, appendBinary[uint16](order))
+	case []uint32:
+		return writeChannelValues(ch, DataTypeUint32, v, appendBinary[uint32](order))
+	case []uint64:
+		return writeChannelValues(ch, DataTypeUint64, v, appendBinary[uint64](order))
+	case []float32:
+		return writeChannelValues(ch, DataTypeFloat32, v, appendBinary[float32](order))
+	case []float64:
+		return writeChannelValues(ch, DataTypeFloat64, v, appendBinary[float64](order))
+	case []complex64:
+		return writeChannelValues(ch, DataTypeComplex64, v, appendBinary[complex64](order))
+	case []complex128:
+		return writeChannelValues(ch, DataTypeComplex128, v, appendBinary[complex128](order))
+	case []bool:
+		return writeChannelValues(ch, DataTypeBool, v, appendBinary[bool](order))
+	case []Float128:
+		return writeChannelValues(ch, DataTypeFloat128, v, func(buf []byte, batch []Float128) ([]byte, error) {
+			for _, value := range batch {
+				buf = appendFloat128(buf, value, order)
+			}
+			return buf, nil
+		})
+	case []Timestamp:
+		return writeChannelValues(ch, DataTypeTimestamp, v, func(buf []byte, batch []Timestamp) ([]byte, error) {
+			for _, value := range batch {
+				buf = appendTimestamp(buf, value, order)
+			}
+			return buf, nil
+		})
+	default:
+		return fmt.Errorf("%w: cannot write %T to channel %s", ErrIncorrectType, values, ch.path)
+	}
+}
+
+// writeChannelValues encodes values of a fixed-size data type with
+// appendBatch, adding them to the channel's unflushed data.
+func writeChannelValues[T any](
+	ch *writerChannel,
+	dataType DataType,
+	values []T,
+	appendBatch func([]byte, []T) ([]byte, error),
+) error {
+	if ch.dataType.baseType() != dataType {
+		return fmt.Errorf("%w: cannot write %s values to %s channel %s", ErrIncorrectType, dataType, ch.dataType, ch.path)
+	}
+
+	data, err := appendBatch(ch.data, values)
+	if err != nil {
+		return err
+	}
+
+	ch.data = data
+	ch.numValues += uint64(len(values))
+
+	return nil
+}
+
+// writeStrings adds strings to the unflushed data of a string channel.
+func (ch *writerChannel) writeStrings(values []string) error {
+	if ch.dataType != DataTypeString {
+		return fmt.Errorf("%w: cannot write %s values to %s channel %s", ErrIncorrectType, DataTypeString, ch.dataType, ch.path)
+	}
+
+	size := uint64(len(ch.data))
+	for _, value := range values {
+		size += uint64(len(value))
+	}
+	if size > math.MaxUint32 {
+		return fmt.Errorf("%w: string data of channel %s is too large for a single segment, flush more often", ErrInvalidFileFormat, ch.path)
+	}
+
+	for _, value := range values {
+		ch.data = append(ch.data, value...)
+		ch.strOffsets = append(ch.strOffsets, uint32(len(ch.data)))
+	}
+	ch.numValues += uint64(len(values))
+
+	return nil
+}
+
+// dataSize returns the size in bytes of the channel's unflushed raw data.
+func (ch *writerChannel) dataSize() uint64 {
+	return uint64(len(ch.data)) + 4*uint64(len(ch.strOffsets))
+}
+
+// Flush writes the values given to [Writer.Write] since the last flush, along
+// with any channels which have been added since then, to the file as a new
+// segment. Does nothing if there is nothing to write.
+//
+// The lead in of the segment is written first, marking the segment as
+// incomplete, and its length is only filled in once the rest of the segment
+// has been written. A reader which opens the file part way through a flush
+// sees the earlier segments as they were, followed by an incomplete segment,
+// rather than a segment whose data hasn't been written yet. If Flush fails,
+// the values are kept and the next call writes the segment again in the same
+// place.
+func (w *Writer) Flush() error {
+	var channels []*writerChannel
+	for _, ch := range w.channels {
+		if !ch.written || ch.numValues > 0 {
+			channels = append(channels, ch)
+		}
+	}
+
+	if len(channels) == 0 {
+		return nil
+	}
+
+	order := binary.LittleEndian
+
+	objects := &bytes.Buffer{}
+	numObjects := uint32(0)
+	var newPaths []string
+
+	// The root object and each group are written once, before their first
+	// channel.
+	writeEmptyObject := func(path string) error {
+		if w.written[path] || slices.Contains(newPaths, path) {
+			return nil
+		}
+
+		newPaths = append(newPaths, path)
+		numObjects++
+
+		if err := writeString(objects, order, path); err != nil {
+			return err
+		}
+		if err := writeUint32(objects, order, rawIndexHeaderNoRawData); err != nil {
+			return err
+		}
+		return writeUint32(objects, order, 0)
+	}
+
+	if err := writeEmptyObject(formatPath("", "")); err != nil {
+		return err
+	}
+
+	rawDataSize := uint64(0)
+	for _, ch := range channels {
+		if err := writeEmptyObject(formatPath(ch.groupName, "")); err != nil {
+			return err
+		}
+
+		if err := ch.writeObject(objects, order); err != nil {
+			return fmt.Errorf("failed to write metadata for channel %s: %w", ch.path, err)
+		}
+		numObjects++
+		rawDataSize += ch.dataSize()
+	}
+
+	// Every segment has a new object list, so that channels without new
+	// values aren't expected to have data in it.
+	toc := tocContainsMetadata | tocContainsNewObjectList
+	if rawDataSize > 0 {
+		toc |= tocContainsRawData
+	}
+
+	metadataSize := uint64(4 + objects.Len())
+
+	segment := &bytes.Buffer{}
+	segment.Write(tdmsMagicBytes)
+	for _, value := range []uint32{toc, 4713} {
+		if err := writeUint32(segment, order, value); err != nil {
+			return err
+		}
+	}
+	for _, value := range []uint64{segmentIncomplete, metadataSize} {
+		if err := writeUint64(segment, order, value); err != nil {
+			return err
+		}
+	}
+	if err := writeUint32(segment, order, numObjects); err != nil {
+		return err
+	}
+	segment.Write(objects.Bytes())
+
+	if _, err := w.w.Seek(w.offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(segment.Bytes()); err != nil {
+		return err
+	}
+
+	for _, ch := range channels {
+		if err := ch.writeData(w.w, order); err != nil {
+			return fmt.Errorf("failed to write data for channel %s: %w", ch.path, err)
+		}
+	}
+
+	// Now that the whole segment has been written, fill in the offset of the
+	// next segment, which marks it as complete.
+	if _, err := w.w.Seek(w.offset+12, io.SeekStart); err != nil {
+		return err
+	}
+	if err := writeUint64(w.w, order, metadataSize+rawDataSize); err != nil {
+		return err
+	}
+
+	w.offset += int64(leadInSize + metadataSize + rawDataSize)
+	if _, err := w.w.Seek(w.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	for _, path := range newPaths {
+		w.written[path] = true
+	}
+
+	for _, ch := range channels {
+		ch.written = true
+		ch.numValues = 0
+		ch.data = ch.data[:0]
+		ch.strOffsets = ch.strOffsets[:0]
+	}
+
+	return nil
+}
+
+// writeObject writes the metadata of the channel for the next segment. Its
+// properties are only written the first time.
+func (ch *writerChannel) writeObject(w io.Writer, order binary.ByteOrder) error {
+	if err := writeString(w, order, ch.path); err != nil {
+		return err
+	}
+
+	if ch.dataType == DataTypeVoid {
+		if err := writeUint32(w, order, rawIndexHeaderNoRawData); err != nil {
+			return err
+		}
+	} else {
+		indexLen := uint32(20)
+		if ch.dataType == DataTypeString {
+			indexLen = 28
+		}
+
+		for _, value := range []uint32{indexLen, uint32(ch.dataType), 1} {
+			if err := writeUint32(w, order, value); err != nil {
+				return err
+			}
+		}
+
+		if err := writeUint64(w, order, ch.numValues); err != nil {
+			return err
+		}
+
+		if ch.dataType == DataTypeString {
+			if err := writeUint64(w, order, ch.dataSize()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ch.written {
+		return writeUint32(w, order, 0)
+	}
+
+	return writeProperties(w, order, ch.properties)
+}
+
+// writeData writes the channel's unflushed raw data.
+func (ch *writerChannel) writeData(w io.Writer, order binary.ByteOrder) error {
+	if ch.dataType == DataTypeString {
+		offsets, err := binary.Append(nil, order, ch.strOffsets)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(offsets); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(ch.data)
+	return err
+}
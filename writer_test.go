@@ -0,0 +1,270 @@
+package tdms
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// failingWriteSeeker fails every write once writesLeft writes have been made,
+// unless writesLeft is negative.
+type failingWriteSeeker struct {
+	*os.File
+	writesLeft int
+}
+
+func (f *failingWriteSeeker) Write(p []byte) (int, error) {
+	if f.writesLeft == 0 {
+		return 0, errors.New("write failed")
+	}
+	f.writesLeft--
+	return f.File.Write(p)
+}
+
+func newTestWriter(t *testing.T) (*Writer, *failingWriteSeeker, string) {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "writer.tdms")
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	ws := &failingWriteSeeker{File: file, writesLeft: -1}
+	w, err := NewWriter(ws)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	return w, ws, filename
+}
+
+func openWrittenFile(t *testing.T, filename string) *File {
+	t.Helper()
+
+	f, err := Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open written file: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func TestWriter(t *testing.T) {
+	w, _, filename := newTestWriter(t)
+
+	unit := map[string]Property{"unit_string": {Name: "unit_string", TypeCode: DataTypeString, Value: "V"}}
+	if err := w.AddChannel("group", "a", DataTypeFloat64, unit); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if err := w.AddChannel("group", "b", DataTypeString, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+
+	if err := w.Write("group", "a", []float64{1, 2}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+	if err := w.Write("group", "b", []string{"x", "yz"}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	f := openWrittenFile(t, filename)
+	if f.IsIncomplete || f.NumSegments() != 1 {
+		t.Fatalf("expected a single complete segment, got %d (incomplete %v)", f.NumSegments(), f.IsIncomplete)
+	}
+
+	a, err := testChannel(t, f, "group", "a").ReadDataFloat64All()
+	if err != nil || !slices.Equal(a, []float64{1, 2}) {
+		t.Errorf("expected [1 2], got %v (%v)", a, err)
+	}
+	if unit, ok := testChannel(t, f, "group", "a").Unit(); !ok || unit != "V" {
+		t.Errorf("expected unit V, got %q", unit)
+	}
+
+	// A channel added later is written with the next segment, and a channel
+	// without new values is left out of it.
+	if err := w.AddChannel("other", "c", DataTypeInt32, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if err := w.Write("group", "a", []float64{3}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	// Nothing is pending, so this doesn't write another segment.
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	f = openWrittenFile(t, filename)
+	if f.IsIncomplete || f.NumSegments() != 2 {
+		t.Fatalf("expected two complete segments, got %d (incomplete %v)", f.NumSegments(), f.IsIncomplete)
+	}
+
+	a, err = testChannel(t, f, "group", "a").ReadDataFloat64All()
+	if err != nil || !slices.Equal(a, []float64{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v (%v)", a, err)
+	}
+
+	b, err := testChannel(t, f, "group", "b").ReadDataStringAll()
+	if err != nil || !slices.Equal(b, []string{"x", "yz"}) {
+		t.Errorf("expected [x yz], got %v (%v)", b, err)
+	}
+
+	c := testChannel(t, f, "other", "c")
+	if c.DataType != DataTypeInt32 || c.NumValues() != 0 {
+		t.Errorf("expected empty int32 channel, got %d values of %s", c.NumValues(), c.DataType)
+	}
+}
+
+func TestWriterFlushError(t *testing.T) {
+	w, ws, filename := newTestWriter(t)
+
+	if err := w.AddChannel("group", "a", DataTypeInt32, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if err := w.Write("group", "a", []int32{1, 2}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	if err := w.Write("group", "a", []int32{3}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+
+	// Fail after the lead in and metadata, when writing the raw data.
+	ws.writesLeft = 1
+
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected flush to fail")
+	}
+
+	f := openWrittenFile(t, filename)
+	if !f.IsIncomplete {
+		t.Error("expected file to be incomplete after a failed flush")
+	}
+
+	a, err := testChannel(t, f, "group", "a").ReadDataInt32All()
+	if err != nil || !slices.Equal(a, []int32{1, 2}) {
+		t.Errorf("expected [1 2] from the complete segment, got %v (%v)", a, err)
+	}
+
+	// Flushing again writes the segment in the same place.
+	ws.writesLeft = -1
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	f = openWrittenFile(t, filename)
+	if f.IsIncomplete || f.NumSegments() != 2 {
+		t.Fatalf("expected two complete segments, got %d (incomplete %v)", f.NumSegments(), f.IsIncomplete)
+	}
+
+	a, err = testChannel(t, f, "group", "a").ReadDataInt32All()
+	if err != nil || !slices.Equal(a, []int32{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v (%v)", a, err)
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w, _, _ := newTestWriter(t)
+
+	if err := w.AddChannel("group", "a", DataTypeInt32, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+
+	if err := w.AddChannel("group", "a", DataTypeInt32, nil); err == nil {
+		t.Error("expected an error adding a channel twice")
+	}
+
+	if err := w.AddChannel("group", "b", DataTypeFixedPoint, nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	props := map[string]Property{"gain": {Name: "gain", TypeCode: DataTypeFloat64, Value: "1.5"}}
+	if err := w.AddChannel("group", "c", DataTypeInt32, props); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+
+	if err := w.Write("group", "missing", []int32{1}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := w.Write("group", "a", []float64{1}); !errors.Is(err, ErrIncorrectType) {
+		t.Errorf("expected ErrIncorrectType, got %v", err)
+	}
+}
+
+func TestWriterEmptyChannels(t *testing.T) {
+	w, _, filename := newTestWriter(t)
+
+	unit := map[string]Property{"unit_string": {Name: "unit_string", TypeCode: DataTypeString, Value: "V"}}
+	expected := map[string]DataType{
+		"int32":   DataTypeInt32,
+		"string":  DataTypeString,
+		"float64": DataTypeFloat64WithUnit,
+		"void":    DataTypeVoid,
+	}
+
+	for name, dataType := range expected {
+		if err := w.AddChannel("group", name, dataType, unit); err != nil {
+			t.Fatalf("failed to add channel %s: %v", name, err)
+		}
+	}
+	if err := w.AddChannel("group", "nil", DataTypeInt32, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	// Empty channels stay empty once another channel has data.
+	if err := w.AddChannel("group", "data", DataTypeInt32, nil); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	if err := w.Write("group", "data", []int32{1, 2}); err != nil {
+		t.Fatalf("failed to write values: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	f := openWrittenFile(t, filename)
+	expected["nil"] = DataTypeInt32
+
+	for name, dataType := range expected {
+		ch := testChannel(t, f, "group", name)
+
+		if ch.DataType != dataType.baseType() || ch.NumValues() != 0 {
+			t.Errorf("channel %s: expected empty %s channel, got %d values of %s", name, dataType, ch.NumValues(), ch.DataType)
+		}
+
+		if unit, ok := ch.Unit(); name != "nil" && (!ok || unit != "V") {
+			t.Errorf("channel %s: expected unit V, got %q", name, unit)
+		}
+
+		values, err := ch.ReadDataAnyAll()
+		if err != nil || len(values) != 0 {
+			t.Errorf("channel %s: expected no values, got %v (%v)", name, values, err)
+		}
+	}
+
+	if len(testChannel(t, f, "group", "nil").Properties) != 0 {
+		t.Error("expected channel added with nil properties to have none")
+	}
+
+	if code := testChannel(t, f, "group", "float64").RawTypeCode(); code != uint32(DataTypeFloat64WithUnit) {
+		t.Errorf("expected raw type code %#x, got %#x", DataTypeFloat64WithUnit, code)
+	}
+}